@@ -0,0 +1,172 @@
+package gen
+
+import (
+	"go/ast"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// digests maps the algorithms accepted by
+// //hsp:digest to the package and the function
+// computing their 32 byte digest, and to their
+// crypto.Hash, passed to the signers
+var digests = map[string]struct{ pkg, fn, hash string }{
+	"sha256":     {"crypto/sha256", "sha256.Sum256", "crypto.SHA256"},
+	"sha512_256": {"crypto/sha512", "sha512.Sum512_256", "crypto.SHA512_256"},
+	"blake2b":    {"golang.org/x/crypto/blake2b", "blake2b.Sum256", "crypto.BLAKE2b_256"},
+}
+
+// DigestImport returns the quoted import path
+// of the package implementing the digest
+// algorithm, or "" if algo is unknown.
+func DigestImport(algo string) string {
+	d, ok := digests[algo]
+	if !ok {
+		return ""
+	}
+	return strconv.Quote(d.pkg)
+}
+
+func digest(w io.Writer, algos map[string]string) *digestGen {
+	return &digestGen{
+		p:     printer{w: w},
+		algos: algos,
+	}
+}
+
+// digestGen prints the Digest and DoubleDigest
+// methods of the types that have a digest
+// algorithm, on top of MarshalHash
+type digestGen struct {
+	passes
+	p     printer
+	v     string
+	algos map[string]string // type name ("" for all types) -> algorithm
+}
+
+func (d *digestGen) Method() Method { return Marshal }
+
+func (d *digestGen) setVersion(v string) {
+	d.v = v
+}
+
+func (d *digestGen) algo(typ string) string {
+	if a, ok := d.algos[typ]; ok {
+		return a
+	}
+	return d.algos[""]
+}
+
+func (d *digestGen) Execute(p Elem) error {
+	if !d.p.ok() {
+		return d.p.err
+	}
+	p = d.applyall(p)
+	if p == nil || !IsPrintable(p) {
+		return nil
+	}
+
+	// versioned types dispatch in MarshalHash,
+	// a single Digest covers all the versions
+	if d.v != "" {
+		return nil
+	}
+	algo := d.algo(BaseName(p.TypeName()))
+	if algo == "" {
+		return nil
+	}
+	fn := digests[algo].fn
+
+	c := p.Varname()
+	recv := imutMethodReceiver(p)
+
+	d.p.comment("Digest returns the " + algo + " digest of MarshalHash")
+	d.p.printf("\nfunc (%s %s) Digest() (d [32]byte, err error) {", c, recv)
+	d.p.print("\nvar o []byte")
+	d.p.printf("\nif o, err = %s.MarshalHash(); err != nil {\nreturn\n}", c)
+	d.p.printf("\nd = %s(o)", fn)
+	d.p.nakedReturn()
+
+	d.p.comment("DoubleDigest returns the " + algo + " digest of Digest")
+	d.p.printf("\nfunc (%s %s) DoubleDigest() (d [32]byte, err error) {", c, recv)
+	d.p.printf("\nif d, err = %s.Digest(); err != nil {\nreturn\n}", c)
+	d.p.printf("\nd = %s(d[:])", fn)
+	d.p.nakedReturn()
+
+	if ps, ok := p.(*Struct); ok && ps.CacheField != "" {
+		d.cache(ps, c)
+	}
+	if ps, ok := p.(*Struct); ok && ps.SignatureField != "" {
+		d.sign(ps, c, algo)
+	}
+	return d.p.err
+}
+
+// cache prints the methods using the
+// hsp.DigestCache field of s
+func (d *digestGen) cache(s *Struct, c string) {
+	recv := "*" + s.TypeName()
+	cache := c + "." + s.CacheField
+
+	d.p.comment("CachedDigest returns Digest, computed once until InvalidateHash is called")
+	d.p.printf("\nfunc (%s %s) CachedDigest() (d [32]byte, err error) {", c, recv)
+	d.p.printf("\nvar ok bool\nif d, ok = %s.Load(); ok {\nreturn\n}", cache)
+	d.p.printf("\nif d, err = %s.Digest(); err != nil {\nreturn\n}", c)
+	d.p.printf("\n%s.Store(d)", cache)
+	d.p.nakedReturn()
+
+	d.p.comment("InvalidateHash drops the digest cached by CachedDigest")
+	d.p.printf("\nfunc (%s %s) InvalidateHash() {", c, recv)
+	d.p.printf("\n%s.Invalidate()", cache)
+	d.p.closeblock()
+
+	if !s.CacheSetters {
+		return
+	}
+	for i := range s.Fields {
+		name := s.Fields[i].FieldName
+		if !ast.IsExported(name) {
+			continue
+		}
+		// flattened fields are named by their path
+		setter := "Set" + strings.Replace(name, ".", "", -1)
+		d.p.comment(setter + " sets " + name + " and invalidates the cached digest")
+		d.p.printf("\nfunc (%s %s) %s(v %s) {", c, recv, setter, s.Fields[i].FieldElem.TypeName())
+		d.p.printf("\n%s.%s = v", c, name)
+		d.p.printf("\n%s.Invalidate()", cache)
+		d.p.closeblock()
+	}
+}
+
+// sign prints the methods signing the
+// digest of s, see sign.go in the hsp
+// package
+func (d *digestGen) sign(s *Struct, c string, algo string) {
+	recv := "*" + s.TypeName()
+	sig := c + "." + s.SignatureField
+	signer := c + "." + s.SignerField
+	digest := "Digest"
+	if s.CacheField != "" {
+		digest = "CachedDigest"
+	}
+
+	d.p.comment("SignHash sets " + s.SignerField + " to the public key of priv, and " +
+		s.SignatureField + " to its signature of " + digest)
+	d.p.printf("\nfunc (%s %s) SignHash(priv crypto.Signer) (err error) {", c, recv)
+	d.p.print("\nvar ok bool")
+	d.p.printf("\nif %s, ok = priv.Public().(%s); !ok {", signer, s.SignerType)
+	d.p.printf("\nreturn herr.New(%q)\n}", "hsp: the public key of priv is not of type "+s.SignerType)
+	d.p.print("\nvar d [32]byte")
+	d.p.printf("\nif d, err = %s.%s(); err != nil {\nreturn\n}", c, digest)
+	d.p.printf("\n%s, err = hsp.SignDigest(priv, d[:], %s)", sig, digests[algo].hash)
+	d.p.nakedReturn()
+
+	d.p.comment("VerifyHash returns an error unless " + s.SignatureField +
+		" is the signature of " + digest + " by " + s.SignerField)
+	d.p.printf("\nfunc (%s %s) VerifyHash() (err error) {", c, recv)
+	d.p.print("\nvar d [32]byte")
+	d.p.printf("\nif d, err = %s.%s(); err != nil {\nreturn\n}", c, digest)
+	d.p.printf("\nreturn hsp.VerifyDigest(%s, d[:], %s, %s)", signer, sig, digests[algo].hash)
+	d.p.closeblock()
+}
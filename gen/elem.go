@@ -233,11 +233,12 @@ func (a *Array) Copy() Elem {
 
 func (a *Array) Complexity() int { return 1 + a.Els.Complexity() }
 
-// Map is a map[string]Elem
+// Map is a map[Key]Elem
 type Map struct {
 	common
 	Keyidx string // key variable name
 	Validx string // value variable name
+	Key    Elem   // key element
 	Value  Elem   // value element
 }
 
@@ -252,6 +253,7 @@ ridx:
 		goto ridx
 	}
 
+	m.Key.SetVarname(m.Keyidx)
 	m.Value.SetVarname(m.Validx)
 }
 
@@ -259,22 +261,24 @@ func (m *Map) TypeName() string {
 	if m.common.alias != "" {
 		return m.common.alias
 	}
-	m.common.Alias("map[string]" + m.Value.TypeName())
+	m.common.Alias("map[" + m.Key.TypeName() + "]" + m.Value.TypeName())
 	return m.common.alias
 }
 
 func (m *Map) Copy() Elem {
 	g := *m
+	g.Key = m.Key.Copy()
 	g.Value = m.Value.Copy()
 	return &g
 }
 
-func (m *Map) Complexity() int { return 2 + m.Value.Complexity() }
+func (m *Map) Complexity() int { return 1 + m.Key.Complexity() + m.Value.Complexity() }
 
 type Slice struct {
 	common
-	Index string
-	Els   Elem // The type of each element
+	Index  string
+	Els    Elem   // The type of each element
+	Merkle string // digest algorithm of the Merkle tree written instead, see merkle.go
 }
 
 func (s *Slice) SetVarname(a string) {
@@ -323,9 +327,12 @@ func (s *Ptr) SetVarname(a string) {
 		return
 
 	case *BaseElem:
-		// identities have pointer receivers
-		if x.Value == IDENT {
+		// identities have pointer receivers,
+		// pointers to type parameters have none
+		if x.Value == IDENT && !x.TypeParam {
 			x.SetVarname(a)
+		} else if x.TypeParam {
+			x.SetVarname("(*" + a + ")")
 		} else {
 			x.SetVarname("*" + a)
 		}
@@ -368,21 +375,38 @@ type Struct struct {
 	Versioning            bool          // generate versioned marshal hash
 	OldMarshalBody        string        // old version hsp, marshal method body
 	OldMsgSizeBody        string        // old version hsp, msgsize method body
+	OldAppendBody         string        // old version hsp, append method body (inline nesting only)
 	VersionList           []string      // version map
 	CurrentVersion        string        // current version hash
 	CurrentNumericVersion int           // current numeric version
+	CacheField            string        // hsp.DigestCache field, see CachedDigest
+	CacheSetters          bool          // generate setters invalidating the cache
+	Domain                string        // first object counted by the header, see domain.go
+	SignatureField        string        // []byte field set by SignHash, not hashed
+	SignerField           string        // public key field set by SignHash, not hashed
+	SignerType            string        // type of the SignerField
 }
 
 func (s *Struct) ComputeVersion() {
 	var fieldHashes []string
 
 	for i := range s.Fields {
-		fieldHashes = append(fieldHashes, s.Fields[i].FieldName+":"+
-			s.Fields[i].FieldElem.TypeName()+":"+
-			s.Fields[i].FieldTag)
+		fh := s.Fields[i].FieldName + ":" +
+			s.Fields[i].FieldElem.TypeName() + ":" +
+			s.Fields[i].FieldTag
+		if s.Fields[i].OmitEmpty {
+			fh += ",omitempty"
+		}
+		if sl, ok := s.Fields[i].FieldElem.(*Slice); ok && sl.Merkle != "" {
+			fh += ",merkle"
+		}
+		fieldHashes = append(fieldHashes, fh)
 	}
 
 	sort.Strings(fieldHashes)
+	if s.Domain != "" {
+		fieldHashes = append(fieldHashes, "domain:"+s.Domain)
+	}
 
 	h := sha256.Sum256([]byte(strings.Join(fieldHashes, "|")))
 	hs := hex.EncodeToString(h[:])
@@ -433,6 +457,7 @@ type StructField struct {
 	FieldName    string // the name of the struct field
 	FieldElem    Elem   // the field type
 	VersionField bool   // the field represents the field
+	OmitEmpty    bool   // the field is left out when empty, see CanOmitEmpty
 }
 
 // Len returns the length of the uints array.
@@ -474,6 +499,10 @@ type BaseElem struct {
 	ShimFromBase string    // shim from base type, or empty
 	Value        Primitive // Type of element
 	Convert      bool      // should we do an explicit conversion?
+	Local        bool      // IDENT declared in the parsed files, generated alongside
+	TypeParam    bool      // IDENT is a type parameter of the generic type, see generic.go
+	Float        FloatMode // encoding of floats, see float.go
+	Time         TimeMode  // encoding of times, see time.go
 	mustinline   bool      // must inline; not printable
 	needsref     bool      // needs reference for shim
 }
@@ -543,6 +572,13 @@ func (s *BaseElem) BaseName() string {
 	return s.Value.String()
 }
 
+// encName returns the name of the hsp functions
+// and sizes writing the element, which depends on
+// its float and time modes, e.g. Float64Canonical
+func (s *BaseElem) encName() string {
+	return s.BaseName() + s.Float.String() + s.Time.String()
+}
+
 func (s *BaseElem) BaseType() string {
 	switch s.Value {
 	case IDENT:
@@ -0,0 +1,49 @@
+package gen
+
+import (
+	"io"
+)
+
+func encode(w io.Writer) *encodeGen {
+	return &encodeGen{p: printer{w: w}}
+}
+
+// encodeGen prints EncodeHash, which writes the
+// output of MarshalHash to an hsp.Writer, see
+// hsp.EncodeHash, on top of WriteHash: a value is
+// then streamed to a file or a socket without
+// holding its encoding in memory.
+type encodeGen struct {
+	passes
+	p printer
+	v string
+}
+
+func (e *encodeGen) Method() Method { return Encode }
+
+func (e *encodeGen) setVersion(v string) {
+	e.v = v
+}
+
+func (e *encodeGen) Execute(p Elem) error {
+	if !e.p.ok() {
+		return e.p.err
+	}
+	p = e.applyall(p)
+	if p == nil || !IsPrintable(p) {
+		return nil
+	}
+
+	// versioned types dispatch in WriteHash,
+	// a single EncodeHash covers all the versions
+	if e.v != "" {
+		return nil
+	}
+
+	c := p.Varname()
+	e.p.comment("EncodeHash writes the output of MarshalHash to w, flushed by the caller")
+	e.p.printf("\nfunc (%s %s) EncodeHash(w *hsp.Writer) error {", c, imutMethodReceiver(p))
+	e.p.printf("\nreturn %s.WriteHash(w)", c)
+	e.p.closeblock()
+	return e.p.err
+}
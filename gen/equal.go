@@ -0,0 +1,278 @@
+package gen
+
+import (
+	"io"
+	"strings"
+	"unicode"
+)
+
+func equal(w io.Writer) *equalGen {
+	return &equalGen{
+		p: printer{w: w},
+	}
+}
+
+// equalGen generates EqualHash, which compares
+// two values the way their MarshalHash outputs
+// compare, without serializing them.
+//
+// The tree is walked with the variable names of
+// the receiver; the names of the other value are
+// derived by replacing their root identifier, see
+// other.
+type equalGen struct {
+	passes
+	p   printer
+	v   string
+	alt map[string]string // root identifier -> other root identifier
+}
+
+func (e *equalGen) Method() Method { return Equal }
+
+func (e *equalGen) setVersion(v string) {
+	e.v = v
+}
+
+func (e *equalGen) Execute(p Elem) error {
+	if !e.p.ok() {
+		return e.p.err
+	}
+	p = e.applyall(p)
+	if p == nil {
+		return nil
+	}
+
+	if !IsPrintable(p) {
+		return nil
+	}
+
+	// save the vname before calling
+	// methodReceiver, which may alter it
+	c := p.Varname()
+	recv := methodReceiver(p)
+	e.alt = map[string]string{c: "other"}
+
+	e.p.comment("EqualHash" + e.v + " reports whether MarshalHash" + e.v + " of z and other are equal")
+	e.p.printf("\nfunc (%s %s) EqualHash%s(other %s) bool {", c, recv, e.v, recv)
+	e.p.printf("\nif %s == nil || other == nil {\nreturn %s == other\n}", c, c)
+
+	ps, versioned := p.(*Struct)
+	versioned = versioned && ps.Versioning && e.v == ""
+	switch {
+	case e.v == "oldver":
+		// the old version body is only
+		// known as a MarshalHash method
+		e.p.printf("\nzb, err := %s.MarshalHasholdver()", c)
+		e.differ("err != nil")
+		e.p.print("\nob, err := other.MarshalHasholdver()")
+		e.differ("err != nil")
+		e.p.print("\nreturn bytes.Equal(zb, ob)\n}\n")
+	case versioned:
+		// both sides use the same version,
+		// or their encodings are compared
+		e.p.printf("\nif %s.HSPCurrentVersion() != other.HSPCurrentVersion() {", c)
+		e.p.printf("\nreturn hsp.EqualHash(%s, other)\n}", c)
+		e.p.printf("\nswitch %s.HSPCurrentVersion() {", c)
+		for i := range ps.VersionList {
+			e.p.printf("\ncase %d:", i)
+			e.p.printf("\nreturn %s.EqualHash%s(other)", c, ps.VersionList[i])
+		}
+		e.p.print("\n}")
+		e.p.print("\nreturn false\n}\n")
+	default:
+		next(e, p)
+		e.p.print("\nreturn true\n}\n")
+	}
+	unsetReceiver(p)
+	return e.p.err
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// other returns the name of the
+// variable of the other value
+// matching the variable name
+func (e *equalGen) other(name string) string {
+	i := strings.IndexFunc(name, isIdentRune)
+	if i < 0 {
+		return name
+	}
+	j := strings.IndexFunc(name[i:], func(r rune) bool { return !isIdentRune(r) })
+	if j < 0 {
+		j = len(name)
+	} else {
+		j += i
+	}
+	if alt, ok := e.alt[name[i:j]]; ok {
+		return name[:i] + alt + name[j:]
+	}
+	return name
+}
+
+func (e *equalGen) differ(cond string) {
+	e.p.printf("\nif %s {\nreturn false\n}", cond)
+}
+
+func (e *equalGen) gStruct(s *Struct) {
+	if !e.p.ok() {
+		return
+	}
+	for i := range s.Fields {
+		if !e.p.ok() {
+			return
+		}
+		f := &s.Fields[i]
+		if !f.OmitEmpty {
+			next(e, f.FieldElem)
+			continue
+		}
+		// empty values are left out, and
+		// equal whatever they are, e.g. -0 and 0
+		vname := f.FieldElem.Varname()
+		set := nonEmpty(f.FieldElem, vname)
+		e.differ("(" + set + ") != (" + nonEmpty(f.FieldElem, e.other(vname)) + ")")
+		e.p.printf("\nif %s {", set)
+		next(e, f.FieldElem)
+		e.p.closeblock()
+	}
+}
+
+func (e *equalGen) gSlice(s *Slice) {
+	if !e.p.ok() {
+		return
+	}
+	// nil and empty slices have
+	// the same encoding
+	vname := s.Varname()
+	e.differ("len(" + vname + ") != len(" + e.other(vname) + ")")
+	e.p.rangeBlock(s.Index, vname, e, s.Els)
+}
+
+func (e *equalGen) gArray(a *Array) {
+	if !e.p.ok() {
+		return
+	}
+	vname := a.Varname()
+	if be, ok := a.Els.(*BaseElem); ok && be.Value == Byte {
+		e.differ(vname + " != " + e.other(vname))
+		return
+	}
+	e.p.rangeBlock(a.Index, vname, e, a.Els)
+}
+
+func (e *equalGen) gMap(m *Map) {
+	if !e.p.ok() {
+		return
+	}
+	// the keys are sorted by MarshalHash,
+	// so a lookup by key is the same
+	vname := m.Varname()
+	ovname := e.other(vname)
+	oval := randIdent()
+	e.alt[m.Validx] = oval
+	e.differ("len(" + vname + ") != len(" + ovname + ")")
+	e.p.printf("\nfor %s, %s := range %s {", m.Keyidx, m.Validx, vname)
+	e.p.printf("\n%s, ok := %s[%s]", oval, ovname, m.Keyidx)
+	e.differ("!ok")
+	next(e, m.Value)
+	e.p.closeblock()
+}
+
+func (e *equalGen) gPtr(p *Ptr) {
+	if !e.p.ok() {
+		return
+	}
+	vname := p.Varname()
+	ovname := e.other(vname)
+	e.differ("(" + vname + " == nil) != (" + ovname + " == nil)")
+	e.p.printf("\nif %s != nil {", vname)
+	if be, ok := p.Value.(*BaseElem); ok && be.Value == IDENT && !be.Convert && !be.TypeParam {
+		// identities keep the name of the pointer
+		e.ident(be, vname, ovname, "")
+	} else {
+		next(e, p.Value)
+	}
+	e.p.closeblock()
+}
+
+// ident compares two values a and b of a type
+// with a MarshalHash method; ref is the operator
+// taking the address of b
+func (e *equalGen) ident(be *BaseElem, a, b string, ref string) {
+	if be.TypeParam {
+		// may be nil pointers, see marshalGen
+		e.differ("!hsp.EqualIntf(" + a + ", " + b + ")")
+		return
+	}
+	if be.Local {
+		// generated alongside
+		e.differ("!" + a + ".EqualHash(" + ref + b + ")")
+		return
+	}
+	e.differ("!hsp.EqualHash(" + ref + a + ", " + ref + b + ")")
+}
+
+func (e *equalGen) gBase(b *BaseElem) {
+	if !e.p.ok() {
+		return
+	}
+	a, o := b.Varname(), e.other(b.Varname())
+
+	if b.Convert {
+		if b.ShimMode == Cast {
+			a, o = b.ToBase()+"("+a+")", b.ToBase()+"("+o+")"
+		} else {
+			ca, co := randIdent(), randIdent()
+			e.p.printf("\n%s, err := %s(%s)", ca, b.ToBase(), a)
+			e.differ("err != nil")
+			e.p.printf("\n%s, err := %s(%s)", co, b.ToBase(), o)
+			e.differ("err != nil")
+			a, o = ca, co
+		}
+	}
+
+	if b.Float != RawFloat {
+		// all NaNs are equal, as are -0 and
+		// +0, see hsp.AppendFloat64Canonical
+		if b.Value == Float32 {
+			a, o = "float64("+a+")", "float64("+o+")"
+		}
+		e.differ("!hsp.EqualFloatCanonical(" + a + ", " + o + ")")
+		return
+	}
+
+	switch b.Value {
+	case IDENT:
+		e.ident(b, a, o, "&")
+	case Bytes:
+		e.differ("!bytes.Equal(" + a + ", " + o + ")")
+	case Float32:
+		// bitwise, as encoded
+		e.differ("math.Float32bits(" + a + ") != math.Float32bits(" + o + ")")
+	case Float64:
+		e.differ("math.Float64bits(" + a + ") != math.Float64bits(" + o + ")")
+	case Complex64:
+		e.differ("math.Float32bits(real(" + a + ")) != math.Float32bits(real(" + o + ")) || " +
+			"math.Float32bits(imag(" + a + ")) != math.Float32bits(imag(" + o + "))")
+	case Complex128:
+		e.differ("math.Float64bits(real(" + a + ")) != math.Float64bits(real(" + o + ")) || " +
+			"math.Float64bits(imag(" + a + ")) != math.Float64bits(imag(" + o + "))")
+	case Time:
+		if b.Time != TimeExt {
+			// truncated to the precision encoded
+			e.differ("!hsp.EqualTimeAs(" + a + ", " + o + ", hsp.Append" + b.encName() + ")")
+			break
+		}
+		// the encoding keeps the instant, in
+		// nanoseconds, but not the location
+		e.differ("!(" + a + ").Equal(" + o + ")")
+	case Intf:
+		e.differ("!hsp.EqualIntf(" + a + ", " + o + ")")
+	case Ext:
+		e.differ("!hsp.EqualExtension(" + a + ", " + o + ")")
+	default:
+		e.differ(a + " != " + o)
+	}
+}
@@ -0,0 +1,70 @@
+package gen
+
+// The floats are written with their IEEE 754 bits,
+// so that the NaNs with different payloads, or -0
+// and +0, have different hashes. The canonical mode,
+// set by the //hsp:float directive or the canonical
+// tag option, collapses the NaNs to a single quiet
+// NaN, maps -0 to +0, and widens the float32s to
+// float64s, so that changing the type of a field
+// doesn't change the hashes. The integral mode also
+// writes the whole numbers as integers.
+//
+// Complex numbers and the floats held by interfaces
+// are not affected.
+
+// FloatMode is the encoding of
+// the float32 and float64 elements.
+type FloatMode uint8
+
+const (
+	RawFloat       FloatMode = iota // IEEE 754 bits
+	CanonicalFloat                  // see hsp.AppendFloat64Canonical
+	IntegralFloat                   // see hsp.AppendFloat64Integral
+)
+
+// String returns the suffix of the
+// hsp functions writing the mode
+func (m FloatMode) String() string {
+	switch m {
+	case CanonicalFloat:
+		return "Canonical"
+	case IntegralFloat:
+		return "Integral"
+	}
+	return ""
+}
+
+// SetFloatMode sets the mode of the floats of e, including
+// the ones of its fields, elements and pointers, but not of
+// the named types, and returns whether e holds any. Floats
+// whose mode is already set, by a tag, are left unchanged.
+func SetFloatMode(e Elem, m FloatMode) bool {
+	switch e := e.(type) {
+	case *BaseElem:
+		if e.Value != Float32 && e.Value != Float64 {
+			return false
+		}
+		if e.Float == RawFloat {
+			e.Float = m
+		}
+		return true
+	case *Ptr:
+		return SetFloatMode(e.Value, m)
+	case *Slice:
+		return SetFloatMode(e.Els, m)
+	case *Array:
+		return SetFloatMode(e.Els, m)
+	case *Map:
+		return SetFloatMode(e.Value, m)
+	case *Struct:
+		found := false
+		for i := range e.Fields {
+			if SetFloatMode(e.Fields[i].FieldElem, m) {
+				found = true
+			}
+		}
+		return found
+	}
+	return false
+}
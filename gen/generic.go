@@ -0,0 +1,33 @@
+package gen
+
+import "strings"
+
+// Generic types are aliased with their type
+// parameters, e.g. Page[T], so that the methods
+// declare their receiver as *Page[T]. Their type
+// parameters are IDENTs marked TypeParam, which are
+// only known to implement hsp.HashMarshaler: they
+// are written by hsp.AppendIntfHash, nested as the
+// types of other packages unless they are nil
+// pointers, and their size and decoding go through
+// hsp.HashSize and hsp.ReadHashMarshalerBytes.
+//
+// The tests are not generated for generic types,
+// which can't be instantiated without knowing the
+// types implementing the constraint.
+
+// isGeneric returns whether the
+// named type e has type parameters
+func isGeneric(e Elem) bool {
+	return strings.HasSuffix(e.TypeName(), "]")
+}
+
+// BaseName returns the name of the type
+// typ without its type parameters or
+// arguments, e.g. Page for Page[T]
+func BaseName(typ string) string {
+	if i := strings.IndexByte(typ, '['); i > 0 {
+		return typ[:i]
+	}
+	return typ
+}
@@ -0,0 +1,121 @@
+package gen
+
+import "fmt"
+
+// KeyOrder is the canonical rule used to
+// sort the keys of a map before they are
+// written, so that a map always hashes to
+// the same bytes regardless of Go's
+// randomized map iteration order.
+//
+// The rules are:
+//
+//   - string keys (and the named strings declared
+//     alongside) are sorted byte-wise, as
+//     sort.Strings does
+//   - signed and unsigned integer keys (and named
+//     integers) are sorted by numeric value
+//   - byte array keys, e.g. [32]byte, are sorted
+//     byte-wise, as bytes.Compare does
+//   - keys of any other named type, e.g. proto.NodeID
+//     declared in another package, are sorted by their
+//     MarshalHash output, as bytes.Compare does, even
+//     when it is a string: with the length prefix of
+//     the encoding, the shorter strings may then come
+//     first. Their underlying type is only known with
+//     -typecheck, which must not change any hash.
+//
+// Any other key type (floats, bools, time.Time,
+// structs, interfaces...) is rejected.
+type KeyOrder uint8
+
+const (
+	InvalidKeyOrder KeyOrder = iota
+	StringKeyOrder           // byte-wise order of strings
+	NumericKeyOrder          // numeric order of integers
+	BytesKeyOrder            // byte-wise order of byte arrays
+	EncodedKeyOrder          // byte-wise order of MarshalHash output
+)
+
+// MapKeyOrder returns the canonical sort
+// rule for a map key element, or InvalidKeyOrder
+// if the element cannot be used as a map key.
+func MapKeyOrder(e Elem) KeyOrder {
+	switch e := e.(type) {
+	case *BaseElem:
+		if e.Convert && e.ShimMode == Convert {
+			// converting shims may fail
+			// and can't be used in a comparison
+			return InvalidKeyOrder
+		}
+		switch e.Value {
+		case String:
+			return StringKeyOrder
+		case Int, Int8, Int16, Int32, Int64,
+			Uint, Uint8, Uint16, Uint32, Uint64, Byte:
+			return NumericKeyOrder
+		case IDENT:
+			return EncodedKeyOrder
+		}
+	case *Array:
+		if be, ok := e.Els.(*BaseElem); ok && !be.Convert && (be.Value == Byte || be.Value == Uint8) {
+			return BytesKeyOrder
+		}
+	}
+	return InvalidKeyOrder
+}
+
+// lessExpr returns the expression used by
+// sort.Slice to compare two keys of the
+// slice named 'slice' at indexes i and j.
+func lessExpr(o KeyOrder, key Elem, slice string) string {
+	a, b := slice+"[i]", slice+"[j]"
+	switch o {
+	case StringKeyOrder, NumericKeyOrder:
+		if be := key.(*BaseElem); be.Convert {
+			a, b = be.ToBase()+"("+a+")", be.ToBase()+"("+b+")"
+		}
+		return fmt.Sprintf("%s < %s", a, b)
+	case BytesKeyOrder:
+		return fmt.Sprintf("bytes.Compare(%s[:], %s[:]) < 0", a, b)
+	case EncodedKeyOrder:
+		return fmt.Sprintf("bytes.Compare(%s.enc, %s.enc) < 0", a, b)
+	}
+	panic("invalid map key order")
+}
+
+// sortedKeys opens a loop over the entries of
+// map s in canonical key order, declaring the
+// key and value variables of s; the caller
+// writes both and closes the block.
+func (p *printer) sortedKeys(s *Map, order KeyOrder) {
+	vname := s.Varname()
+	p.printf("\n%sSlice := make([]%s, 0, len(%s))", s.Keyidx, s.Key.TypeName(), vname)
+	p.printf("\nfor i := range %s {\n%sSlice = append(%sSlice, i)\n}",
+		vname, s.Keyidx, s.Keyidx)
+	if be, ok := s.Key.(*BaseElem); ok && be.Value == String && !be.Convert {
+		p.printf("\nsort.Strings(%sSlice)", s.Keyidx)
+	} else {
+		p.printf("\nsort.Slice(%[1]sSlice, func(i, j int) bool { return %[2]s })",
+			s.Keyidx, lessExpr(order, s.Key, s.Keyidx+"Slice"))
+	}
+	p.printf("\nfor _, %s := range %sSlice {\n %s := %s[%s]", s.Keyidx, s.Keyidx, s.Validx, vname, s.Keyidx)
+}
+
+// encodedKeys is sortedKeys for EncodedKeyOrder:
+// each key is marshaled once and sorted by its
+// encoding. The loop declares the value variable
+// of s and <Keyidx>Entry, whose enc field holds
+// the key encoding to be written by the caller.
+func (p *printer) encodedKeys(s *Map) {
+	vname := s.Varname()
+	p.printf("\n%sSlice := make([]struct {\nkey %s\nenc []byte\n}, 0, len(%s))", s.Keyidx, s.Key.TypeName(), vname)
+	p.printf("\nfor %s := range %s {", s.Keyidx, vname)
+	p.printf("\nvar enc []byte")
+	p.printf("\nif enc, err = %s.MarshalHash(); err != nil {\nreturn\n}", s.Key.Varname())
+	p.printf("\n%[1]sSlice = append(%[1]sSlice, struct {\nkey %[2]s\nenc []byte\n}{%[1]s, enc})", s.Keyidx, s.Key.TypeName())
+	p.closeblock()
+	p.printf("\nsort.Slice(%[1]sSlice, func(i, j int) bool { return %[2]s })",
+		s.Keyidx, lessExpr(EncodedKeyOrder, s.Key, s.Keyidx+"Slice"))
+	p.printf("\nfor _, %[1]sEntry := range %[1]sSlice {\n %[2]s := %[3]s[%[1]sEntry.key]", s.Keyidx, s.Validx, vname)
+}
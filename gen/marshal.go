@@ -8,17 +8,19 @@ import (
 	"github.com/CovenantSQL/HashStablePack/marshalhash"
 )
 
-func marshal(w io.Writer) *marshalGen {
+func marshal(w io.Writer, inline bool) *marshalGen {
 	return &marshalGen{
-		p: printer{w: w},
+		p:      printer{w: w},
+		inline: inline,
 	}
 }
 
 type marshalGen struct {
 	passes
-	p    printer
-	fuse []byte
-	v    string
+	p      printer
+	fuse   []byte
+	v      string
+	inline bool // append nested types in place, see AppendHash
 }
 
 func (m *marshalGen) Method() Method { return Marshal }
@@ -80,36 +82,91 @@ func (m *marshalGen) Execute(p Elem) error {
 		m.p.print("\n}")
 	}
 
+	if m.v == "oldver" {
+		m.oldVersion(p.(*Struct), c)
+		return m.p.err
+	}
+
+	versioned := false
+	if ps, ok := p.(*Struct); ok && ps.Versioning && m.v == "" {
+		versioned = true
+	}
+
 	m.p.comment("MarshalHash" + m.v + " marshals for hash")
-	m.p.printf("\nfunc (%s %s) MarshalHash%s() (o []byte, err error) ", c, imutMethodReceiver(p), m.v)
-	if m.v != "oldver" {
-		m.p.printf("{")
-
-		if ps, ok := p.(*Struct); ok && ps.Versioning && m.v == "" {
-			// version enabled and print switch statements
-			m.p.printf("\nswitch %s.HSPCurrentVersion() {", c)
-			for i := range ps.VersionList {
-				m.p.printf("\ncase %d:", i)
-				m.p.printf("\nreturn %s.MarshalHash%s()", c, ps.VersionList[i])
-			}
-			m.p.print("\ndefault:")
-			m.p.print("\nerr = herr.New(\"invalid struct version\")")
-			m.p.print("\nreturn")
-			m.p.print("\n}")
-			m.p.nakedReturn()
+	m.p.printf("\nfunc (%s %s) MarshalHash%s() (o []byte, err error) {", c, imutMethodReceiver(p), m.v)
+	if versioned {
+		// version enabled and print switch statements
+		m.versionSwitch(p.(*Struct), c, "MarshalHash", "")
+	} else if m.inline {
+		m.p.printf("\nvar b []byte")
+		m.p.printf("\nreturn %s.AppendHash%s(hsp.Require(b, %s.Msgsize%s()))\n}\n", c, m.v, c, m.v)
+	} else {
+		m.p.printf("\nvar b []byte")
+		m.p.printf("\no = hsp.Require(b, %s.Msgsize%s())", c, m.v)
+		next(m, p)
+		m.p.nakedReturn()
+	}
+
+	if m.inline {
+		m.p.comment("AppendHash" + m.v + " appends the hash encoding to b")
+		m.p.printf("\nfunc (%s %s) AppendHash%s(b []byte) (o []byte, err error) {", c, imutMethodReceiver(p), m.v)
+		if versioned {
+			m.versionSwitch(p.(*Struct), c, "AppendHash", "b")
 		} else {
-			m.p.printf("\nvar b []byte")
-			m.p.printf("\no = hsp.Require(b, %s.Msgsize%s())", c, m.v)
+			m.p.print("\no = b")
 			next(m, p)
 			m.p.nakedReturn()
 		}
-	} else {
-		m.p.print(p.(*Struct).OldMarshalBody)
 	}
 
 	return m.p.err
 }
 
+// versionSwitch prints the body of a method
+// dispatching to its per version variants
+func (m *marshalGen) versionSwitch(s *Struct, c string, method string, args string) {
+	m.p.printf("\nswitch %s.HSPCurrentVersion() {", c)
+	for i := range s.VersionList {
+		m.p.printf("\ncase %d:", i)
+		m.p.printf("\nreturn %s.%s%s(%s)", c, method, s.VersionList[i], args)
+	}
+	m.p.print("\ndefault:")
+	m.p.print("\nerr = herr.New(\"invalid struct version\")")
+	m.p.print("\nreturn")
+	// every case returns
+	m.p.print("\n}\n}\n")
+}
+
+// oldVersion prints the methods of a struct
+// version generated before versioning was enabled,
+// using the method bodies of the old generated file.
+func (m *marshalGen) oldVersion(s *Struct, c string) {
+	recv := imutMethodReceiver(s)
+	if s.OldAppendBody != "" {
+		// the old file was generated with inline nesting,
+		// so its MarshalHash body only calls AppendHash
+		m.p.comment("MarshalHasholdver marshals for hash")
+		m.p.printf("\nfunc (%s %s) MarshalHasholdver() (o []byte, err error) {", c, recv)
+		m.p.printf("\nvar b []byte")
+		m.p.printf("\nreturn %s.AppendHasholdver(hsp.Require(b, %s.Msgsizeoldver()))\n}\n", c, c)
+		m.p.comment("AppendHasholdver appends the hash encoding to b")
+		m.p.printf("\nfunc (%s %s) AppendHasholdver(b []byte) (o []byte, err error) ", c, recv)
+		m.p.print(s.OldAppendBody)
+		return
+	}
+
+	m.p.comment("MarshalHasholdver marshals for hash")
+	m.p.printf("\nfunc (%s %s) MarshalHasholdver() (o []byte, err error) ", c, recv)
+	m.p.print(s.OldMarshalBody)
+	if m.inline {
+		m.p.comment("AppendHasholdver appends the hash encoding to b")
+		m.p.printf("\nfunc (%s %s) AppendHasholdver(b []byte) (o []byte, err error) {", c, recv)
+		m.p.printf("\nif o, err = %s.MarshalHasholdver(); err != nil {\nreturn\n}", c)
+		m.p.print("\no = append(b, o...)")
+		m.p.nakedReturn()
+	}
+}
+
 func (m *marshalGen) rawAppend(typ string, argfmt string, arg interface{}) {
 	m.p.printf("\no = hsp.Append%s(o, %s)", typ, fmt.Sprintf(argfmt, arg))
 }
@@ -143,11 +200,17 @@ func (m *marshalGen) gStruct(s *Struct) {
 }
 
 func (m *marshalGen) tuple(s *Struct) {
+	if s.omitted() > 0 {
+		m.omitempty(s, arrayHeader)
+		return
+	}
 	data := make([]byte, 0, 5)
-	data = marshalhash.AppendArrayHeader(data, uint32(len(s.Fields)))
-	m.p.printf("\n// array header, size %d", len(s.Fields))
+	n := s.fixedFields()
+	data = marshalhash.AppendArrayHeader(data, uint32(n))
+	m.p.printf("\n// array header, size %d", n)
 	m.Fuse(data)
-	if len(s.Fields) == 0 {
+	m.domain(s)
+	if n == 0 {
 		m.fuseHook()
 	}
 	for i := range s.Fields {
@@ -159,11 +222,17 @@ func (m *marshalGen) tuple(s *Struct) {
 }
 
 func (m *marshalGen) mapstruct(s *Struct) {
+	if s.omitted() > 0 {
+		m.omitempty(s, mapHeader)
+		return
+	}
 	data := make([]byte, 0, 64)
-	data = marshalhash.AppendMapHeader(data, uint32(len(s.Fields)))
-	m.p.printf("\n// map header, size %d", len(s.Fields))
+	n := s.fixedFields()
+	data = marshalhash.AppendMapHeader(data, uint32(n))
+	m.p.printf("\n// map header, size %d", n)
 	m.Fuse(data)
-	if len(s.Fields) == 0 {
+	m.domain(s)
+	if n == 0 {
 		m.fuseHook()
 	}
 	for i := range s.Fields {
@@ -179,6 +248,50 @@ func (m *marshalGen) mapstruct(s *Struct) {
 	}
 }
 
+// omitempty writes a struct with omitempty
+// fields, the ones that aren't empty in a
+// trailing map, see omitempty.go
+func (m *marshalGen) omitempty(s *Struct, header string) {
+	m.fuseHook()
+	sz, set := m.p.countFields(s)
+	m.rawAppend(header, literalFmt, sz)
+	m.domain(s)
+	for i := range s.Fields {
+		if !m.p.ok() {
+			return
+		}
+		if !s.Fields[i].OmitEmpty {
+			next(m, s.Fields[i].FieldElem)
+		}
+	}
+	m.fuseHook()
+	m.p.printf("\nif %s > 0 {", set)
+	m.rawAppend(mapHeader, literalFmt, set)
+	for i := range s.Fields {
+		if !m.p.ok() {
+			return
+		}
+		f := &s.Fields[i]
+		if !f.OmitEmpty {
+			continue
+		}
+		m.p.printf("\nif %s {", nonEmpty(f.FieldElem, f.FieldElem.Varname()))
+		m.rawAppend("String", "%q", f.FieldTag)
+		next(m, f.FieldElem)
+		m.p.closeblock()
+	}
+	m.p.closeblock()
+}
+
+// domain writes the domain of s, if any,
+// as the first object of the struct
+func (m *marshalGen) domain(s *Struct) {
+	if s.Domain != "" {
+		m.p.printf("\n// domain %q", s.Domain)
+		m.Fuse(marshalhash.AppendDomain(nil, s.Domain))
+	}
+}
+
 // append raw data
 func (m *marshalGen) rawbytes(bts []byte) {
 	m.p.print("\no = append(o, ")
@@ -194,14 +307,31 @@ func (m *marshalGen) gMap(s *Map) {
 	}
 	m.fuseHook()
 	vname := s.Varname()
+	order := MapKeyOrder(s.Key)
+	if order == InvalidKeyOrder {
+		m.p.err = fmt.Errorf("unsupported map key type %s in %s", s.Key.TypeName(), s.TypeName())
+		return
+	}
 	m.rawAppend(mapHeader, lenAsUint32, vname)
-	m.p.printf("\n%sSlice := make([]string, 0, len(%s))", s.Keyidx, vname)
-	m.p.printf("\nfor i := range %s {\n%sSlice = append(%sSlice, i)\n}",
-		vname, s.Keyidx, s.Keyidx)
-	m.p.printf("\nsort.Strings(%sSlice)", s.Keyidx)
-	m.p.printf("\nfor _, %s := range %sSlice {\n %s := %s[%s]", s.Keyidx, s.Keyidx, s.Validx, vname, s.Keyidx)
-	//m.p.printf("\nfor %s, %s := range %s {", s.Keyidx, s.Validx, vname)
-	m.rawAppend(stringTyp, literalFmt, s.Keyidx)
+
+	// keys are written in canonical
+	// order, see KeyOrder in mapkey.go
+	if order == EncodedKeyOrder {
+		m.encodedKeys(s)
+		return
+	}
+	m.p.sortedKeys(s, order)
+	next(m, s.Key)
+	next(m, s.Value)
+	m.p.closeblock()
+}
+
+// encodedKeys writes a map whose keys are only
+// known to implement MarshalHash, see
+// printer.encodedKeys in mapkey.go
+func (m *marshalGen) encodedKeys(s *Map) {
+	m.p.encodedKeys(s)
+	m.p.printf("\no = hsp.AppendBytes(o, %sEntry.enc)", s.Keyidx)
 	next(m, s.Value)
 	m.p.closeblock()
 }
@@ -212,6 +342,17 @@ func (m *marshalGen) gSlice(s *Slice) {
 	}
 	m.fuseHook()
 	vname := s.Varname()
+	if s.Merkle != "" {
+		// see merkle.go
+		fn := merkleFn(s)
+		m.p.printf(`
+			if leaves, err := hsp.MerkleLeaves(%s, %s); err != nil {
+				return nil, err
+			} else {
+				o = hsp.AppendMerkleRoot(o, %s, leaves)
+			}`, fn, vname, fn)
+		return
+	}
 	m.rawAppend(arrayHeader, lenAsUint32, vname)
 	m.p.rangeBlock(s.Index, vname, m, s.Els)
 }
@@ -261,17 +402,34 @@ func (m *marshalGen) gBase(b *BaseElem) {
 	var echeck bool
 	switch b.Value {
 	case IDENT:
+		if b.TypeParam {
+			// nil pointers are written as nil,
+			// see hsp.AppendIntfHash
+			echeck = true
+			m.p.printf("\no, err = hsp.AppendIntfHash(o, %s)", vname)
+			break
+		}
+		if m.inline && b.Local {
+			// types generated alongside are
+			// appended in place, unwrapped
+			m.p.printf("\nif o, err = %s.AppendHash(o); err != nil {\nreturn\n}", vname)
+			break
+		}
 		m.p.printf(`
 			if oTemp, err := %s.MarshalHash(); err != nil {
 				return nil, err
 			} else {
 				o = hsp.AppendBytes(o, oTemp)
 			}`, vname)
-	case Intf, Ext:
+	case Intf:
+		// canonical encoding, see hsp.AppendIntfHash
+		echeck = true
+		m.p.printf("\no, err = hsp.AppendIntfHash(o, %s)", vname)
+	case Ext:
 		echeck = true
 		m.p.printf("\no, err = hsp.Append%s(o, %s)", b.BaseName(), vname)
 	default:
-		m.rawAppend(b.BaseName(), literalFmt, vname)
+		m.rawAppend(b.encName(), literalFmt, vname)
 	}
 
 	if echeck {
@@ -0,0 +1,152 @@
+package gen
+
+import (
+	"io"
+	"strings"
+)
+
+// The slices tagged merkle, e.g. `hsp:"3,merkle"`,
+// are written as the root of the Merkle tree of the
+// MarshalHash output of their elements, see merkle.go
+// in the hsp package, computed with the digest
+// algorithm of their type, or sha256. The methods
+// printed by merkleGen prove that an element is part
+// of the tree. The elements can't be decoded from the
+// root: UnmarshalHash leaves the slice empty.
+
+// CanMerkle returns whether e can be tagged merkle:
+// a slice of named types implementing MarshalHash.
+func CanMerkle(e Elem) bool {
+	s, ok := e.(*Slice)
+	if !ok {
+		return false
+	}
+	be, ok := s.Els.(*BaseElem)
+	return ok && be.Value == IDENT && !be.TypeParam
+}
+
+// SetMerkleDigest sets the digest algorithm of the
+// slices tagged merkle of e, including the ones of its
+// fields, elements and pointers, but not of the named
+// types, and returns whether e holds any. See
+// DigestImport.
+func SetMerkleDigest(e Elem, algo string) bool {
+	switch e := e.(type) {
+	case *Ptr:
+		return SetMerkleDigest(e.Value, algo)
+	case *Slice:
+		if e.Merkle != "" {
+			e.Merkle = algo
+			return true
+		}
+		return SetMerkleDigest(e.Els, algo)
+	case *Array:
+		return SetMerkleDigest(e.Els, algo)
+	case *Map:
+		return SetMerkleDigest(e.Value, algo)
+	case *Struct:
+		found := false
+		for i := range e.Fields {
+			if SetMerkleDigest(e.Fields[i].FieldElem, algo) {
+				found = true
+			}
+		}
+		return found
+	}
+	return false
+}
+
+// merkleFn returns the function computing
+// the digests of the tree of s
+func merkleFn(s *Slice) string {
+	return digests[s.Merkle].fn
+}
+
+// hasMerkle returns whether a field of the struct e is
+// tagged merkle, so that UnmarshalHash can't restore it
+func hasMerkle(e Elem) bool {
+	s, ok := e.(*Struct)
+	if !ok {
+		return false
+	}
+	for i := range s.Fields {
+		if sl, ok := s.Fields[i].FieldElem.(*Slice); ok && sl.Merkle != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func merkle(w io.Writer) *merkleGen {
+	return &merkleGen{p: printer{w: w}}
+}
+
+// merkleGen prints the methods computing the
+// root of the fields tagged merkle and proving
+// that an element is part of it
+type merkleGen struct {
+	passes
+	p printer
+	v string
+}
+
+func (m *merkleGen) Method() Method { return Marshal }
+
+func (m *merkleGen) setVersion(v string) {
+	m.v = v
+}
+
+func (m *merkleGen) Execute(p Elem) error {
+	if !m.p.ok() {
+		return m.p.err
+	}
+	p = m.applyall(p)
+	if p == nil || !IsPrintable(p) {
+		return nil
+	}
+	// the fields of the current version only, and
+	// not the ones of the type parameters' types
+	s, ok := p.(*Struct)
+	if !ok || m.v != "" || isGeneric(p) {
+		return nil
+	}
+
+	c := p.Varname()
+	recv := imutMethodReceiver(p)
+	for i := range s.Fields {
+		sl, ok := s.Fields[i].FieldElem.(*Slice)
+		if !ok || sl.Merkle == "" {
+			continue
+		}
+		// flattened fields are named by their path
+		name := s.Fields[i].FieldName
+		method := strings.Replace(name, ".", "", -1)
+		fn := merkleFn(sl)
+		vname := c + "." + name
+
+		m.p.comment(method + "MerkleRoot returns the root of the " + sl.Merkle +
+			" Merkle tree of " + name + ", written by MarshalHash in its place")
+		m.p.printf("\nfunc (%s %s) %sMerkleRoot() (root [32]byte, err error) {", c, recv, method)
+		m.p.print("\nvar leaves [][32]byte")
+		m.p.printf("\nif leaves, err = hsp.MerkleLeaves(%s, %s); err != nil {\nreturn\n}", fn, vname)
+		m.p.printf("\nroot = hsp.MerkleRoot(%s, leaves)", fn)
+		m.p.nakedReturn()
+
+		m.p.comment(method + "Proof returns the proof that the element i of " +
+			name + " is part of the tree of " + method + "MerkleRoot")
+		m.p.printf("\nfunc (%s %s) %sProof(i int) (proof hsp.MerkleProof, err error) {", c, recv, method)
+		m.p.print("\nvar leaves [][32]byte")
+		m.p.printf("\nif leaves, err = hsp.MerkleLeaves(%s, %s); err != nil {\nreturn\n}", fn, vname)
+		m.p.printf("\nreturn hsp.NewMerkleProof(%s, leaves, i)\n}\n", fn)
+
+		verify := "Verify" + p.TypeName() + method + "Proof"
+		m.p.comment(verify + " returns whether proof shows that v is the element proof.Index of the " +
+			name + " of a " + p.TypeName() + " whose " + method + "MerkleRoot is root")
+		m.p.printf("\nfunc %s(root [32]byte, v %s, proof hsp.MerkleProof) (ok bool, err error) {", verify, sl.Els.TypeName())
+		m.p.print("\nvar o []byte")
+		m.p.print("\nif o, err = v.MarshalHash(); err != nil {\nreturn\n}")
+		m.p.printf("\nok = proof.Verify(%s, root, hsp.MerkleLeaf(%s, o))", fn, fn)
+		m.p.nakedReturn()
+	}
+	return m.p.err
+}
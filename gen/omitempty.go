@@ -0,0 +1,144 @@
+package gen
+
+// The fields tagged omitempty are left out of
+// the encoding when they hold an empty value, so
+// that adding such a field to a struct doesn't
+// change the hashes of the existing values.
+//
+// The rule deciding whether a value is empty is
+// part of the encoding and must never change:
+//
+//   - false, 0 (including -0) and "" are empty
+//   - nil pointers and interfaces are empty
+//   - slices, maps and []byte of length 0 are
+//     empty, whether they are nil or not
+//   - the zero time.Time is empty
+//
+// Any other type (structs, arrays, extensions,
+// nested types...) has no empty value, and
+// omitempty is ignored on its fields.
+//
+// The other fields are written first, after the
+// domain of the struct if any, in the order of
+// their tags. The omitempty fields that
+// aren't empty follow, in a map from their tag,
+// as a string, to their value, in the same order,
+// counted as one more field by the map or array
+// header of the struct; the map is left out when
+// they are all empty. As the tags are only read
+// as the keys of this map, a value of another
+// field can't be taken for one of them.
+
+// CanOmitEmpty returns whether omitempty
+// is supported for the element e.
+func CanOmitEmpty(e Elem) bool {
+	return nonEmpty(e, "x") != ""
+}
+
+// nonEmpty returns the expression reporting
+// whether vname, of element e, isn't empty,
+// or "" if e has no empty value
+func nonEmpty(e Elem, vname string) string {
+	switch e := e.(type) {
+	case *Ptr:
+		return vname + " != nil"
+	case *Slice, *Map:
+		return "len(" + vname + ") != 0"
+	case *BaseElem:
+		if e.Convert {
+			if e.ShimMode != Cast {
+				// converting shims may fail
+				return ""
+			}
+			vname = e.ToBase() + "(" + vname + ")"
+		}
+		switch e.Value {
+		case Bytes:
+			return "len(" + vname + ") != 0"
+		case String:
+			return vname + ` != ""`
+		case Bool:
+			return vname
+		case Intf:
+			return vname + " != nil"
+		case Time:
+			return "!" + vname + ".IsZero()"
+		case Float32, Float64, Complex64, Complex128,
+			Uint, Uint8, Uint16, Uint32, Uint64, Byte,
+			Int, Int8, Int16, Int32, Int64:
+			return vname + " != 0"
+		}
+	}
+	return ""
+}
+
+// zeroValue returns the expression of
+// the empty value of element e, as set by
+// UnmarshalHash when a field is omitted
+func zeroValue(e Elem) string {
+	switch e := e.(type) {
+	case *Ptr, *Slice, *Map:
+		return "nil"
+	case *BaseElem:
+		var zero string
+		switch e.Value {
+		case Bytes, Intf:
+			zero = "nil"
+		case String:
+			zero = `""`
+		case Bool:
+			zero = "false"
+		case Time:
+			zero = "time.Time{}"
+		default:
+			zero = "0"
+		}
+		if e.Convert {
+			return e.FromBase() + "(" + zero + ")"
+		}
+		return zero
+	}
+	return ""
+}
+
+// omitted returns the number of
+// omitempty fields of s
+func (s *Struct) omitted() int {
+	n := 0
+	for i := range s.Fields {
+		if s.Fields[i].OmitEmpty {
+			n++
+		}
+	}
+	return n
+}
+
+// fixedFields returns the number of objects of s
+// always counted by its header: its domain, if
+// any, and the fields not tagged omitempty
+func (s *Struct) fixedFields() int {
+	n := len(s.Fields) - s.omitted()
+	if s.Domain != "" {
+		n++
+	}
+	return n
+}
+
+// countFields declares the variables holding the
+// header of s written by MarshalHash, and the
+// number of omitempty fields that aren't empty,
+// and returns their names
+func (p *printer) countFields(s *Struct) (sz string, set string) {
+	set = randIdent()
+	p.printf("\n%s := uint32(0)", set)
+	for i := range s.Fields {
+		f := &s.Fields[i]
+		if f.OmitEmpty {
+			p.printf("\nif %s {\n%s++\n}", nonEmpty(f.FieldElem, f.FieldElem.Varname()), set)
+		}
+	}
+	sz = randIdent()
+	p.printf("\n%s := uint32(%d)", sz, s.fixedFields())
+	p.printf("\nif %s > 0 {\n%s++\n}", set, sz)
+	return sz, set
+}
@@ -0,0 +1,199 @@
+package gen
+
+import (
+	"io"
+	"strings"
+)
+
+func random(w io.Writer) *randGen {
+	return &randGen{
+		p: printer{w: w},
+	}
+}
+
+// randGen generates the hspRandom functions of
+// the tests, which populate a value of the type
+// with the values drawn from an hsp.Rand, walking
+// the same tree as the other generators.
+//
+// Nested types generated alongside are populated
+// by their own function; the types of other files
+// or packages are left to their zero value.
+type randGen struct {
+	passes
+	p printer
+	v string
+}
+
+func (r *randGen) Method() Method { return marshaltest }
+
+func (r *randGen) setVersion(v string) {
+	r.v = v
+}
+
+// randFunc is the name of the function
+// populating values of the type typ
+func randFunc(typ string) string {
+	return "hspRandom" + typ
+}
+
+func (r *randGen) Execute(p Elem) error {
+	if !r.p.ok() {
+		return r.p.err
+	}
+	// populators fill the current struct
+	// and are shared by the version tests
+	if r.v != "" {
+		return nil
+	}
+	p = r.applyall(p)
+	if p == nil || !IsPrintable(p) {
+		return nil
+	}
+	switch p.(type) {
+	case *Struct, *Array, *Slice, *Map:
+	default:
+		return nil
+	}
+
+	c := p.Varname()
+	r.p.comment(randFunc(p.TypeName()) + " populates " + c + " with values drawn from r")
+	r.p.printf("\nfunc %s(%s %s, r *hsp.Rand) {", randFunc(p.TypeName()), c, methodReceiver(p))
+	r.p.print("\nif !r.Enter() {\nreturn\n}\ndefer r.Leave()")
+	next(r, p)
+	if ps, ok := p.(*Struct); ok && ps.Versioning {
+		// the only version MarshalHash accepts
+		r.p.printf("\n%s.%s = %d", c, ps.VersionField, ps.CurrentNumericVersion)
+	}
+	r.p.print("\n}\n\n")
+	unsetReceiver(p)
+	return r.p.err
+}
+
+func (r *randGen) gStruct(s *Struct) {
+	if !r.p.ok() {
+		return
+	}
+	for i := range s.Fields {
+		if !r.p.ok() {
+			return
+		}
+		next(r, s.Fields[i].FieldElem)
+	}
+}
+
+func (r *randGen) gSlice(s *Slice) {
+	if !r.p.ok() {
+		return
+	}
+	r.p.printf("\n%s = make(%s, r.Len())", s.Varname(), s.TypeName())
+	if fills(s.Els) {
+		r.p.rangeBlock(s.Index, s.Varname(), r, s.Els)
+	}
+}
+
+func (r *randGen) gArray(a *Array) {
+	if !r.p.ok() {
+		return
+	}
+	if be, ok := a.Els.(*BaseElem); ok && be.Value == Byte {
+		r.p.printf("\nr.Read((%s)[:])", a.Varname())
+		return
+	}
+	if fills(a.Els) {
+		r.p.rangeBlock(a.Index, a.Varname(), r, a.Els)
+	}
+}
+
+func (r *randGen) gMap(m *Map) {
+	if !r.p.ok() {
+		return
+	}
+	vname := m.Varname()
+	r.p.printf("\n%s = make(%s)", vname, m.TypeName())
+	r.p.printf("\nfor n := r.Len(); n > 0; n-- {")
+	r.p.declare(m.Keyidx, m.Key.TypeName())
+	r.p.declare(m.Validx, m.Value.TypeName())
+	next(r, m.Key)
+	next(r, m.Value)
+	r.p.printf("\n%s[%s] = %s", vname, m.Keyidx, m.Validx)
+	r.p.closeblock()
+}
+
+func (r *randGen) gPtr(p *Ptr) {
+	if !r.p.ok() {
+		return
+	}
+	if !fills(p.Value) {
+		// left nil
+		return
+	}
+	be, ident := p.Value.(*BaseElem)
+	ident = ident && be.Value == IDENT && !be.Convert
+	r.p.printf("\nif r.Nil() {\n%s = nil\n} else {", p.Varname())
+	r.p.printf("\n%s = new(%s)", p.Varname(), p.Value.TypeName())
+	if ident {
+		// identities keep the name of the pointer
+		r.p.printf("\n%s(%s, r)", randFunc(be.TypeName()), p.Varname())
+	} else {
+		next(r, p.Value)
+	}
+	r.p.closeblock()
+}
+
+// fills returns whether the populator sets
+// any part of e: the types of other files or
+// packages are unknown, and, like extensions
+// and shims through a reference, keep their
+// zero value
+func fills(e Elem) bool {
+	switch e := e.(type) {
+	case *BaseElem:
+		switch {
+		case e.needsref, e.Value == Ext:
+			return false
+		case e.Value == IDENT:
+			// generic types have no populator
+			return e.Local && !e.Convert && !strings.Contains(e.TypeName(), "[")
+		}
+		return true
+	case *Ptr:
+		return fills(e.Value)
+	case *Slice:
+		return true
+	case *Array:
+		return fills(e.Els)
+	case *Map:
+		return true
+	case *Struct:
+		for i := range e.Fields {
+			if fills(e.Fields[i].FieldElem) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func (r *randGen) gBase(b *BaseElem) {
+	if !r.p.ok() || !fills(b) {
+		return
+	}
+	vname := b.Varname()
+
+	if b.Value == IDENT {
+		r.p.printf("\n%s(&%s, r)", randFunc(b.TypeName()), vname)
+		return
+	}
+	val := "r." + b.BaseName() + "()"
+
+	switch {
+	case !b.Convert:
+		r.p.printf("\n%s = %s", vname, val)
+	case b.ShimMode == Cast:
+		r.p.printf("\n%s = %s(%s)", vname, b.FromBase(), val)
+	default:
+		r.p.printf("\nif v, err := %s(%s); err == nil {\n%s = v\n}", b.FromBase(), val, vname)
+	}
+}
@@ -0,0 +1,98 @@
+package gen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+var keyOrders = map[KeyOrder]string{
+	StringKeyOrder:  "string",
+	NumericKeyOrder: "numeric",
+	BytesKeyOrder:   "bytes",
+	EncodedKeyOrder: "encoded",
+}
+
+// SchemaOf returns the schema of e, whose named
+// types generated alongside are nested in place if
+// inline is set, as with //hsp:nesting inline.
+func SchemaOf(e Elem, inline bool) *marshalhash.Schema {
+	if st, ok := e.(*Struct); ok {
+		// only the fields of the type itself are
+		// sorted, see marshalGen.sort: the structs
+		// inlined into it keep their declaration order
+		sorted := *st
+		sorted.Fields = append([]StructField(nil), st.Fields...)
+		sort.Sort(&sorted)
+		return structSchema(&sorted, inline)
+	}
+	return schemaOf(e, inline)
+}
+
+func schemaOf(e Elem, inline bool) *marshalhash.Schema {
+	switch e := e.(type) {
+	case *BaseElem:
+		if e.Value != IDENT {
+			return &marshalhash.Schema{Kind: "primitive", Type: e.TypeName(), Encoding: e.encName()}
+		}
+		s := &marshalhash.Schema{Kind: "named", Type: e.TypeName(), Nesting: "bin"}
+		switch {
+		case e.TypeParam:
+			s.Nesting = "intf"
+		case inline && e.Local:
+			s.Nesting = "inline"
+		}
+		return s
+	case *Ptr:
+		return &marshalhash.Schema{Kind: "pointer", Elem: schemaOf(e.Value, inline)}
+	case *Slice:
+		if e.Merkle != "" {
+			return &marshalhash.Schema{Kind: "merkle", Type: e.TypeName(), Digest: e.Merkle, Elem: schemaOf(e.Els, inline)}
+		}
+		return &marshalhash.Schema{Kind: "slice", Type: e.TypeName(), Elem: schemaOf(e.Els, inline)}
+	case *Array:
+		s := &marshalhash.Schema{Kind: "array", Type: e.TypeName(), Size: e.Size, Elem: schemaOf(e.Els, inline)}
+		if be, ok := e.Els.(*BaseElem); ok && be.Value == Byte {
+			s.Encoding = "Bytes"
+		}
+		return s
+	case *Map:
+		return &marshalhash.Schema{
+			Kind:     "map",
+			Type:     e.TypeName(),
+			KeyOrder: keyOrders[MapKeyOrder(e.Key)],
+			Key:      schemaOf(e.Key, inline),
+			Elem:     schemaOf(e.Value, inline),
+		}
+	case *Struct:
+		return structSchema(e, inline)
+	}
+	return nil
+}
+
+func structSchema(st *Struct, inline bool) *marshalhash.Schema {
+	s := &marshalhash.Schema{Kind: "struct", Mode: "map"}
+	if !strings.HasPrefix(st.TypeName(), "struct{") {
+		s.Type = st.TypeName()
+	}
+	if st.AsTuple {
+		s.Mode = "tuple"
+	}
+	s.Domain = st.Domain
+	for _, f := range st.Fields {
+		s.Fields = append(s.Fields, marshalhash.SchemaField{
+			Name:      f.FieldName,
+			Tag:       f.FieldTag,
+			OmitEmpty: f.OmitEmpty,
+			Type:      schemaOf(f.FieldElem, inline),
+		})
+	}
+	if st.Versioning {
+		version := st.CurrentNumericVersion
+		s.VersionField = st.VersionField
+		s.Versions = st.VersionList
+		s.Version = &version
+	}
+	return s
+}
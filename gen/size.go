@@ -21,18 +21,20 @@ const (
 	expr
 )
 
-func sizes(w io.Writer) *sizeGen {
+func sizes(w io.Writer, inline bool) *sizeGen {
 	return &sizeGen{
-		p:     printer{w: w},
-		state: assign,
+		p:      printer{w: w},
+		state:  assign,
+		inline: inline,
 	}
 }
 
 type sizeGen struct {
 	passes
-	v     string
-	p     printer
-	state sizeState
+	v      string
+	p      printer
+	state  sizeState
+	inline bool // nested types are appended in place, see marshalGen
 }
 
 func (s *sizeGen) Method() Method { return Size }
@@ -102,8 +104,8 @@ func (s *sizeGen) Execute(p Elem) error {
 			}
 			s.p.print("\ndefault:")
 			s.p.print("\nreturn 0")
-			s.p.print("\n}")
-			s.p.nakedReturn()
+			// every case returns
+			s.p.print("\n}\n}\n")
 		} else {
 			next(s, p)
 			s.p.nakedReturn()
@@ -120,8 +122,16 @@ func (s *sizeGen) gStruct(st *Struct) {
 		return
 	}
 
-	nfields := uint32(len(st.Fields))
+	nfields := uint32(st.fixedFields())
+	if st.Domain != "" {
+		s.addConstant(strconv.Itoa(len(marshalhash.AppendDomain(nil, st.Domain))))
+	}
 
+	if st.omitted() > 0 {
+		// the map of the omitempty fields
+		nfields++
+		s.addConstant(builtinSize("MapHeader"))
+	}
 	if st.AsTuple {
 		data := marshalhash.AppendArrayHeader(nil, nfields)
 		s.addConstant(strconv.Itoa(len(data)))
@@ -129,6 +139,11 @@ func (s *sizeGen) gStruct(st *Struct) {
 			if !s.p.ok() {
 				return
 			}
+			if st.Fields[i].OmitEmpty {
+				// written after its tag
+				data = marshalhash.AppendString(data[:0], st.Fields[i].FieldTag)
+				s.addConstant(strconv.Itoa(len(data)))
+			}
 			next(s, st.Fields[i].FieldElem)
 		}
 	} else {
@@ -156,6 +171,10 @@ func (s *sizeGen) gSlice(sl *Slice) {
 		return
 	}
 
+	if sl.Merkle != "" {
+		s.addConstant(builtinSize("MerkleRoot"))
+		return
+	}
 	s.addConstant(builtinSize(arrayHeader))
 
 	// if the slice's element is a fixed size
@@ -198,8 +217,18 @@ func (s *sizeGen) gMap(m *Map) {
 	s.p.printf("\nif %s != nil {", vn)
 	s.p.printf("\nfor %s, %s := range %s {", m.Keyidx, m.Validx, vn)
 	s.p.printf("\n_ = %s", m.Validx) // we may not use the value
-	s.p.printf("\ns += hsp.StringPrefixSize + len(%s)", m.Keyidx)
-	s.state = expr
+	if _, ok := fixedsizeExpr(m.Key); ok {
+		s.p.printf("\n_ = %s", m.Keyidx) // nor the key
+	}
+	s.state = add
+	if MapKeyOrder(m.Key) == EncodedKeyOrder {
+		// written as a 'bin' object, even
+		// when nested types are inlined
+		s.addConstant(builtinSize("BytesPrefix"))
+		s.addConstant(basesizeExpr(IDENT, m.Keyidx, ""))
+	} else {
+		next(s, m.Key)
+	}
 	next(s, m.Value)
 	s.p.closeblock()
 	s.p.closeblock()
@@ -218,7 +247,7 @@ func (s *sizeGen) gBase(b *BaseElem) {
 		// ensure we don't get "unused variable" warnings from outer slice iterations
 		s.p.printf("\n_ = %s", b.Varname())
 
-		s.p.printf("\ns += %s", basesizeExpr(b.Value, vname, b.BaseName()))
+		s.p.printf("\ns += %s", basesizeExpr(b.Value, vname, b.encName()))
 		s.state = expr
 
 	} else {
@@ -226,7 +255,16 @@ func (s *sizeGen) gBase(b *BaseElem) {
 		if b.Convert {
 			vname = tobaseConvert(b)
 		}
-		s.addConstant(basesizeExpr(b.Value, vname, b.BaseName()))
+		if b.TypeParam {
+			// only known to implement hsp.HashMarshaler
+			s.addConstant("hsp.HashSize(" + vname + ")")
+			return
+		}
+		if b.Value == IDENT && !(s.inline && b.Local) {
+			// nested in a 'bin' object
+			s.addConstant(builtinSize("BytesPrefix"))
+		}
+		s.addConstant(basesizeExpr(b.Value, vname, b.encName()))
 	}
 }
 
@@ -265,7 +303,7 @@ func fixedsizeExpr(e Elem) (string, bool) {
 		}
 	case *BaseElem:
 		if fixedSize(e.Value) {
-			return builtinSize(e.BaseName()), true
+			return builtinSize(e.encName()), true
 		}
 	case *Struct:
 		var str string
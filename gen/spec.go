@@ -20,7 +20,7 @@ const (
 
 // Method is a bitfield representing something that the
 // generator knows how to print.
-type Method uint8
+type Method uint16
 
 // are the bits in 'f' set in 'm'?
 func (m Method) isset(f Method) bool { return m&f == f }
@@ -32,13 +32,25 @@ func (m Method) String() string {
 		return "<invalid method>"
 	case Marshal:
 		return "marshal"
+	case Unmarshal:
+		return "unmarshal"
+	case Append:
+		return "append"
+	case Stream:
+		return "stream"
+	case Encode:
+		return "encode"
+	case Equal:
+		return "equal"
+	case Golden:
+		return "golden"
 	case Size:
 		return "size"
 	case Test:
 		return "test"
 	default:
 		// return e.g. "decode+encode+test"
-		modes := [...]Method{Marshal, Size, Test}
+		modes := [...]Method{Marshal, Unmarshal, Append, Stream, Encode, Equal, Golden, Size, Test}
 		any := false
 		nm := ""
 		for _, mm := range modes {
@@ -57,57 +69,163 @@ func (m Method) String() string {
 }
 
 const (
-	Marshal     Method           = 1 << iota // hsp.Marshaler
-	Size                                     // hsp.Sizer
-	Test                                     // generate tests
-	invalidmeth                              // this isn't a method
-	marshaltest = Marshal | Test             // tests for Marshaler and Unmarshaler
+	Marshal       Method                       = 1 << iota // hsp.Marshaler
+	Unmarshal                                              // UnmarshalHash
+	Append                                                 // AppendHash, nested types are appended in place
+	Stream                                                 // WriteHash, streams MarshalHash into an io.Writer
+	Encode                                                 // EncodeHash, WriteHash for hsp.Encode
+	Equal                                                  // EqualHash, compares as MarshalHash does
+	Golden                                                 // golden hash tests, see hsp.CheckGolden
+	Size                                                   // hsp.Sizer
+	Test                                                   // generate tests
+	invalidmeth                                            // this isn't a method
+	marshaltest   = Marshal | Test                         // tests for Marshaler
+	unmarshaltest = Marshal | Unmarshal | Test             // tests for UnmarshalHash round trips
+	streamtest    = Marshal | Stream | Test                // tests for WriteHash
+	encodetest    = Marshal | Encode | Test                // tests for EncodeHash
+	equaltest     = Marshal | Equal | Test                 // tests for EqualHash
+	goldentest    = Marshal | Golden | Test                // golden hash tests
 )
 
 type Printer struct {
-	m     Method
-	out   io.Writer
-	tests io.Writer
-	gens  []generator
+	m       Method
+	out     io.Writer
+	tests   io.Writer
+	gens    []generator
+	digests map[string]string
+	golden  *golden
 }
 
 func NewPrinter(m Method, out io.Writer, tests io.Writer, v string) *Printer {
 	if m.isset(Test) && tests == nil {
 		panic("cannot print tests with 'nil' tests argument!")
 	}
-	gens := make([]generator, 0, 7)
+	gens := make([]generator, 0, 12)
+	digests := make(map[string]string)
+	gold := &golden{samples: make(map[string][]string)}
 	if m.isset(Marshal) {
-		mg := marshal(out)
+		mg := marshal(out, m.isset(Append))
+		dg := digest(out, digests)
+		kg := merkle(out)
 		if v != "" {
 			mg.setVersion(v)
+			dg.setVersion(v)
+			kg.setVersion(v)
 		}
-		gens = append(gens, mg)
+		gens = append(gens, mg, dg, kg)
+	}
+	if m.isset(Unmarshal) {
+		ug := unmarshal(out, m.isset(Append))
+		if v != "" {
+			ug.setVersion(v)
+		}
+		gens = append(gens, ug)
+	}
+	if m.isset(Stream) {
+		wg := writehash(out, m.isset(Append))
+		if v != "" {
+			wg.setVersion(v)
+		}
+		gens = append(gens, wg)
+	}
+	if m.isset(Encode) {
+		ng := encode(out)
+		if v != "" {
+			ng.setVersion(v)
+		}
+		gens = append(gens, ng)
+	}
+	if m.isset(Equal) {
+		eg := equal(out)
+		if v != "" {
+			eg.setVersion(v)
+		}
+		gens = append(gens, eg)
 	}
 	if m.isset(Size) {
-		sg := sizes(out)
+		sg := sizes(out, m.isset(Append))
 		if v != "" {
 			sg.setVersion(v)
 		}
 		gens = append(gens, sg)
 	}
 	if m.isset(marshaltest) {
+		rg := random(tests)
 		tg := mtest(tests)
 		if v != "" {
+			rg.setVersion(v)
 			tg.setVersion(v)
 		}
-		gens = append(gens, tg)
+		gens = append(gens, rg, tg)
+	}
+	if m.isset(unmarshaltest) {
+		ut := utest(tests)
+		if v != "" {
+			ut.setVersion(v)
+		}
+		gens = append(gens, ut)
+	}
+	if m.isset(streamtest) {
+		st := stest(tests)
+		if v != "" {
+			st.setVersion(v)
+		}
+		gens = append(gens, st)
+	}
+	if m.isset(encodetest) {
+		nt := ntest(tests)
+		if v != "" {
+			nt.setVersion(v)
+		}
+		gens = append(gens, nt)
+	}
+	if m.isset(equaltest) {
+		et := etest(tests)
+		if v != "" {
+			et.setVersion(v)
+		}
+		gens = append(gens, et)
+	}
+	if m.isset(goldentest) {
+		gt := gtest(tests, gold)
+		if v != "" {
+			gt.setVersion(v)
+		}
+		gens = append(gens, gt)
 	}
 	if len(gens) == 0 {
 		panic("NewPrinter called with invalid method flags")
 	}
 	return &Printer{
-		m:     m,
-		gens:  gens,
-		out:   out,
-		tests: tests,
+		m:       m,
+		gens:    gens,
+		out:     out,
+		tests:   tests,
+		digests: digests,
+		golden:  gold,
 	}
 }
 
+// Digest sets the algorithm of the Digest methods
+// printed for the type named typ, or for all the
+// types if typ is empty. See DigestImport.
+func (p *Printer) Digest(typ string, algo string) {
+	p.digests[typ] = algo
+}
+
+// GoldenFile sets the file holding the golden
+// hashes checked by the tests, see hsp.CheckGolden.
+func (p *Printer) GoldenFile(file string) {
+	p.golden.file = file
+}
+
+// GoldenSamples sets the functions returning the
+// samples of the type named typ checked against the
+// golden hashes, in place of random values.
+func (p *Printer) GoldenSamples(typ string, funcs []string) {
+	p.golden.samples[typ] = funcs
+}
+
 // TransformPass is a pass that transforms individual
 // elements. (Note that if the returned is different from
 // the argument, it should not point to the same objects.)
@@ -117,7 +235,7 @@ type TransformPass func(Elem) Elem
 // types of a given name.
 func IgnoreTypename(name string) TransformPass {
 	return func(e Elem) Elem {
-		if e.TypeName() == name {
+		if BaseName(e.TypeName()) == name {
 			return nil
 		}
 		return e
@@ -137,6 +255,10 @@ func (p *Printer) ApplyDirective(pass Method, t TransformPass) {
 // Print prints an Elem.
 func (p *Printer) Print(e Elem) error {
 	for _, g := range p.gens {
+		if g.Method().isset(Test) && isGeneric(e) {
+			// see generic.go
+			continue
+		}
 		// Elem.SetVarname() is called before the Print() step in parse.FileSet.PrintTo().
 		// Elem.SetVarname() generates identifiers as it walks the Elem. This can cause
 		// collisions between idents created during SetVarname and idents created during Print,
@@ -6,7 +6,12 @@ import (
 )
 
 var (
-	marshalTestTempl = template.New("MarshalTest")
+	marshalTestTempl   = template.New("MarshalTest")
+	unmarshalTestTempl = template.New("UnmarshalTest")
+	streamTestTempl    = template.New("StreamTest")
+	encodeTestTempl    = template.New("EncodeTest")
+	equalTestTempl     = template.New("EqualTest")
+	goldenTestTempl    = template.New("GoldenTest")
 )
 
 func mtest(w io.Writer) *mtestGen {
@@ -41,25 +46,237 @@ func (m *mtestGen) Execute(p Elem) error {
 
 func (m *mtestGen) Method() Method { return marshaltest }
 
+func utest(w io.Writer) *utestGen {
+	return &utestGen{w: w}
+}
+
+type utestGen struct {
+	passes
+	v string
+	w io.Writer
+}
+
+func (u *utestGen) setVersion(v string) {
+	u.v = v
+}
+
+func (u *utestGen) Execute(p Elem) error {
+	p = u.applyall(p)
+	// old version types have no UnmarshalHash, and
+	// the zero value of a versioned type does not
+	// use the current version, which is the only
+	// one decoded; versions are tested one by one
+	if ps, ok := p.(*Struct); ok && ps.Versioning && u.v == "" {
+		return nil
+	}
+	// the fields tagged merkle aren't decoded
+	if hasMerkle(p) {
+		return nil
+	}
+	if p != nil && IsPrintable(p) && u.v != "oldver" {
+		switch p.(type) {
+		case *Struct, *Array, *Slice, *Map:
+			if u.v != "" {
+				return template.Must(unmarshalTestTempl.Clone()).Funcs(template.FuncMap{
+					"suffix": func() string { return u.v },
+				}).Execute(u.w, p)
+			}
+			return unmarshalTestTempl.Execute(u.w, p)
+		}
+	}
+	return nil
+}
+
+func (u *utestGen) Method() Method { return unmarshaltest }
+
+func stest(w io.Writer) *stestGen {
+	return &stestGen{w: w}
+}
+
+type stestGen struct {
+	passes
+	v string
+	w io.Writer
+}
+
+func (s *stestGen) setVersion(v string) {
+	s.v = v
+}
+
+func (s *stestGen) Execute(p Elem) error {
+	p = s.applyall(p)
+	if p != nil && IsPrintable(p) {
+		switch p.(type) {
+		case *Struct, *Array, *Slice, *Map:
+			if s.v != "" {
+				return template.Must(streamTestTempl.Clone()).Funcs(template.FuncMap{
+					"suffix": func() string { return s.v },
+				}).Execute(s.w, p)
+			}
+			return streamTestTempl.Execute(s.w, p)
+		}
+	}
+	return nil
+}
+
+func (s *stestGen) Method() Method { return streamtest }
+
+func ntest(w io.Writer) *ntestGen {
+	return &ntestGen{w: w}
+}
+
+type ntestGen struct {
+	passes
+	v string
+	w io.Writer
+}
+
+func (n *ntestGen) setVersion(v string) {
+	n.v = v
+}
+
+func (n *ntestGen) Execute(p Elem) error {
+	p = n.applyall(p)
+	// EncodeHash covers all the versions
+	if p != nil && IsPrintable(p) && n.v == "" {
+		switch p.(type) {
+		case *Struct, *Array, *Slice, *Map:
+			return encodeTestTempl.Execute(n.w, p)
+		}
+	}
+	return nil
+}
+
+func (n *ntestGen) Method() Method { return encodetest }
+
+func etest(w io.Writer) *etestGen {
+	return &etestGen{w: w}
+}
+
+type etestGen struct {
+	passes
+	v string
+	w io.Writer
+}
+
+func (e *etestGen) setVersion(v string) {
+	e.v = v
+}
+
+func (e *etestGen) Execute(p Elem) error {
+	p = e.applyall(p)
+	if p != nil && IsPrintable(p) {
+		switch p.(type) {
+		case *Struct, *Array, *Slice, *Map:
+			if e.v != "" {
+				return template.Must(equalTestTempl.Clone()).Funcs(template.FuncMap{
+					"suffix": func() string { return e.v },
+				}).Execute(e.w, p)
+			}
+			return equalTestTempl.Execute(e.w, p)
+		}
+	}
+	return nil
+}
+
+func (e *etestGen) Method() Method { return equaltest }
+
+// golden holds the options of the
+// golden hash tests, see Printer.GoldenFile
+type golden struct {
+	file    string              // golden file, relative to the package
+	samples map[string][]string // type name -> sample functions
+}
+
+func gtest(w io.Writer, g *golden) *gtestGen {
+	return &gtestGen{w: w, g: g}
+}
+
+type gtestGen struct {
+	passes
+	v string
+	w io.Writer
+	g *golden
+}
+
+func (g *gtestGen) setVersion(v string) {
+	g.v = v
+}
+
+func (g *gtestGen) Execute(p Elem) error {
+	p = g.applyall(p)
+	if p != nil && IsPrintable(p) {
+		switch p.(type) {
+		case *Struct, *Array, *Slice, *Map:
+			return template.Must(goldenTestTempl.Clone()).Funcs(template.FuncMap{
+				"suffix":  func() string { return g.v },
+				"file":    func() string { return g.g.file },
+				"samples": func() []string { return g.g.samples[p.TypeName()] },
+			}).Execute(g.w, p)
+		}
+	}
+	return nil
+}
+
+func (g *gtestGen) Method() Method { return goldentest }
+
 func init() {
 	template.Must(marshalTestTempl.Funcs(template.FuncMap{
 		"suffix": func() string { return "" },
 	}).Parse(`func TestMarshalHash{{suffix}}{{.TypeName}}(t *testing.T) {
-	v := {{.TypeName}}{}
-	binary.Read(rand.Reader, binary.BigEndian, &v)
-	bts1, err := v.MarshalHash{{suffix}}()
-	if err != nil {
-		t.Fatal(err)
-	}
-	bts2, err := v.MarshalHash{{suffix}}()
-	if err != nil {
-		t.Fatal(err)
-	}
-	if !bytes.Equal(bts1, bts2) {
-		t.Fatal("hash not stable")
+	for seed := int64(0); seed < 16; seed++ {
+		v := {{.TypeName}}{}
+		hspRandom{{.TypeName}}(&v, hsp.NewRand(seed))
+		bts1, err := v.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatalf("seed %d: hash not stable", seed)
+		}
+		if s := v.Msgsize{{suffix}}(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash()", seed, s, len(bts1))
+		}
+
+		// the sizes of the elements of long
+		// containers exceed the slack of headers
+		long := {{.TypeName}}{}
+		hspRandom{{.TypeName}}(&long, hsp.NewRand(seed).Long(256))
+		if bts1, err = long.MarshalHash{{suffix}}(); err != nil {
+			t.Fatal(err)
+		}
+		if s := long.Msgsize{{suffix}}(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash() with long containers", seed, s, len(bts1))
+		}
 	}
 }
 
+func FuzzMarshalHash{{suffix}}{{.TypeName}}(f *testing.F) {
+	f.Add([]byte("hsp"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := {{.TypeName}}{}
+		hspRandom{{.TypeName}}(&v, hsp.NewRandBytes(data).Long(256))
+		bts1, err := v.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatal("hash not stable")
+		}
+		if s := v.Msgsize{{suffix}}(); s < len(bts1) {
+			t.Fatalf("Msgsize() = %d, less than the %d bytes of MarshalHash()", s, len(bts1))
+		}
+	})
+}
+
 func BenchmarkMarshalHash{{suffix}}{{.TypeName}}(b *testing.B) {
 	v := {{.TypeName}}{}
 	b.ReportAllocs()
@@ -81,6 +298,172 @@ func BenchmarkAppendMsg{{suffix}}{{.TypeName}}(b *testing.B) {
 	}
 }
 
+`))
+
+	template.Must(unmarshalTestTempl.Funcs(template.FuncMap{
+		"suffix": func() string { return "" },
+	}).Parse(`func TestUnmarshalHash{{suffix}}{{.TypeName}}(t *testing.T) {
+	for seed := int64(0); seed < 16; seed++ {
+		v := {{.TypeName}}{}
+		hspRandom{{.TypeName}}(&v, hsp.NewRand(seed))
+		bts1, err := v.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		vn := {{.TypeName}}{}
+		left, err := vn.UnmarshalHash{{suffix}}(bts1)
+		if err != nil {
+			t.Fatalf("seed %d: %s", seed, err)
+		}
+		if len(left) > 0 {
+			t.Errorf("seed %d: %d bytes left over after UnmarshalHash(): %q", seed, len(left), left)
+		}
+		bts2, err := vn.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatalf("seed %d: hash not stable after round trip", seed)
+		}
+	}
+}
+
+func BenchmarkUnmarshalHash{{suffix}}{{.TypeName}}(b *testing.B) {
+	v := {{.TypeName}}{}
+	bts, _ := v.MarshalHash{{suffix}}()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i:=0; i<b.N; i++ {
+		_, err := v.UnmarshalHash{{suffix}}(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+`))
+
+	template.Must(streamTestTempl.Funcs(template.FuncMap{
+		"suffix": func() string { return "" },
+	}).Parse(`func TestWriteHash{{suffix}}{{.TypeName}}(t *testing.T) {
+	for seed := int64(0); seed < 16; seed++ {
+		v := {{.TypeName}}{}
+		hspRandom{{.TypeName}}(&v, hsp.NewRand(seed))
+		bts, err := v.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		err = v.WriteHash{{suffix}}(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts, buf.Bytes()) {
+			t.Fatalf("seed %d: WriteHash output differs from MarshalHash", seed)
+		}
+	}
+}
+
+func BenchmarkWriteHash{{suffix}}{{.TypeName}}(b *testing.B) {
+	v := {{.TypeName}}{}
+	bts, _ := v.MarshalHash{{suffix}}()
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i:=0; i<b.N; i++ {
+		v.WriteHash{{suffix}}(hsp.Nowhere)
+	}
+}
+
+`))
+
+	template.Must(encodeTestTempl.Parse(`func TestEncodeHash{{.TypeName}}(t *testing.T) {
+	for seed := int64(0); seed < 16; seed++ {
+		v := {{.TypeName}}{}
+		hspRandom{{.TypeName}}(&v, hsp.NewRand(seed))
+		bts, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		err = hsp.EncodeHash(&buf, &v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts, buf.Bytes()) {
+			t.Fatalf("seed %d: EncodeHash output differs from MarshalHash", seed)
+		}
+	}
+}
+
+`))
+
+	template.Must(equalTestTempl.Funcs(template.FuncMap{
+		"suffix": func() string { return "" },
+	}).Parse(`func TestEqualHash{{suffix}}{{.TypeName}}(t *testing.T) {
+	for seed := int64(0); seed < 16; seed++ {
+		v, vo := {{.TypeName}}{}, {{.TypeName}}{}
+		hspRandom{{.TypeName}}(&v, hsp.NewRand(seed))
+		hspRandom{{.TypeName}}(&vo, hsp.NewRand(seed+1))
+		if !v.EqualHash{{suffix}}(&v) || !vo.EqualHash{{suffix}}(&vo) {
+			t.Fatalf("seed %d: value not equal to itself", seed)
+		}
+		bts, err := v.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		btso, err := vo.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.EqualHash{{suffix}}(&vo) != bytes.Equal(bts, btso) {
+			t.Fatalf("seed %d: EqualHash disagrees with MarshalHash", seed)
+		}
+	}
+}
+
+func BenchmarkEqualHash{{suffix}}{{.TypeName}}(b *testing.B) {
+	v := {{.TypeName}}{}
+	vo := {{.TypeName}}{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i:=0; i<b.N; i++ {
+		v.EqualHash{{suffix}}(&vo)
+	}
+}
+
+`))
+
+	template.Must(goldenTestTempl.Funcs(template.FuncMap{
+		"suffix":  func() string { return "" },
+		"file":    func() string { return "" },
+		"samples": func() []string { return nil },
+	}).Parse(`func TestGoldenHash{{suffix}}{{.TypeName}}(t *testing.T) {
+	var samples []hsp.GoldenSample
+{{- range samples}}
+	{
+		v := {{.}}()
+		bts, err := v.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		samples = append(samples, hsp.GoldenSample{Name: "{{.}}", Value: v, Hash: bts})
+	}
+{{- else}}
+	for seed := int64(0); seed < 4; seed++ {
+		v := {{.TypeName}}{}
+		hspRandom{{.TypeName}}(&v, hsp.NewRand(seed))
+		bts, err := v.MarshalHash{{suffix}}()
+		if err != nil {
+			t.Fatal(err)
+		}
+		samples = append(samples, hsp.GoldenSample{Name: fmt.Sprintf("seed%d", seed), Value: v, Hash: bts})
+	}
+{{- end}}
+	hsp.CheckGolden(t, "{{file}}", "{{.TypeName}}{{suffix}}", samples)
+}
+
 `))
 
 }
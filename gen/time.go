@@ -0,0 +1,82 @@
+package gen
+
+// The times are written by hsp.AppendTime with
+// their nanoseconds, unless their field is tagged
+// with another encoding, e.g. `hsp:"ts,time=unix_ms"`,
+// which keeps the precision of the values stored
+// elsewhere: see time.go in the hsp package.
+
+// TimeMode is the encoding
+// of the time.Time elements.
+type TimeMode uint8
+
+const (
+	TimeExt       TimeMode = iota // hsp.AppendTime
+	TimeUnix                      // time=unix
+	TimeUnixMilli                 // time=unix_ms
+	TimeUnixMicro                 // time=unix_us
+	TimeRFC3339                   // time=rfc3339
+)
+
+// timeModes are the values
+// of the time tag option
+var timeModes = map[string]TimeMode{
+	"unix":    TimeUnix,
+	"unix_ms": TimeUnixMilli,
+	"unix_us": TimeUnixMicro,
+	"rfc3339": TimeRFC3339,
+}
+
+// ParseTimeMode returns the mode
+// of a time tag option value.
+func ParseTimeMode(s string) (TimeMode, bool) {
+	m, ok := timeModes[s]
+	return m, ok
+}
+
+// String returns the suffix of the
+// hsp functions writing the mode
+func (m TimeMode) String() string {
+	switch m {
+	case TimeUnix:
+		return "Unix"
+	case TimeUnixMilli:
+		return "UnixMilli"
+	case TimeUnixMicro:
+		return "UnixMicro"
+	case TimeRFC3339:
+		return "RFC3339"
+	}
+	return ""
+}
+
+// SetTimeMode sets the mode of the times of e, including
+// the ones of its fields, elements and pointers, but not of
+// the named types, and returns whether e holds any.
+func SetTimeMode(e Elem, m TimeMode) bool {
+	switch e := e.(type) {
+	case *BaseElem:
+		if e.Value != Time {
+			return false
+		}
+		e.Time = m
+		return true
+	case *Ptr:
+		return SetTimeMode(e.Value, m)
+	case *Slice:
+		return SetTimeMode(e.Els, m)
+	case *Array:
+		return SetTimeMode(e.Els, m)
+	case *Map:
+		return SetTimeMode(e.Value, m)
+	case *Struct:
+		found := false
+		for i := range e.Fields {
+			if SetTimeMode(e.Fields[i].FieldElem, m) {
+				found = true
+			}
+		}
+		return found
+	}
+	return false
+}
@@ -0,0 +1,292 @@
+package gen
+
+import (
+	"io"
+	"sort"
+	"strconv"
+)
+
+func unmarshal(w io.Writer, inline bool) *unmarshalGen {
+	return &unmarshalGen{
+		p:      printer{w: w},
+		inline: inline,
+	}
+}
+
+type unmarshalGen struct {
+	passes
+	p      printer
+	v      string
+	inline bool // nested types are decoded in place, see AppendHash
+}
+
+func (u *unmarshalGen) Method() Method { return Unmarshal }
+
+func (u *unmarshalGen) setVersion(v string) {
+	u.v = v
+}
+
+// the field order must match the one
+// written by marshalGen, as no keys are
+// available to look fields up by name
+func (u *unmarshalGen) sort(e Elem) {
+	if es, ok := e.(*Struct); ok {
+		sort.Sort(es)
+	}
+}
+
+func (u *unmarshalGen) Execute(p Elem) error {
+	if !u.p.ok() {
+		return u.p.err
+	}
+	p = u.applyall(p)
+	if p == nil {
+		return nil
+	}
+	u.sort(p)
+
+	if !IsPrintable(p) {
+		return nil
+	}
+
+	// the old version body was written by
+	// a generator without decoding support
+	if u.v == "oldver" {
+		return nil
+	}
+
+	// save the vname before calling
+	// methodReceiver, which may alter it
+	c := p.Varname()
+
+	u.p.comment("UnmarshalHash" + u.v + " unmarshals the output of MarshalHash" + u.v)
+	u.p.printf("\nfunc (%s %s) UnmarshalHash%s(bts []byte) (o []byte, err error) {", c, methodReceiver(p), u.v)
+
+	if ps, ok := p.(*Struct); ok && ps.Versioning && u.v == "" {
+		// the encoding carries no version prefix,
+		// so only the current version is decoded
+		u.p.printf("\nif o, err = %s.UnmarshalHash%s(bts); err != nil {\nreturn\n}", c, ps.CurrentVersion)
+		u.p.printf("\nif %s.HSPCurrentVersion() != %d {", c, ps.CurrentNumericVersion)
+		u.p.print("\nerr = herr.New(\"invalid struct version\")")
+		u.p.print("\n}")
+		u.p.nakedReturn()
+	} else {
+		next(u, p)
+		if ps, ok := p.(*Struct); ok && ps.CacheField != "" {
+			u.p.printf("\n%s.%s.Invalidate()", c, ps.CacheField)
+		}
+		u.p.print("\no = bts")
+		u.p.nakedReturn()
+	}
+	unsetReceiver(p)
+	return u.p.err
+}
+
+func (u *unmarshalGen) gStruct(s *Struct) {
+	if !u.p.ok() {
+		return
+	}
+	sz := randIdent()
+	u.p.declare(sz, u32)
+	if s.AsTuple {
+		u.p.printf("\n%s, bts, err = hsp.ReadArrayHeaderBytes(bts)", sz)
+	} else {
+		u.p.printf("\n%s, bts, err = hsp.ReadMapHeaderBytes(bts)", sz)
+	}
+	u.p.print(errcheck)
+	if s.omitted() > 0 {
+		u.omitempty(s, sz)
+		return
+	}
+	u.p.arrayCheck(strconv.Itoa(s.fixedFields()), sz)
+	u.domain(s)
+	for i := range s.Fields {
+		if !u.p.ok() {
+			return
+		}
+		next(u, s.Fields[i].FieldElem)
+	}
+}
+
+// omitempty decodes the fields of a struct with
+// omitempty fields, sz being its header: the ones
+// that aren't empty are in a trailing map, counted
+// by sz, from their tag to their value
+func (u *unmarshalGen) omitempty(s *Struct, sz string) {
+	plain := strconv.Itoa(s.fixedFields())
+	omitted := strconv.Itoa(s.omitted())
+	u.p.printf("\nif %[1]s < %[2]s || %[1]s > %[2]s+1 { err = hsp.ArrayError{Wanted: %[2]s+1, Got: %[1]s}; return }", sz, plain)
+	u.domain(s)
+	for i := range s.Fields {
+		if !u.p.ok() {
+			return
+		}
+		f := &s.Fields[i]
+		if !f.OmitEmpty {
+			next(u, f.FieldElem)
+			continue
+		}
+		u.p.printf("\n%s = %s", f.FieldElem.Varname(), zeroValue(f.FieldElem))
+	}
+	set := randIdent()
+	u.p.printf("\nif %s > %s {", sz, plain)
+	u.p.printf("\nvar %s uint32", set)
+	u.p.printf("\n%s, bts, err = hsp.ReadMapHeaderBytes(bts)", set)
+	u.p.print(errcheck)
+	u.p.printf("\nif %[1]s == 0 || %[1]s > %[2]s { err = hsp.ArrayError{Wanted: %[2]s, Got: %[1]s}; return }", set, omitted)
+	for i := range s.Fields {
+		if !u.p.ok() {
+			return
+		}
+		f := &s.Fields[i]
+		if !f.OmitEmpty {
+			continue
+		}
+		// the tags are written in the order of the fields
+		u.p.printf("\nif tag, rest, terr := hsp.ReadStringZC(bts); %s > 0 && terr == nil && string(tag) == %q {", set, f.FieldTag)
+		u.p.printf("\nbts = rest\n%s--", set)
+		next(u, f.FieldElem)
+		u.p.closeblock()
+	}
+	// unknown tags, or out of order
+	u.p.printf("\nif %s != 0 { err = hsp.ArrayError{Wanted: 0, Got: %s}; return }", set, set)
+	u.p.closeblock()
+}
+
+// domain checks the domain of s, if any,
+// written first in the struct
+func (u *unmarshalGen) domain(s *Struct) {
+	if s.Domain != "" {
+		u.p.printf("\nbts, err = hsp.ReadDomainBytes(bts, %q)", s.Domain)
+		u.p.print(errcheck)
+	}
+}
+
+func (u *unmarshalGen) gArray(a *Array) {
+	if !u.p.ok() {
+		return
+	}
+
+	// special case for [const]byte objects
+	// see marshal.go for symmetry
+	if be, ok := a.Els.(*BaseElem); ok && be.Value == Byte {
+		u.p.printf("\nbts, err = hsp.ReadExactBytes(bts, (%s)[:])", a.Varname())
+		u.p.print(errcheck)
+		return
+	}
+
+	sz := randIdent()
+	u.p.declare(sz, u32)
+	u.p.printf("\n%s, bts, err = hsp.ReadArrayHeaderBytes(bts)", sz)
+	u.p.print(errcheck)
+	u.p.arrayCheck(coerceArraySize(a.Size), sz)
+	u.p.rangeBlock(a.Index, a.Varname(), u, a.Els)
+}
+
+func (u *unmarshalGen) gSlice(s *Slice) {
+	if !u.p.ok() {
+		return
+	}
+	if s.Merkle != "" {
+		// the elements can't be decoded
+		// from the root, see merkle.go
+		u.p.print("\n_, bts, err = hsp.ReadMerkleRootBytes(bts)")
+		u.p.print(errcheck)
+		u.p.printf("\n%s = nil", s.Varname())
+		return
+	}
+	sz := randIdent()
+	u.p.declare(sz, u32)
+	u.p.printf("\n%s, bts, err = hsp.ReadArrayHeaderBytes(bts)", sz)
+	u.p.print(errcheck)
+	u.p.resizeSlice(sz, s)
+	u.p.rangeBlock(s.Index, s.Varname(), u, s.Els)
+}
+
+func (u *unmarshalGen) gMap(m *Map) {
+	if !u.p.ok() {
+		return
+	}
+	sz := randIdent()
+	u.p.declare(sz, u32)
+	u.p.printf("\n%s, bts, err = hsp.ReadMapHeaderBytes(bts)", sz)
+	u.p.print(errcheck)
+
+	// allocate or clear map
+	u.p.resizeMap(sz, m)
+
+	// loop and get key,value
+	u.p.printf("\nfor %s > 0 {", sz)
+	u.p.printf("\nvar %s %s; var %s %s; %s--", m.Keyidx, m.Key.TypeName(), m.Validx, m.Value.TypeName(), sz)
+	next(u, m.Key)
+	next(u, m.Value)
+	u.p.mapAssign(m)
+	u.p.closeblock()
+}
+
+func (u *unmarshalGen) gPtr(p *Ptr) {
+	u.p.printf("\nif hsp.IsNil(bts) { bts, err = hsp.ReadNilBytes(bts); if err != nil { return }; %s = nil; } else { ", p.Varname())
+	u.p.initPtr(p)
+	next(u, p.Value)
+	u.p.closeblock()
+}
+
+func (u *unmarshalGen) gBase(b *BaseElem) {
+	if !u.p.ok() {
+		return
+	}
+
+	refname := b.Varname() // assigned to
+	lowered := b.Varname() // passed as argument
+	if b.Convert {
+		// begin 'tmp' block
+		refname = randIdent()
+		lowered = b.ToBase() + "(" + lowered + ")"
+		u.p.printf("\n{\nvar %s %s", refname, b.BaseType())
+	}
+
+	switch b.Value {
+	case Bytes:
+		u.p.printf("\n%s, bts, err = hsp.ReadBytesBytes(bts, %s)", refname, lowered)
+	case Ext:
+		u.p.printf("\nbts, err = hsp.ReadExtensionBytes(bts, %s)", lowered)
+	case IDENT:
+		if b.TypeParam {
+			// only known to implement hsp.HashMarshaler
+			u.p.printf("\nbts, err = hsp.ReadHashMarshalerBytes(bts, &%s)", lowered)
+			break
+		}
+		if u.inline && b.Local {
+			u.p.printf("\nbts, err = %s.UnmarshalHash(bts)", lowered)
+			break
+		}
+		// nested types are wrapped in a 'bin' object
+		// by marshalGen, see gBase in marshal.go
+		inner := randIdent()
+		u.p.printf("\nvar %s []byte", inner)
+		u.p.printf("\n%s, bts, err = hsp.ReadBytesZC(bts)", inner)
+		u.p.print(errcheck)
+		u.p.printf("\n_, err = %s.UnmarshalHash(%s)", lowered, inner)
+	case Time:
+		if b.Time != TimeExt {
+			// nil is read as the zero time
+			u.p.printf("\n%s, bts, err = hsp.Read%sBytes(bts)", refname, b.encName())
+			break
+		}
+		// zero time is written as nil by hsp.AppendTime
+		u.p.printf("\nif hsp.IsNil(bts) { bts, err = hsp.ReadNilBytes(bts); %s = time.Time{} } else { %s, bts, err = hsp.ReadTimeBytes(bts) }", refname, refname)
+	default:
+		u.p.printf("\n%s, bts, err = hsp.Read%sBytes(bts)", refname, b.encName())
+	}
+	u.p.print(errcheck)
+
+	if b.Convert {
+		// close 'tmp' block
+		if b.ShimMode == Cast {
+			u.p.printf("\n%s = %s(%s)\n}", b.Varname(), b.FromBase(), refname)
+		} else {
+			u.p.printf("\n%s, err = %s(%s)\n}", b.Varname(), b.FromBase(), refname)
+			u.p.print(errcheck)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+func writehash(w io.Writer, inline bool) *writeHashGen {
+	return &writeHashGen{
+		p:      printer{w: w},
+		inline: inline,
+	}
+}
+
+// writeHashGen generates WriteHash, which
+// streams the exact bytes of MarshalHash
+// into an io.Writer, usually a hash.Hash,
+// through a small hsp.Writer buffer.
+type writeHashGen struct {
+	passes
+	p      printer
+	fuse   []byte
+	v      string
+	inline bool // nested types are written in place, see AppendHash
+}
+
+func (e *writeHashGen) Method() Method { return Stream }
+
+func (e *writeHashGen) setVersion(v string) {
+	e.v = v
+}
+
+// the field order must match the one
+// written by marshalGen
+func (e *writeHashGen) sort(el Elem) {
+	if es, ok := el.(*Struct); ok {
+		sort.Sort(es)
+	}
+}
+
+func (e *writeHashGen) Execute(p Elem) error {
+	if !e.p.ok() {
+		return e.p.err
+	}
+	p = e.applyall(p)
+	if p == nil {
+		return nil
+	}
+	e.sort(p)
+
+	if !IsPrintable(p) {
+		return nil
+	}
+
+	// save the vname before calling
+	// methodReceiver, which may alter it
+	c := p.Varname()
+
+	e.p.comment("WriteHash" + e.v + " writes the output of MarshalHash" + e.v + " to w")
+	e.p.printf("\nfunc (%s %s) WriteHash%s(w io.Writer) (err error) {", c, imutMethodReceiver(p), e.v)
+
+	if e.v == "oldver" {
+		// the old version body only
+		// exists as a MarshalHash method
+		e.p.printf("\nvar o []byte")
+		e.p.printf("\nif o, err = %s.MarshalHasholdver(); err != nil {\nreturn\n}", c)
+		e.p.print("\n_, err = w.Write(o)")
+		e.p.nakedReturn()
+		return e.p.err
+	}
+
+	if ps, ok := p.(*Struct); ok && ps.Versioning && e.v == "" {
+		e.p.printf("\nswitch %s.HSPCurrentVersion() {", c)
+		for i := range ps.VersionList {
+			e.p.printf("\ncase %d:", i)
+			e.p.printf("\nreturn %s.WriteHash%s(w)", c, ps.VersionList[i])
+		}
+		e.p.print("\ndefault:")
+		e.p.print("\nerr = herr.New(\"invalid struct version\")")
+		e.p.print("\nreturn")
+		// every case returns
+		e.p.print("\n}\n}\n")
+		return e.p.err
+	}
+
+	e.p.print("\nen := hsp.NewHashWriter(w)")
+	next(e, p)
+	e.fuseHook()
+	e.p.print("\nerr = hsp.ReleaseHashWriter(en, w)")
+	e.p.nakedReturn()
+	return e.p.err
+}
+
+func (e *writeHashGen) fuseHook() {
+	if len(e.fuse) > 0 {
+		e.appendraw(e.fuse)
+		e.fuse = e.fuse[:0]
+	}
+}
+
+func (e *writeHashGen) Fuse(b []byte) {
+	if len(e.fuse) == 0 {
+		e.fuse = b
+	} else {
+		e.fuse = append(e.fuse, b...)
+	}
+}
+
+func (e *writeHashGen) appendraw(bts []byte) {
+	e.p.print("\nerr = en.Append(")
+	for i, b := range bts {
+		if i != 0 {
+			e.p.print(", ")
+		}
+		e.p.printf("0x%x", b)
+	}
+	e.p.print(")\nif err != nil { return }")
+}
+
+func (e *writeHashGen) writeAndCheck(typ string, argfmt string, arg interface{}) {
+	e.p.printf("\nerr = en.Write%s(%s)", typ, fmt.Sprintf(argfmt, arg))
+	e.p.print(errcheck)
+}
+
+func (e *writeHashGen) gStruct(s *Struct) {
+	if !e.p.ok() {
+		return
+	}
+	if s.omitted() > 0 {
+		e.omitempty(s)
+		return
+	}
+	data := make([]byte, 0, 5)
+	n := s.fixedFields()
+	if s.AsTuple {
+		data = marshalhash.AppendArrayHeader(data, uint32(n))
+		e.p.printf("\n// array header, size %d", n)
+	} else {
+		data = marshalhash.AppendMapHeader(data, uint32(n))
+		e.p.printf("\n// map header, size %d", n)
+	}
+	e.Fuse(data)
+	e.domain(s)
+	if n == 0 {
+		e.fuseHook()
+	}
+	for i := range s.Fields {
+		if !e.p.ok() {
+			return
+		}
+		next(e, s.Fields[i].FieldElem)
+	}
+}
+
+// omitempty writes a struct with omitempty
+// fields as marshalGen does
+func (e *writeHashGen) omitempty(s *Struct) {
+	e.fuseHook()
+	sz, set := e.p.countFields(s)
+	if s.AsTuple {
+		e.writeAndCheck(arrayHeader, literalFmt, sz)
+	} else {
+		e.writeAndCheck(mapHeader, literalFmt, sz)
+	}
+	e.domain(s)
+	for i := range s.Fields {
+		if !e.p.ok() {
+			return
+		}
+		if !s.Fields[i].OmitEmpty {
+			next(e, s.Fields[i].FieldElem)
+		}
+	}
+	e.fuseHook()
+	e.p.printf("\nif %s > 0 {", set)
+	e.writeAndCheck(mapHeader, literalFmt, set)
+	for i := range s.Fields {
+		if !e.p.ok() {
+			return
+		}
+		f := &s.Fields[i]
+		if !f.OmitEmpty {
+			continue
+		}
+		e.p.printf("\nif %s {", nonEmpty(f.FieldElem, f.FieldElem.Varname()))
+		e.writeAndCheck("String", "%q", f.FieldTag)
+		next(e, f.FieldElem)
+		e.p.closeblock()
+	}
+	e.p.closeblock()
+}
+
+// domain writes the domain of s
+// as marshalGen does
+func (e *writeHashGen) domain(s *Struct) {
+	if s.Domain != "" {
+		e.p.printf("\n// domain %q", s.Domain)
+		e.Fuse(marshalhash.AppendDomain(nil, s.Domain))
+	}
+}
+
+func (e *writeHashGen) gMap(s *Map) {
+	if !e.p.ok() {
+		return
+	}
+	e.fuseHook()
+	order := MapKeyOrder(s.Key)
+	if order == InvalidKeyOrder {
+		e.p.err = fmt.Errorf("unsupported map key type %s in %s", s.Key.TypeName(), s.TypeName())
+		return
+	}
+	e.writeAndCheck(mapHeader, lenAsUint32, s.Varname())
+
+	// same canonical order as marshalGen
+	if order == EncodedKeyOrder {
+		e.p.encodedKeys(s)
+		e.writeAndCheck("Bytes", "%sEntry.enc", s.Keyidx)
+	} else {
+		e.p.sortedKeys(s, order)
+		next(e, s.Key)
+	}
+	next(e, s.Value)
+	e.p.closeblock()
+}
+
+func (e *writeHashGen) gSlice(s *Slice) {
+	if !e.p.ok() {
+		return
+	}
+	e.fuseHook()
+	vname := s.Varname()
+	if s.Merkle != "" {
+		// as marshalGen does
+		fn := merkleFn(s)
+		e.p.printf(`
+			if leaves, err := hsp.MerkleLeaves(%s, %s); err != nil {
+				return err
+			} else {
+				root := hsp.MerkleRoot(%s, leaves)
+				err = en.WriteBytes(root[:])
+				if err != nil { return err }
+			}`, fn, vname, fn)
+		return
+	}
+	e.writeAndCheck(arrayHeader, lenAsUint32, vname)
+	e.p.rangeBlock(s.Index, vname, e, s.Els)
+}
+
+func (e *writeHashGen) gArray(a *Array) {
+	if !e.p.ok() {
+		return
+	}
+	e.fuseHook()
+	if be, ok := a.Els.(*BaseElem); ok && be.Value == Byte {
+		e.writeAndCheck("Bytes", "(%s)[:]", a.Varname())
+		return
+	}
+
+	e.writeAndCheck(arrayHeader, literalFmt, coerceArraySize(a.Size))
+	e.p.rangeBlock(a.Index, a.Varname(), e, a.Els)
+}
+
+func (e *writeHashGen) gPtr(p *Ptr) {
+	if !e.p.ok() {
+		return
+	}
+	e.fuseHook()
+	e.p.printf("\nif %s == nil {\nerr = en.WriteNil()\nif err != nil { return }\n} else {", p.Varname())
+	next(e, p.Value)
+	e.p.closeblock()
+}
+
+func (e *writeHashGen) gBase(b *BaseElem) {
+	if !e.p.ok() {
+		return
+	}
+	e.fuseHook()
+	vname := b.Varname()
+
+	if b.Convert {
+		if b.ShimMode == Cast {
+			vname = tobaseConvert(b)
+		} else {
+			vname = randIdent()
+			e.p.printf("\nvar %s %s", vname, b.BaseType())
+			e.p.printf("\n%s, err = %s", vname, tobaseConvert(b))
+			e.p.printf(errcheck)
+		}
+	}
+
+	switch b.Value {
+	case IDENT:
+		if b.TypeParam {
+			// as marshalGen does
+			e.p.printf(`
+			if oTemp, err := hsp.AppendIntfHash(nil, %s); err != nil {
+				return err
+			} else {
+				_, err = en.Write(oTemp)
+				if err != nil { return err }
+			}`, vname)
+			break
+		}
+		if e.inline && b.Local {
+			// types generated alongside
+			// stream into the same writer
+			e.p.printf("\nerr = %s.WriteHash(en)", vname)
+			e.p.print(errcheck)
+			break
+		}
+		// nested types are wrapped in a 'bin'
+		// object, see gBase in marshal.go
+		e.p.printf(`
+			if oTemp, err := %s.MarshalHash(); err != nil {
+				return err
+			} else {
+				err = en.WriteBytes(oTemp)
+				if err != nil { return err }
+			}`, vname)
+	case Intf:
+		// Writer.WriteIntf is not canonical,
+		// see hsp.AppendIntfHash
+		e.p.printf(`
+			if oTemp, err := hsp.AppendIntfHash(nil, %s); err != nil {
+				return err
+			} else {
+				_, err = en.Write(oTemp)
+				if err != nil { return err }
+			}`, vname)
+	case Time:
+		if b.Time != TimeExt {
+			e.writeAndCheck(b.encName(), literalFmt, vname)
+			break
+		}
+		// zero time is written as nil by hsp.AppendTime
+		e.p.printf("\nif (%s).IsZero() {\nerr = en.WriteNil()\n} else {\nerr = en.WriteTime(%s)\n}", vname, vname)
+		e.p.print(errcheck)
+	default:
+		e.writeAndCheck(b.encName(), literalFmt, vname)
+	}
+}
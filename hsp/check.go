@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ttacon/chalk"
+)
+
+// check implements 'hsp check [paths]': the
+// //go:generate hsp directives of the Go files
+// in paths are run in memory, and the output is
+// compared with the generated files on disk,
+// which must all be written by some directive. A
+// path ending with '/...' is walked recursively.
+// It returns the exit status: 1 if any file is
+// out of date or left over, 2 on errors.
+func check(paths []string) int {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	var gofiles []string
+	for _, p := range paths {
+		found, err := sourceFiles(p)
+		if err != nil {
+			fmt.Println(chalk.Red.Color(err.Error()))
+			return 2
+		}
+		gofiles = append(gofiles, found...)
+	}
+
+	var stale, owned []string
+	written := make(map[string]bool)
+	for _, gofile := range gofiles {
+		dirs, err := generateDirectives(gofile)
+		if err != nil {
+			fmt.Println(chalk.Red.Color(err.Error()))
+			return 2
+		}
+		for _, args := range dirs {
+			res, err := checkDirective(gofile, args)
+			if err != nil {
+				fmt.Println(chalk.Red.Color(gofile + ": " + err.Error()))
+				return 2
+			}
+			stale = append(stale, res.stale...)
+			owned = append(owned, res.owned...)
+			for _, path := range res.written {
+				written[path] = true
+			}
+		}
+	}
+	orphans, err := orphanFiles(owned, written)
+	if err != nil {
+		fmt.Println(chalk.Red.Color(err.Error()))
+		return 2
+	}
+
+	if len(stale) > 0 {
+		fmt.Println(chalk.Red.Color("Generated files out of date, run go generate:"))
+		for _, name := range stale {
+			fmt.Println(chalk.Red.Color("\t" + name))
+		}
+	}
+	if len(orphans) > 0 {
+		fmt.Println(chalk.Red.Color("Generated files not written by any directive, remove them:"))
+		for _, name := range orphans {
+			fmt.Println(chalk.Red.Color("\t" + name))
+		}
+	}
+	if len(stale) > 0 || len(orphans) > 0 {
+		return 1
+	}
+	fmt.Println(chalk.Green.Color("Generated files up to date."))
+	return 0
+}
+
+// sourceFiles returns the Go files of path,
+// without the generated and test files
+func sourceFiles(path string) ([]string, error) {
+	if strings.HasSuffix(path, "/...") {
+		var out []string
+		root := strings.TrimSuffix(path, "/...")
+		err := filepath.Walk(root, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			name := fi.Name()
+			if fi.IsDir() {
+				// skipped by the go tool as well
+				if p != root && (name == "testdata" || name == "vendor" ||
+					strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if isSource(name) {
+				out = append(out, p)
+			}
+			return nil
+		})
+		return out, err
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, fi := range infos {
+		if !fi.IsDir() && isSource(fi.Name()) {
+			out = append(out, filepath.Join(path, fi.Name()))
+		}
+	}
+	return out, nil
+}
+
+func isSource(name string) bool {
+	return strings.HasSuffix(name, ".go") &&
+		!strings.HasSuffix(name, "_gen.go") &&
+		!strings.HasSuffix(name, "_test.go")
+}
+
+// generateDirectives returns the arguments
+// of the //go:generate hsp directives of gofile
+func generateDirectives(gofile string) ([][]string, error) {
+	f, err := os.Open(gofile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out [][]string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "//go:generate ") {
+			continue
+		}
+		words := strings.Fields(line)[1:]
+		// 'go run path/to/hsp' is fine too
+		if len(words) > 2 && words[0] == "go" && words[1] == "run" {
+			words = words[2:]
+		}
+		if len(words) == 0 || filepath.Base(words[0]) != "hsp" {
+			continue
+		}
+		args := words[1:]
+		for i := range args {
+			args[i] = os.Expand(args[i], func(v string) string {
+				if v == "GOFILE" {
+					return filepath.Base(gofile)
+				}
+				return os.Getenv(v)
+			})
+		}
+		out = append(out, args)
+	}
+	return out, sc.Err()
+}
+
+// checked is the outcome of checkDirective
+type checked struct {
+	stale   []string // the files that differ from the ones on disk
+	written []string // the files written or kept by the directive
+	owned   []string // the glob patterns of the files it may write
+}
+
+// checkDirective runs hsp with args as go generate
+// would for gofile, and prints a diff of the files
+// that differ from the ones on disk
+func checkDirective(gofile string, args []string) (*checked, error) {
+	if err := parseDirective(gofile, args); err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(gofile)
+	var (
+		files       map[string][]byte
+		owned, kept []string
+	)
+	err := inDir(dir, func() (err error) {
+		files, owned, kept, err = generate(*file, flagMode(), *unexported)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := new(checked)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		res.written = append(res.written, path)
+		old, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if bytes.Equal(old, files[name]) {
+			continue
+		}
+		from := path
+		if old == nil {
+			from = "/dev/null"
+		}
+		unifiedDiff(os.Stdout, from, path, old, files[name])
+		res.stale = append(res.stale, path)
+	}
+	for _, name := range kept {
+		res.written = append(res.written, filepath.Join(dir, name))
+	}
+	for _, pattern := range owned {
+		res.owned = append(res.owned, filepath.Join(dir, pattern))
+	}
+	return res, nil
+}
+
+// orphanFiles returns the files matching the owned
+// glob patterns that were not written, e.g. the test
+// file of a directive that no longer has -tests, or
+// the files of a version that has been dropped
+func orphanFiles(owned []string, written map[string]bool) ([]string, error) {
+	var out []string
+	seen := make(map[string]bool)
+	for _, pattern := range owned {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			if !written[path] && !seen[path] {
+				seen[path] = true
+				out = append(out, path)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// parseDirective sets the flags as go generate
+// sets them when it runs hsp with args for gofile
+func parseDirective(gofile string, args []string) error {
+	// start over from the defaults
+	flag.VisitAll(func(f *flag.Flag) {
+		f.Value.Set(f.DefValue)
+	})
+	if err := flag.CommandLine.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		*file = filepath.Base(gofile)
+	}
+	return nil
+}
+
+// inDir runs f in the directory dir, as go
+// generate runs hsp in the directory of the file
+func inDir(dir string, f func() error) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+	err = f()
+	if cerr := os.Chdir(wd); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// lines of context around the changes
+const diffContext = 3
+
+type diffLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+// unifiedDiff writes the differences between a and b
+// as a unified diff, with the file names from and to
+func unifiedDiff(w io.Writer, from, to string, a, b []byte) {
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	// keep the changes and their context
+	keep := make([]bool, len(edits))
+	for i, e := range edits {
+		if e.op == ' ' {
+			continue
+		}
+		for j := i - diffContext; j <= i+diffContext; j++ {
+			if j >= 0 && j < len(edits) {
+				keep[j] = true
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", from, to)
+	var ai, bi int // lines of a and b before edits[i]
+	for i := 0; i < len(edits); {
+		if !keep[i] {
+			ai, bi = ai+1, bi+1
+			i++
+			continue
+		}
+		j, an, bn := i, 0, 0
+		for ; j < len(edits) && keep[j]; j++ {
+			if edits[j].op != '+' {
+				an++
+			}
+			if edits[j].op != '-' {
+				bn++
+			}
+		}
+		fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", hunkStart(ai, an), an, hunkStart(bi, bn), bn)
+		for _, e := range edits[i:j] {
+			fmt.Fprintf(w, "%c%s\n", e.op, e.text)
+		}
+		ai, bi, i = ai+an, bi+bn, j
+	}
+}
+
+// the line numbers start at 1, an empty
+// range starts at the line before it
+func hunkStart(before, n int) int {
+	if n == 0 {
+		return before
+	}
+	return before + 1
+}
+
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+// diffLines returns the shortest edit script
+// turning a into b, following Myers' algorithm
+func diffLines(a, b []string) []diffLine {
+	// the common prefix and suffix are
+	// left out of the search
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+
+	out := make([]diffLine, 0, len(a)+len(b))
+	for _, l := range a[:pre] {
+		out = append(out, diffLine{' ', l})
+	}
+	out = append(out, myers(a[pre:len(a)-suf], b[pre:len(b)-suf])...)
+	for _, l := range a[len(a)-suf:] {
+		out = append(out, diffLine{' ', l})
+	}
+	return out
+}
+
+func myers(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	max := n + m
+	off := max + 1
+	v := make([]int, 2*max+3)
+
+	// trace[d] holds v[k] for -d-1 <= k <= d+1
+	// before the step d, at trace[d][k+d+1]
+	var trace [][]int
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[off-d-1:off+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// walk the trace backwards
+	var rev []diffLine
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		tv := trace[d]
+		k := x - y
+		prev := k - 1
+		if k == -d || (k != d && tv[k-1+d+1] < tv[k+1+d+1]) {
+			prev = k + 1
+		}
+		px := tv[prev+d+1]
+		py := px - prev
+		for x > px && y > py {
+			rev = append(rev, diffLine{' ', a[x-1]})
+			x, y = x-1, y-1
+		}
+		if d > 0 {
+			if x == px {
+				rev = append(rev, diffLine{'+', b[y-1]})
+			} else {
+				rev = append(rev, diffLine{'-', a[x-1]})
+			}
+		}
+		x, y = px, py
+	}
+
+	out := make([]diffLine, len(rev))
+	for i := range rev {
+		out[len(rev)-1-i] = rev[i]
+	}
+	return out
+}
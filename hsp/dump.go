@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ttacon/chalk"
+
+	"github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// dump implements 'hsp dump -type pkg.Type [paths]': the
+// MarshalHash output of a value of the type, read in hex
+// from the standard input, is written as a tree of named
+// parts, see marshalhash.Dump. The types are the ones
+// parsed by the //go:generate hsp directives of the Go
+// files in paths, or the ones of a file written by 'hsp
+// schema'. It returns the exit status: 2 on errors.
+func dump(args []string) int {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+
+	flags := flag.NewFlagSet("dump", flag.ContinueOnError)
+	typ := flags.String("type", "", "type of the value, as pkg.Type or Type")
+	schemaFile := flags.String("schema", "", "schema file written by hsp schema, instead of paths")
+	raw := flags.Bool("raw", false, "read the value in binary instead of hex")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *typ == "" {
+		fmt.Println(chalk.Red.Color("No type to dump; use -type pkg.Type."))
+		return 2
+	}
+
+	fs, err := schemaOf(*typ, *schemaFile, flags.Args())
+	if err == nil {
+		var b []byte
+		if b, err = readValue(os.Stdin, *raw); err == nil {
+			err = marshalhash.Dump(stdout, fs, typeName(*typ), b)
+		}
+	}
+	if err != nil {
+		fmt.Println(chalk.Red.Color(err.Error()))
+		return 2
+	}
+	return 0
+}
+
+// typeName strips the package of typ
+func typeName(typ string) string {
+	if i := strings.IndexByte(typ, '.'); i >= 0 && i < strings.IndexByte(typ+"[", '[') {
+		return typ[i+1:]
+	}
+	return typ
+}
+
+// schemaOf returns the schema of the package of
+// typ, as pkg.Type or Type, holding the types of
+// all the files of the package
+func schemaOf(typ, schemaFile string, paths []string) (*marshalhash.FileSchema, error) {
+	var schemas []marshalhash.FileSchema
+	var err error
+	if schemaFile != "" {
+		var data []byte
+		if data, err = ioutil.ReadFile(schemaFile); err == nil {
+			err = json.Unmarshal(data, &schemas)
+		}
+	} else {
+		schemas, err = loadSchemas(paths)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name := typeName(typ)
+	pkg := strings.TrimSuffix(strings.TrimSuffix(typ, name), ".")
+	for _, fs := range schemas {
+		if fs.Lookup(name) == nil || (pkg != "" && fs.Package != pkg) {
+			continue
+		}
+		// the types nested by typ may be
+		// declared in other files
+		all := &marshalhash.FileSchema{
+			Package: fs.Package,
+			Nesting: fs.Nesting,
+			Types:   make(map[string]*marshalhash.Schema),
+		}
+		for _, other := range schemas {
+			if other.Package != fs.Package {
+				continue
+			}
+			for n, s := range other.Types {
+				all.Types[n] = s
+			}
+		}
+		for n, s := range fs.Types {
+			all.Types[n] = s
+		}
+		return all, nil
+	}
+	return nil, fmt.Errorf("no schema for type %s", typ)
+}
+
+// readValue reads an encoding from r, in
+// hex unless raw is set
+func readValue(r io.Reader, raw bool) ([]byte, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil || raw {
+		return data, err
+	}
+	// whitespace and a 0x prefix are fine
+	text := strings.Join(strings.Fields(string(data)), "")
+	return hex.DecodeString(strings.TrimPrefix(text, "0x"))
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ttacon/chalk"
+
+	"github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// hashDiff implements 'hsp diff -type pkg.Type -a file -b
+// file [paths]': the MarshalHash outputs of two values
+// of the type, read in hex from the files, or from the
+// standard input for "-", are walked in parallel and
+// the parts that differ are listed with their values,
+// see marshalhash.Diff. The types are found as with
+// 'hsp dump'. It returns the exit status: 0 if the
+// outputs are equal, 1 if they differ, 2 on errors.
+func hashDiff(args []string) int {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	typ := flags.String("type", "", "type of the values, as pkg.Type or Type")
+	schemaFile := flags.String("schema", "", "schema file written by hsp schema, instead of paths")
+	fileA := flags.String("a", "", "file holding the first value, - for the standard input")
+	fileB := flags.String("b", "", "file holding the second value, - for the standard input")
+	raw := flags.Bool("raw", false, "read the values in binary instead of hex")
+	first := flags.Bool("first", false, "only list the first difference")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *typ == "" || *fileA == "" || *fileB == "" {
+		fmt.Println(chalk.Red.Color("No values to diff; use -type pkg.Type -a file -b file."))
+		return 2
+	}
+
+	diffs, err := diffValues(*typ, *schemaFile, flags.Args(), *fileA, *fileB, *raw)
+	if err == nil && len(diffs) > 0 {
+		if *first {
+			diffs = diffs[:1]
+		}
+		tw := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+		for _, d := range diffs {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.Path, d.Type, orNone(d.A), orNone(d.B))
+		}
+		err = tw.Flush()
+	}
+	if err != nil {
+		fmt.Println(chalk.Red.Color(err.Error()))
+		return 2
+	}
+	if len(diffs) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func diffValues(typ, schemaFile string, paths []string, fileA, fileB string, raw bool) ([]marshalhash.Difference, error) {
+	fs, err := schemaOf(typ, schemaFile, paths)
+	if err != nil {
+		return nil, err
+	}
+	a, err := readFile(fileA, raw)
+	if err != nil {
+		return nil, err
+	}
+	b, err := readFile(fileB, raw)
+	if err != nil {
+		return nil, err
+	}
+	return marshalhash.Diff(fs, typeName(typ), a, b)
+}
+
+// readFile reads an encoding from
+// name, or the standard input for "-"
+func readFile(name string, raw bool) ([]byte, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return readValue(r, raw)
+}
+
+// orNone formats a missing part
+func orNone(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
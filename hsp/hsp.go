@@ -17,6 +17,23 @@ package main
 //  -o = output file name (default is {input}_gen.go)
 //  -file = input file name (or directory; default is $GOFILE, which is set by the `go generate` command)
 //  -tests = generate tests and benchmarks (default is true)
+//  -unmarshal = also generate UnmarshalHash methods (default is false)
+//  -stream = also generate WriteHash methods (default is false)
+//  -encode = also generate EncodeHash methods, for hsp.EncodeHash, and WriteHash (default is false)
+//  -equal = also generate EqualHash methods (default is false)
+//  -typecheck = resolve the types declared in other packages (default is false)
+//  -golden = also generate golden hash tests, implies -tests (default is false)
+//
+// Run 'hsp check [paths]' to check that the files generated by the
+// //go:generate hsp directives of the Go files in paths are up to date,
+// and that no generated file is left over next to them.
+//
+// Run 'hsp schema [paths]' to write a JSON description of the encoding
+// of the types parsed by the same directives, see marshalhash.Schema, and
+// 'hsp dump -type pkg.Type [paths]' to name the parts of the MarshalHash
+// output of a value of the type, read in hex from the standard input.
+// 'hsp diff -type pkg.Type -a file -b file [paths]' lists the parts of
+// two such outputs that differ.
 //
 // For more information, please read README.md, and the wiki at github.com/CovenantSQL/HashStablePack
 //
@@ -40,11 +57,28 @@ var (
 	file       = flag.String("file", "", "input file")
 	tests      = flag.Bool("tests", true, "create tests and benchmarks")
 	unexported = flag.Bool("unexported", false, "also process unexported types")
+	unmarshal  = flag.Bool("unmarshal", false, "create UnmarshalHash methods")
+	stream     = flag.Bool("stream", false, "create WriteHash methods")
+	encode     = flag.Bool("encode", false, "create EncodeHash methods, and WriteHash")
+	equal      = flag.Bool("equal", false, "create EqualHash methods")
+	typecheck  = flag.Bool("typecheck", false, "resolve foreign types by type checking")
+	golden     = flag.Bool("golden", false, "create golden hash tests")
 )
 
 func main() {
 	flag.Parse()
 
+	switch flag.Arg(0) {
+	case "check":
+		os.Exit(check(flag.Args()[1:]))
+	case "schema":
+		os.Exit(schema(flag.Args()[1:]))
+	case "dump":
+		os.Exit(dump(flag.Args()[1:]))
+	case "diff":
+		os.Exit(hashDiff(flag.Args()[1:]))
+	}
+
 	// GOFILE is set by go generate
 	if *file == "" {
 		*file = os.Getenv("GOFILE")
@@ -58,12 +92,7 @@ func main() {
 	})
 	fmt.Printf("file: %s\n", *file)
 
-	var mode gen.Method
-	mode |= gen.Marshal | gen.Size
-	if *tests {
-		mode |= gen.Test
-	}
-
+	mode := flagMode()
 	if mode&^gen.Test == 0 {
 		fmt.Println(chalk.Red.Color("No methods to generate; -io=false && -marshal=false"))
 		os.Exit(1)
@@ -75,73 +104,166 @@ func main() {
 	}
 }
 
+// flagMode returns the methods
+// selected by the command line
+func flagMode() gen.Method {
+	var mode gen.Method
+	mode |= gen.Marshal | gen.Size
+	if *unmarshal {
+		mode |= gen.Unmarshal
+	}
+	if *stream {
+		mode |= gen.Stream
+	}
+	if *encode {
+		mode |= gen.Stream | gen.Encode
+	}
+	if *equal {
+		mode |= gen.Equal
+	}
+	if *tests {
+		mode |= gen.Test
+	}
+	if *golden {
+		mode |= gen.Golden | gen.Test
+	}
+	return mode
+}
+
 // Run writes all methods using the associated file or path, e.g.
 //
 //	err := hsp.Run("path/to/myfile.go", gen.Size|gen.Marshal|gen.Unmarshal|gen.Test, false)
 //
 func Run(gofile string, mode gen.Method, unexported bool) error {
+	files, err := Generate(gofile, mode, unexported)
+	if err != nil {
+		return err
+	}
+	return printer.WriteFiles(files)
+}
+
+// Generate returns the content of the files written
+// by Run by file name, without writing them.
+func Generate(gofile string, mode gen.Method, unexported bool) (map[string][]byte, error) {
+	files, _, _, err := generate(gofile, mode, unexported)
+	return files, err
+}
+
+// generate is Generate, also returning the glob patterns
+// of the files it may write, the generated file, its test
+// file and the files of the versions of its structs, and
+// the files of the earlier versions, which are kept as is.
+func generate(gofile string, mode gen.Method, unexported bool) (files map[string][]byte, owned, kept []string, err error) {
 	if mode&^gen.Test == 0 {
-		return nil
+		return nil, nil, nil, nil
 	}
 	fmt.Println(chalk.Magenta.Color("======== HashStablePack Code Generator ======="))
 	fmt.Printf(chalk.Magenta.Color(">>> Input: \"%s\"\n"), gofile)
-	fs, err := parse.File(gofile, unexported)
+	parseFile := parse.File
+	if *typecheck {
+		parseFile = parse.TypedFile
+	}
+	fs, err := parseFile(gofile, unexported)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
 
 	if len(fs.Identities) == 0 {
 		fmt.Println(chalk.Magenta.Color("No types requiring code generation were found!"))
-		return nil
+		return nil, nil, nil, nil
 	}
 
-	var versionTypes []*gen.Struct
+	if fs.NestInline {
+		mode |= gen.Append
+	}
 
-	for _, el := range fs.Identities {
-		if st, ok := el.(*gen.Struct); ok {
-			if st.Versioning {
-				versionTypes = append(versionTypes, st)
-			}
+	genFileName := newFilename(gofile, fs.Package)
+	stem := strings.TrimSuffix(genFileName, "_gen.go")
+	owned = []string{genFileName, stem + "_gen_test.go"}
+	files = make(map[string][]byte)
+	add := func(more map[string][]byte, err error) error {
+		for name, data := range more {
+			files[name] = data
 		}
+		return err
 	}
 
-	genFileName := newFilename(gofile, fs.Package)
+	versionTypes, err := setVersions(genFileName, fs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, st := range versionTypes {
+		prefix := stem + "_" + strings.ToLower(st.TypeName()) + "_"
+		owned = append(owned, prefix+"*_gen.go", prefix+"*_gen_test.go")
 
-	if len(versionTypes) > 0 {
-		// should parse existing _gen.go for old version data
-		if err := parse.ParseOldGenFile(genFileName, versionTypes); err != nil {
-			return err
+		// print version type files
+		if err := add(printer.RenderVersionFile(genFileName, fs, st, mode)); err != nil {
+			return nil, nil, nil, err
 		}
 
-		// set numeric versions
-		for _, st := range versionTypes {
-			found := false
-			for i, v := range st.VersionList {
-				if v == st.CurrentVersion {
-					found = true
-					st.CurrentNumericVersion = i
-					break
-				}
-			}
-			if !found {
-				st.CurrentNumericVersion = len(st.VersionList)
-				st.VersionList = append(st.VersionList, st.CurrentVersion)
+		if st.OldMarshalBody != "" && st.OldMsgSizeBody != "" {
+			if err := add(printer.RenderOldVersionFile(genFileName, fs, st, mode)); err != nil {
+				return nil, nil, nil, err
 			}
+		}
 
-			// print version type files
-			if err := printer.PrintVersionFile(genFileName, fs, st, mode); err != nil {
-				return err
+		// the methods of the earlier versions
+		// are called by the generated file
+		for _, v := range st.VersionList {
+			if v == st.CurrentVersion {
+				continue
+			}
+			kept = append(kept, prefix+v+"_gen.go")
+			if mode&gen.Test == gen.Test {
+				kept = append(kept, prefix+v+"_gen_test.go")
 			}
+		}
+	}
 
-			if st.OldMarshalBody != "" && st.OldMsgSizeBody != "" {
-				if err := printer.PrintOldVersionFile(genFileName, fs, st, mode); err != nil {
-					return err
-				}
+	if err := add(printer.RenderFile(genFileName, fs, mode)); err != nil {
+		return nil, nil, nil, err
+	}
+	return files, owned, kept, nil
+}
+
+// setVersions sets the version lists of the versioned
+// structs of fs from the existing generated file, with
+// their current version, and returns the structs
+func setVersions(genFileName string, fs *parse.FileSet) ([]*gen.Struct, error) {
+	var versionTypes []*gen.Struct
+
+	for _, el := range fs.Identities {
+		if st, ok := el.(*gen.Struct); ok {
+			if st.Versioning {
+				versionTypes = append(versionTypes, st)
 			}
 		}
 	}
+	if len(versionTypes) == 0 {
+		return nil, nil
+	}
+
+	// should parse existing _gen.go for old version data
+	if err := parse.ParseOldGenFile(genFileName, versionTypes); err != nil {
+		return nil, err
+	}
 
-	return printer.PrintFile(genFileName, fs, mode)
+	// set numeric versions
+	for _, st := range versionTypes {
+		found := false
+		for i, v := range st.VersionList {
+			if v == st.CurrentVersion {
+				found = true
+				st.CurrentNumericVersion = i
+				break
+			}
+		}
+		if !found {
+			st.CurrentNumericVersion = len(st.VersionList)
+			st.VersionList = append(st.VersionList, st.CurrentVersion)
+		}
+	}
+	return versionTypes, nil
 }
 
 // picks a new file name based on input flags and input filename(s).
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ttacon/chalk"
+
+	"github.com/CovenantSQL/HashStablePack/gen"
+	"github.com/CovenantSQL/HashStablePack/marshalhash"
+	"github.com/CovenantSQL/HashStablePack/parse"
+)
+
+// schema implements 'hsp schema [paths]': the
+// //go:generate hsp directives of the Go files in
+// paths are run as 'hsp check' does, but the schema
+// of the encoding of the parsed types is written as
+// JSON to the standard output instead, see
+// marshalhash.Schema. The logs go to the standard
+// error. It returns the exit status: 2 on errors.
+func schema(paths []string) int {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+
+	schemas, err := loadSchemas(paths)
+	if err == nil {
+		enc := json.NewEncoder(stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "\t")
+		err = enc.Encode(schemas)
+	}
+	if err != nil {
+		fmt.Println(chalk.Red.Color(err.Error()))
+		return 2
+	}
+	return 0
+}
+
+// loadSchemas runs the //go:generate hsp directives
+// of the Go files in paths, as 'hsp check' does, and
+// returns the schemas of the types they parse
+func loadSchemas(paths []string) ([]marshalhash.FileSchema, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	schemas := []marshalhash.FileSchema{}
+	for _, p := range paths {
+		gofiles, err := sourceFiles(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, gofile := range gofiles {
+			dirs, err := generateDirectives(gofile)
+			if err != nil {
+				return nil, err
+			}
+			for _, args := range dirs {
+				fs, err := directiveSchema(gofile, args)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s", gofile, err)
+				}
+				schemas = append(schemas, fs)
+			}
+		}
+	}
+	return schemas, nil
+}
+
+// directiveSchema parses the file hsp parses when
+// go generate runs it with args for gofile, and
+// returns the schema of its types
+func directiveSchema(gofile string, args []string) (marshalhash.FileSchema, error) {
+	var out marshalhash.FileSchema
+	if err := parseDirective(gofile, args); err != nil {
+		return out, err
+	}
+	dir := filepath.Dir(gofile)
+	out.File = filepath.Join(dir, *file)
+	out.Types = make(map[string]*marshalhash.Schema)
+	parseFile := parse.File
+	if *typecheck {
+		parseFile = parse.TypedFile
+	}
+	err := inDir(dir, func() error {
+		fs, err := parseFile(*file, *unexported)
+		if err != nil {
+			return err
+		}
+		out.Package = fs.Package
+		out.Nesting = "bin"
+		if fs.NestInline {
+			out.Nesting = "inline"
+		}
+		out.Digests = fs.Digests
+
+		// the version lists are kept
+		// in the generated file
+		if _, err := setVersions(newFilename(*file, fs.Package), fs); err != nil {
+			return err
+		}
+		for name, el := range fs.Identities {
+			if s := gen.SchemaOf(el, fs.NestInline); s != nil {
+				out.Types[name] = s
+			}
+		}
+		return nil
+	})
+	return out, err
+}
@@ -0,0 +1,34 @@
+package marshalhash
+
+import "sync/atomic"
+
+// DigestCache holds the digest of the struct
+// it is declared in, see the //hsp:cache directive.
+// The zero value is an empty cache. It is never
+// hashed, and may be read concurrently, but the
+// struct must not be mutated while it is read.
+type DigestCache struct {
+	v atomic.Value // *[32]byte, nil when invalid
+}
+
+// Load returns the cached digest,
+// and whether it is valid.
+func (c *DigestCache) Load() (d [32]byte, ok bool) {
+	p, _ := c.v.Load().(*[32]byte)
+	if p == nil {
+		return d, false
+	}
+	return *p, true
+}
+
+// Store caches the digest d.
+func (c *DigestCache) Store(d [32]byte) {
+	c.v.Store(&d)
+}
+
+// Invalidate drops the cached digest.
+func (c *DigestCache) Invalidate() {
+	if c.v.Load() != nil {
+		c.v.Store((*[32]byte)(nil))
+	}
+}
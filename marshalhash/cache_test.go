@@ -0,0 +1,27 @@
+package marshalhash
+
+import (
+	"testing"
+)
+
+func TestDigestCache(t *testing.T) {
+	var c DigestCache
+	if _, ok := c.Load(); ok {
+		t.Fatal("empty cache is valid")
+	}
+	c.Invalidate()
+
+	d := [32]byte{0x01, 0x02}
+	c.Store(d)
+	if got, ok := c.Load(); !ok || got != d {
+		t.Fatalf("Load() = %x, %v; want %x, true", got, ok, d)
+	}
+	c.Invalidate()
+	if _, ok := c.Load(); ok {
+		t.Fatal("cache is valid after Invalidate()")
+	}
+	c.Store(d)
+	if _, ok := c.Load(); !ok {
+		t.Fatal("cache is invalid after Store()")
+	}
+}
@@ -0,0 +1,124 @@
+package marshalhash
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Difference is a part of two encodings
+// of the same type that is not equal
+type Difference struct {
+	Path string // e.g. Block.Txs[1] or S.Which["ss"]
+	Type string // Go type
+	A, B string // formatted values, "" if missing
+}
+
+// Diff walks a and b, two encodings of the type typ
+// described by fs, in parallel and returns the parts
+// that differ: the primitives with different values,
+// and the fields, elements and map entries found in a
+// single one, in the order they are written. The parts
+// of equal bytes are not walked any further. It returns
+// an error if a or b doesn't match the schema.
+func Diff(fs *FileSchema, typ string, a, b []byte) ([]Difference, error) {
+	na, err := schemaNodes(fs, typ, a)
+	if err != nil {
+		return nil, fmt.Errorf("a: %v", err)
+	}
+	nb, err := schemaNodes(fs, typ, b)
+	if err != nil {
+		return nil, fmt.Errorf("b: %v", err)
+	}
+	if len(na) == 0 || len(nb) == 0 {
+		return nil, nil
+	}
+	return diffNodes(na, 0, nb, 0, nil), nil
+}
+
+// diffNodes appends the differences between na[i]
+// and nb[j], the same part of a and b, to diffs
+func diffNodes(na []*schemaNode, i int, nb []*schemaNode, j int, diffs []Difference) []Difference {
+	a, b := na[i], nb[j]
+	switch {
+	case bytes.Equal(a.Raw, b.Raw):
+		// same bytes, same parts
+		return diffs
+	case isLeaf(na, i) || isLeaf(nb, j):
+		return append(diffs, Difference{Path: a.Path, Type: a.Type, A: a.Value, B: b.Value})
+	}
+
+	// merge the children, found in either or both,
+	// keeping the order in which they are written
+	ca, cb := children(na, i), children(nb, j)
+	inA, inB := byPath(na, ca), byPath(nb, cb)
+	matched := make(map[string]bool)
+	for x, y := 0, 0; x < len(ca) || y < len(cb); {
+		if y < len(cb) && matched[nb[cb[y]].Path] {
+			y++
+			continue
+		}
+		if x < len(ca) {
+			if n := na[ca[x]]; !has(inB, n.Path) {
+				diffs = append(diffs, Difference{Path: n.Path, Type: n.Type, A: n.Value})
+				x++
+				continue
+			}
+		}
+		if y < len(cb) {
+			if n := nb[cb[y]]; !has(inA, n.Path) {
+				diffs = append(diffs, Difference{Path: n.Path, Type: n.Type, B: n.Value})
+				y++
+				continue
+			}
+		}
+		n := na[ca[x]]
+		diffs = diffNodes(na, ca[x], nb, inB[n.Path], diffs)
+		matched[n.Path] = true
+		x++
+	}
+	return diffs
+}
+
+// children returns the indexes of
+// the direct children of nodes[i]
+func children(nodes []*schemaNode, i int) []int {
+	var c []int
+	for k := i + 1; k < len(nodes) && nodes[k].Depth > nodes[i].Depth; k++ {
+		if nodes[k].Depth == nodes[i].Depth+1 {
+			c = append(c, k)
+		}
+	}
+	return c
+}
+
+// byPath returns the indexes of
+// the nodes of idx by their path
+func byPath(nodes []*schemaNode, idx []int) map[string]int {
+	m := make(map[string]int, len(idx))
+	for _, i := range idx {
+		m[nodes[i].Path] = i
+	}
+	return m
+}
+
+// schemaNodes returns the parts of b,
+// the encoding of typ, in pre-order
+func schemaNodes(fs *FileSchema, typ string, b []byte) ([]*schemaNode, error) {
+	var nodes []*schemaNode
+	err := walkSchema(fs, typ, b, func(n *schemaNode) error {
+		nodes = append(nodes, n)
+		return nil
+	})
+	return nodes, err
+}
+
+// isLeaf returns whether nodes[i] has no children
+func isLeaf(nodes []*schemaNode, i int) bool {
+	return i+1 == len(nodes) || nodes[i+1].Depth <= nodes[i].Depth
+}
+
+// has returns whether path is a key of m
+func has(m map[string]int, path string) bool {
+	_, ok := m[path]
+	return ok
+}
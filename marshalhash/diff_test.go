@@ -0,0 +1,72 @@
+package marshalhash
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// appendBlock writes a Block of testSchema
+func appendBlock(b []byte, note string, txs [][]byte, weight uint32, hash byte) []byte {
+	header := AppendMapHeader(nil, 1)
+	if note != "" {
+		header = AppendMapHeader(nil, 2)
+	}
+	header = AppendInt64(header, 7)
+	if note != "" {
+		header = AppendMapHeader(header, 1)
+		header = AppendString(header, "1")
+		header = AppendString(header, note)
+	}
+
+	b = AppendMapHeader(b, 4)
+	b = AppendBytes(b, header)
+	b = AppendArrayHeader(b, uint32(len(txs)))
+	for _, tx := range txs {
+		b = AppendBytes(b, tx)
+	}
+	b = AppendMapHeader(b, 1)
+	b = AppendString(b, "ss")
+	b = AppendUint32(b, weight)
+	b = AppendBytes(b, []byte{0x01, hash})
+	return b
+}
+
+func TestDiff(t *testing.T) {
+	txs := [][]byte{{0xca, 0xfe}, nil}
+	a := appendBlock(nil, "genesis", txs, 3, 0x02)
+	for _, c := range []struct {
+		b    []byte
+		want []Difference
+	}{
+		{a, nil},
+		{appendBlock(nil, "genesis", txs, 4, 0x02), []Difference{
+			{Path: `Block.Weights["ss"]`, Type: "uint32", A: "3", B: "4"},
+		}},
+		{appendBlock(nil, "", txs[:1], 3, 0x03), []Difference{
+			{Path: "Block.Header.Note", Type: "*string", A: `"genesis"`},
+			{Path: "Block.Txs[1]", Type: "[]byte", A: "0x"},
+			{Path: "Block.Hash", Type: "[2]byte", A: "0x0102", B: "0x0103"},
+		}},
+		{appendBlock(nil, "genesis", append(txs, []byte{0x01}), 3, 0x02), []Difference{
+			{Path: "Block.Txs[2]", Type: "[]byte", B: "0x01"},
+		}},
+		// parts found in b only keep their place
+		{appendBlock(nil, "genesis", append(txs, []byte{0x01}), 3, 0x03), []Difference{
+			{Path: "Block.Txs[2]", Type: "[]byte", B: "0x01"},
+			{Path: "Block.Hash", Type: "[2]byte", A: "0x0102", B: "0x0103"},
+		}},
+	} {
+		got, err := Diff(testSchema(), "Block", a, c.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("got %+v, want %+v", got, c.want)
+		}
+	}
+
+	if _, err := Diff(testSchema(), "Block", a, a[:len(a)-1]); err == nil || !strings.HasPrefix(err.Error(), "b: ") {
+		t.Errorf("got error %v for a truncated encoding", err)
+	}
+}
@@ -0,0 +1,30 @@
+package marshalhash
+
+// The structs given a domain by the //hsp:domain
+// directive start with it, so that two types with
+// the same fields, e.g. a transaction and a vote,
+// don't have the same encoding, and a signature of
+// one can't be used for the other. The domain is
+// the first object counted by the header of the
+// struct, so that a struct written inline in another
+// remains one object.
+
+// AppendDomain appends the domain of a struct,
+// written after its header, as a string.
+func AppendDomain(b []byte, domain string) []byte {
+	return AppendString(b, domain)
+}
+
+// ReadDomainBytes reads the domain written by
+// AppendDomain, and returns a DomainError if
+// it is not domain.
+func ReadDomainBytes(b []byte, domain string) (o []byte, err error) {
+	var got []byte
+	if got, o, err = ReadStringZC(b); err != nil {
+		return b, err
+	}
+	if string(got) != domain {
+		return b, DomainError{Wanted: domain, Got: string(got)}
+	}
+	return o, nil
+}
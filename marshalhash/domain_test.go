@@ -0,0 +1,26 @@
+package marshalhash
+
+import (
+	"testing"
+)
+
+func TestReadDomainBytes(t *testing.T) {
+	b := AppendDomain(nil, "covenantsql/tx/v1")
+	b = AppendInt64(b, 0)
+
+	o, err := ReadDomainBytes(b, "covenantsql/tx/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(o) != 1 {
+		t.Errorf("got %d bytes left, want 1", len(o))
+	}
+
+	_, err = ReadDomainBytes(b, "covenantsql/vote/v1")
+	if derr, ok := err.(DomainError); !ok || derr.Got != "covenantsql/tx/v1" {
+		t.Errorf("got error %v, want a DomainError", err)
+	}
+	if _, err = ReadDomainBytes(AppendInt64(nil, 0), "covenantsql/tx/v1"); err == nil {
+		t.Error("read a domain from an int")
+	}
+}
@@ -0,0 +1,436 @@
+package marshalhash
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// schemaNode is a part of an encoding
+// named by its schema, see walkSchema
+type schemaNode struct {
+	Offset int    // in the whole encoding
+	Depth  int    // of nesting
+	Path   string // e.g. Block.Txs[1] or S.Which["ss"]
+	Name   string // last element of Path
+	Type   string // Go type
+	Value  string // formatted value, or header
+	Raw    []byte // encoding, including the children
+}
+
+// schemaWalker walks an encoding
+// as the types of a FileSchema
+type schemaWalker struct {
+	fs    *FileSchema
+	visit func(n *schemaNode) error
+}
+
+// walkSchema calls visit with the parts of b, the
+// encoding of the type typ of fs, in the order they
+// are written, and returns an error if b doesn't
+// match the schema or visit fails.
+func walkSchema(fs *FileSchema, typ string, b []byte, visit func(n *schemaNode) error) error {
+	s := fs.Lookup(typ)
+	if s == nil {
+		return fmt.Errorf("hsp: no schema for type %s", typ)
+	}
+	w := &schemaWalker{fs: fs, visit: visit}
+	o, err := w.walk(s, b, len(b), &schemaNode{Path: typ, Name: typ})
+	if err == nil && len(o) > 0 {
+		err = fmt.Errorf("hsp: %d bytes left over at offset %#x", len(o), len(b)-len(o))
+	}
+	return err
+}
+
+// child returns the node of a part of n
+func (n *schemaNode) child(name, path string) *schemaNode {
+	return &schemaNode{Depth: n.Depth + 1, Path: n.Path + path, Name: name}
+}
+
+// walk visits the part of b written as s, whose
+// node is n, and returns the remaining bytes; end
+// is the offset of the end of b in the encoding
+func (w *schemaWalker) walk(s *Schema, b []byte, end int, n *schemaNode) (o []byte, err error) {
+	n.Offset = end - len(b)
+	if n.Type == "" {
+		n.Type = schemaType(s)
+	}
+	defer func() {
+		if err != nil {
+			return
+		}
+		n.Raw = b[:len(b)-len(o)]
+	}()
+	if IsNil(b) && (s.Kind == "pointer" || s.Kind == "primitive" || s.Nesting == "intf") {
+		// nil pointers, zero times
+		// and nil interfaces
+		n.Value = "nil"
+		o, err = ReadNilBytes(b)
+		return o, w.leaf(n, err)
+	}
+
+	switch s.Kind {
+	case "primitive":
+		if err = checkEncoding(s.Encoding, b); err != nil {
+			return b, w.leaf(n, err)
+		}
+		n.Value, o, err = formatPrimitive(s.Encoding, b)
+		return o, w.leaf(n, err)
+	case "pointer":
+		return w.walk(s.Elem, b, end, n)
+	case "named":
+		return w.named(s, b, end, n)
+	case "slice", "array":
+		return w.array(s, b, end, n)
+	case "merkle":
+		var root [32]byte
+		root, o, err = ReadMerkleRootBytes(b)
+		n.Value = fmt.Sprintf("root 0x%x", root)
+		return o, w.leaf(n, err)
+	case "map":
+		return w.mapEntries(s, b, end, n)
+	case "struct":
+		return w.structFields(s, b, end, n)
+	}
+	return b, fmt.Errorf("hsp: %s: unknown schema kind %q", n.Path, s.Kind)
+}
+
+// leaf visits n, a node without children,
+// if err, returned by its decoding, is nil
+func (w *schemaWalker) leaf(n *schemaNode, err error) error {
+	if err != nil {
+		return fmt.Errorf("hsp: %s at offset %#x: %v", n.Path, n.Offset, err)
+	}
+	return w.visit(n)
+}
+
+func (w *schemaWalker) named(s *Schema, b []byte, end int, n *schemaNode) ([]byte, error) {
+	inner := w.fs.Lookup(s.Type)
+	if s.Nesting == "inline" {
+		if inner == nil {
+			return b, fmt.Errorf("hsp: %s: no schema for type %s", n.Path, s.Type)
+		}
+		return w.walk(inner, b, end, n)
+	}
+	v, o, err := ReadBytesZC(b)
+	if err != nil || inner == nil || s.Nesting == "intf" {
+		// unknown types are
+		// written as their bytes
+		n.Value = fmt.Sprintf("bin(%d) 0x%x", len(v), v)
+		return o, w.leaf(n, err)
+	}
+	// the walk starts over in the
+	// 'bin' object, after its prefix
+	if _, err = w.walk(inner, v, end-len(o), n); err != nil {
+		return b, err
+	}
+	return o, nil
+}
+
+func (w *schemaWalker) array(s *Schema, b []byte, end int, n *schemaNode) (o []byte, err error) {
+	if s.Encoding == "Bytes" {
+		var v []byte
+		v, o, err = ReadBytesZC(b)
+		n.Value = fmt.Sprintf("0x%x", v)
+		return o, w.leaf(n, err)
+	}
+	var sz uint32
+	sz, o, err = ReadArrayHeaderBytes(b)
+	n.Value = fmt.Sprintf("array(%d)", sz)
+	if err = w.leaf(n, err); err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		idx := "[" + strconv.FormatUint(uint64(i), 10) + "]"
+		if o, err = w.walk(s.Elem, o, end, n.child(idx, idx)); err != nil {
+			return b, err
+		}
+	}
+	return o, nil
+}
+
+func (w *schemaWalker) mapEntries(s *Schema, b []byte, end int, n *schemaNode) (o []byte, err error) {
+	var sz uint32
+	sz, o, err = ReadMapHeaderBytes(b)
+	n.Value = fmt.Sprintf("map(%d)", sz)
+	if err = w.leaf(n, err); err != nil {
+		return b, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		var key string
+		key, o, err = formatPrimitive("", o)
+		if err != nil {
+			return b, fmt.Errorf("hsp: %s: key %d at offset %#x: %v", n.Path, i, end-len(o), err)
+		}
+		idx := "[" + key + "]"
+		if o, err = w.walk(s.Elem, o, end, n.child(idx, idx)); err != nil {
+			return b, err
+		}
+	}
+	return o, nil
+}
+
+func (w *schemaWalker) structFields(s *Schema, b []byte, end int, n *schemaNode) (o []byte, err error) {
+	var sz uint32
+	sz, o, err = readStructHeader(s, b)
+	if s.Domain != "" {
+		// counted by the header
+		sz++
+	}
+	if s.Mode == "tuple" {
+		n.Value = fmt.Sprintf("array(%d)", sz)
+	} else {
+		n.Value = fmt.Sprintf("map(%d)", sz)
+	}
+	if s.Domain != "" {
+		n.Value = fmt.Sprintf("%s %q", n.Value, s.Domain)
+		sz--
+	}
+	if err = w.leaf(n, err); err != nil {
+		return b, err
+	}
+	var werr error // of the fields, already located
+	o, err = eachField(s, sz, o, func(f SchemaField, o []byte) ([]byte, bool, error) {
+		o, werr = w.walk(f.Type, o, end, n.child(f.Name, "."+f.Name))
+		return o, werr != nil, nil
+	})
+	switch {
+	case werr != nil:
+		return b, werr
+	case err != nil:
+		return b, fmt.Errorf("hsp: %s at offset %#x: %v", n.Path, n.Offset, err)
+	}
+	return o, nil
+}
+
+// readStructHeader reads the header and the
+// domain of the struct s at the start of b, and
+// returns the number of objects following them
+func readStructHeader(s *Schema, b []byte) (uint32, []byte, error) {
+	var sz uint32
+	var o []byte
+	var err error
+	if s.Mode == "tuple" {
+		sz, o, err = ReadArrayHeaderBytes(b)
+	} else {
+		sz, o, err = ReadMapHeaderBytes(b)
+	}
+	if err != nil || s.Domain == "" {
+		return sz, o, err
+	}
+	if sz == 0 {
+		return 0, b, ArrayError{Wanted: 1, Got: 0}
+	}
+	if o, err = ReadDomainBytes(o, s.Domain); err != nil {
+		return 0, b, err
+	}
+	return sz - 1, o, nil
+}
+
+// eachField calls fn with the fields of the struct s
+// written in b, following its header holding sz, and
+// the bytes starting with their value, in the order
+// they are written: the plain fields, then the ones
+// tagged omitempty that aren't empty, in a trailing
+// map from their tag to their value counted as one
+// more field by the header. fn returns the bytes
+// following the value, and whether to stop there.
+func eachField(s *Schema, sz uint32, b []byte, fn func(f SchemaField, o []byte) ([]byte, bool, error)) ([]byte, error) {
+	plain, omitted := 0, 0
+	for _, f := range s.Fields {
+		if f.OmitEmpty {
+			omitted++
+		} else {
+			plain++
+		}
+	}
+	want := plain
+	if omitted > 0 {
+		want++
+	}
+	if int(sz) < plain || int(sz) > want {
+		return b, fmt.Errorf("%d fields, want %d to %d", sz, plain, want)
+	}
+
+	o := b
+	var stop bool
+	var err error
+	for _, f := range s.Fields {
+		if f.OmitEmpty {
+			continue
+		}
+		if o, stop, err = fn(f, o); stop || err != nil {
+			return o, err
+		}
+	}
+	if int(sz) == plain {
+		return o, nil
+	}
+
+	var set uint32
+	if set, o, err = ReadMapHeaderBytes(o); err != nil {
+		return o, err
+	}
+	if set == 0 || int(set) > omitted {
+		return o, fmt.Errorf("%d omitempty fields, want 1 to %d", set, omitted)
+	}
+	for _, f := range s.Fields {
+		if !f.OmitEmpty || set == 0 {
+			continue
+		}
+		// the tags are written in the order of the fields
+		tag, rest, err := ReadStringZC(o)
+		if err != nil {
+			return o, err
+		}
+		if string(tag) != f.Tag {
+			continue
+		}
+		set--
+		if o, stop, err = fn(f, rest); stop || err != nil {
+			return o, err
+		}
+	}
+	if set != 0 {
+		return o, fmt.Errorf("%d unknown omitempty fields", set)
+	}
+	return o, nil
+}
+
+// schemaType returns the Go type of s,
+// or its kind if it is not known
+func schemaType(s *Schema) string {
+	switch {
+	case s == nil:
+		return "?"
+	case s.Type != "":
+		return s.Type
+	case s.Kind == "pointer":
+		return "*" + schemaType(s.Elem)
+	}
+	return s.Kind
+}
+
+// checkEncoding returns a TypeError if the object at
+// the start of b can't be written by hsp.Append{encoding},
+// e.g. when b is the encoding of another version of the
+// type whose fields have other types
+func checkEncoding(encoding string, b []byte) error {
+	var want []Type
+	switch encoding {
+	case "String":
+		want = []Type{StrType}
+	case "Bytes":
+		want = []Type{BinType}
+	case "Bool":
+		want = []Type{BoolType}
+	case "Int", "Int8", "Int16", "Int32", "Int64",
+		"Uint", "Uint8", "Uint16", "Uint32", "Uint64", "Byte":
+		// small values of either are fixints
+		want = []Type{IntType, UintType}
+	case "Float32":
+		want = []Type{Float32Type}
+	case "Float64", "Float32Canonical", "Float64Canonical":
+		want = []Type{Float64Type}
+	case "Float32Integral", "Float64Integral":
+		want = []Type{Float64Type, IntType, UintType}
+	case "Complex64":
+		want = []Type{Complex64Type}
+	case "Complex128":
+		want = []Type{Complex128Type}
+	case "Time":
+		want = []Type{TimeType}
+	default:
+		// interfaces, extensions and
+		// times read by formatPrimitive
+		return nil
+	}
+	if len(b) == 0 {
+		return ErrShortBytes
+	}
+	got := NextType(b)
+	for _, t := range want {
+		if got == t {
+			return nil
+		}
+	}
+	if got == InvalidType {
+		return InvalidPrefixError(b[0])
+	}
+	return TypeError{Method: want[0], Encoded: got}
+}
+
+// formatPrimitive reads the object at the start of
+// b, written by hsp.Append{encoding}, or of any
+// type if encoding is "", and formats its value
+func formatPrimitive(encoding string, b []byte) (string, []byte, error) {
+	if strings.HasPrefix(encoding, "Time") && encoding != "Time" {
+		var t time.Time
+		var o []byte
+		var err error
+		switch encoding {
+		case "TimeUnix":
+			t, o, err = ReadTimeUnixBytes(b)
+		case "TimeUnixMilli":
+			t, o, err = ReadTimeUnixMilliBytes(b)
+		case "TimeUnixMicro":
+			t, o, err = ReadTimeUnixMicroBytes(b)
+		case "TimeRFC3339":
+			t, o, err = ReadTimeRFC3339Bytes(b)
+		default:
+			return "", b, fmt.Errorf("unknown encoding %s", encoding)
+		}
+		return formatTime(t), o, err
+	}
+	i, o, err := ReadIntfBytes(b)
+	if err != nil {
+		return "", b, err
+	}
+	switch i := i.(type) {
+	case nil:
+		return "nil", o, nil
+	case string:
+		return strconv.Quote(i), o, nil
+	case []byte:
+		return fmt.Sprintf("0x%x", i), o, nil
+	case time.Time:
+		return formatTime(i), o, nil
+	case map[string]interface{}, []interface{}:
+		// interface{} values
+		return FormatValue(i), o, nil
+	}
+	return fmt.Sprint(i), o, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "time{}"
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// Dump writes the tree of the parts of b, the encoding
+// of the type typ described by fs, e.g. as read from the
+// output of 'hsp schema': a line per field, element or
+// map entry, with its offset in b, its name, Go type
+// and value. The nested types that fs doesn't describe,
+// e.g. the ones of other packages, are written as the
+// bytes of their encoding. An object of another kind
+// than the schema says, e.g. in the encoding of another
+// version of typ, is an error. The parts of b before an
+// error are written as well.
+func Dump(w io.Writer, fs *FileSchema, typ string, b []byte) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	err := walkSchema(fs, typ, b, func(n *schemaNode) error {
+		_, err := fmt.Fprintf(tw, "%06x\t%s%s\t%s\t%s\n",
+			n.Offset, strings.Repeat("  ", n.Depth), n.Name, n.Type, n.Value)
+		return err
+	})
+	if ferr := tw.Flush(); err == nil {
+		err = ferr
+	}
+	return err
+}
@@ -0,0 +1,107 @@
+package marshalhash
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// the schema of
+//
+//	type Header struct {
+//		Height int64   `hsp:"0"`
+//		Note   *string `hsp:"1,omitempty"`
+//	}
+//
+//	type Block struct {
+//		Header  Header            `hsp:"0"`
+//		Txs     [][]byte          `hsp:"1"`
+//		Weights map[string]uint32 `hsp:"2"`
+//		Hash    [2]byte           `hsp:"3"`
+//	}
+func testSchema() *FileSchema {
+	prim := func(typ, enc string) *Schema {
+		return &Schema{Kind: "primitive", Type: typ, Encoding: enc}
+	}
+	return &FileSchema{
+		Package: "test",
+		Nesting: "bin",
+		Types: map[string]*Schema{
+			"Header": {Kind: "struct", Type: "Header", Mode: "map", Fields: []SchemaField{
+				{Name: "Height", Tag: "0", Type: prim("int64", "Int64")},
+				{Name: "Note", Tag: "1", OmitEmpty: true, Type: &Schema{Kind: "pointer", Elem: prim("string", "String")}},
+			}},
+			"Block": {Kind: "struct", Type: "Block", Mode: "map", Fields: []SchemaField{
+				{Name: "Header", Tag: "0", Type: &Schema{Kind: "named", Type: "Header", Nesting: "bin"}},
+				{Name: "Txs", Tag: "1", Type: &Schema{Kind: "slice", Type: "[][]byte", Elem: prim("[]byte", "Bytes")}},
+				{Name: "Weights", Tag: "2", Type: &Schema{Kind: "map", Type: "map[string]uint32", KeyOrder: "string",
+					Key: prim("string", "String"), Elem: prim("uint32", "Uint32")}},
+				{Name: "Hash", Tag: "3", Type: &Schema{Kind: "array", Type: "[2]byte", Size: "2", Encoding: "Bytes",
+					Elem: prim("byte", "Byte")}},
+			}},
+		},
+	}
+}
+
+func testBlock() []byte {
+	header := AppendMapHeader(nil, 2)
+	header = AppendInt64(header, 7)
+	header = AppendMapHeader(header, 1)
+	header = AppendString(header, "1")
+	header = AppendString(header, "genesis")
+
+	b := AppendMapHeader(nil, 4)
+	b = AppendBytes(b, header)
+	b = AppendArrayHeader(b, 2)
+	b = AppendBytes(b, []byte{0xca, 0xfe})
+	b = AppendBytes(b, nil)
+	b = AppendMapHeader(b, 1)
+	b = AppendString(b, "ss")
+	b = AppendUint32(b, 3)
+	b = AppendBytes(b, []byte{0x01, 0x02})
+	return b
+}
+
+func TestDump(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Dump(&buf, testSchema(), "Block", testBlock()); err != nil {
+		t.Fatal(err)
+	}
+	want := `
+000000  Block       Block              map(4)
+000003    Header    Header             map(2)
+000004      Height  int64              7
+000008      Note    *string            "genesis"
+000010    Txs       [][]byte           array(2)
+000011      [0]     []byte             0xcafe
+000015      [1]     []byte             0x
+000017    Weights   map[string]uint32  map(1)
+00001b      ["ss"]  uint32             3
+00001c    Hash      [2]byte            0x0102
+`
+	if got := "\n" + buf.String(); got != want {
+		t.Errorf("got:%s\nwant:%s", got, want)
+	}
+}
+
+func TestDumpErrors(t *testing.T) {
+	block := testBlock()
+	for _, c := range []struct {
+		typ  string
+		b    []byte
+		want string
+	}{
+		{"Nope", block, "no schema for type Nope"},
+		{"Block", block[:len(block)-1], "Block.Hash at offset 0x1c"},
+		{"Block", append(block, 0xc0), "1 bytes left over at offset 0x20"},
+		{"Header", AppendMapHeader(nil, 3), "Header at offset 0x0: 3 fields, want 1 to 2"},
+		// e.g. the encoding of another version
+		{"Header", AppendString(AppendMapHeader(nil, 1), "7"), `Header.Height at offset 0x1: hsp: attempted to decode type "str" with method for "int"`},
+	} {
+		var buf bytes.Buffer
+		err := Dump(&buf, testSchema(), c.typ, c.b)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: got error %v, want %q", c.typ, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package marshalhash
+
+import (
+	"bytes"
+	"time"
+)
+
+// HashMarshaler is the interface implemented
+// by the types generated by hsp.
+type HashMarshaler interface {
+	MarshalHash() ([]byte, error)
+}
+
+// EqualHash reports whether a and b have the
+// same MarshalHash output. It is used by the
+// generated EqualHash methods for the types
+// declared in other packages. Errors compare
+// unequal.
+func EqualHash(a, b HashMarshaler) bool {
+	ab, err := a.MarshalHash()
+	if err != nil {
+		return false
+	}
+	bb, err := b.MarshalHash()
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ab, bb)
+}
+
+// EqualIntf reports whether a and b
+// have the same AppendIntfHash output.
+// Errors compare unequal.
+func EqualIntf(a, b interface{}) bool {
+	ab, err := AppendIntfHash(nil, a)
+	if err != nil {
+		return false
+	}
+	bb, err := AppendIntfHash(nil, b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ab, bb)
+}
+
+// EqualExtension reports whether a and b
+// have the same AppendExtension output.
+// Errors compare unequal.
+func EqualExtension(a, b Extension) bool {
+	ab, err := AppendExtension(nil, a)
+	if err != nil {
+		return false
+	}
+	bb, err := AppendExtension(nil, b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ab, bb)
+}
+
+// EqualFloatCanonical reports whether a and b
+// are written the same by AppendFloat64Canonical,
+// which is also true of AppendFloat64Integral:
+// all NaNs are equal, and -0 equals +0.
+func EqualFloatCanonical(a, b float64) bool {
+	return a == b || (a != a && b != b)
+}
+
+// EqualTimeAs reports whether a and b are
+// appended the same by f, e.g. by the time
+// encodings of time.go, which truncate them.
+func EqualTimeAs(a, b time.Time, f func([]byte, time.Time) []byte) bool {
+	return bytes.Equal(f(nil, a), f(nil, b))
+}
@@ -56,6 +56,22 @@ func (a ArrayError) Error() string {
 // Resumable is always 'true' for ArrayErrors
 func (a ArrayError) Resumable() bool { return true }
 
+// DomainError is returned when decoding a
+// type whose domain, see AppendDomain, is not
+// the one found
+type DomainError struct {
+	Wanted string
+	Got    string
+}
+
+// Error implements the error interface
+func (d DomainError) Error() string {
+	return fmt.Sprintf("hsp: wanted domain %q; got %q", d.Wanted, d.Got)
+}
+
+// Resumable is always 'true' for DomainErrors
+func (d DomainError) Resumable() bool { return true }
+
 // IntOverflow is returned when a call
 // would downcast an integer to a type
 // with too few bits to hold its value.
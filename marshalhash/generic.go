@@ -0,0 +1,70 @@
+package marshalhash
+
+import (
+	"reflect"
+)
+
+// The fields of generic types whose type is a
+// type parameter, constrained by HashMarshaler,
+// are written by AppendIntfHash: nil pointers as
+// nil, other values as a 'bin' object holding
+// their MarshalHash output, as the fields of the
+// types declared in other packages.
+
+// HashUnmarshaler is the interface implemented
+// by the types generated by hsp -unmarshal.
+type HashUnmarshaler interface {
+	UnmarshalHash([]byte) ([]byte, error)
+}
+
+// HashSize returns an upper bound of the size
+// of v as written by AppendIntfHash, using its
+// Msgsize method if it implements Sizer.
+func HashSize(v HashMarshaler) int {
+	if isNilPtr(v) {
+		return NilSize
+	}
+	if s, ok := v.(Sizer); ok {
+		return BytesPrefixSize + s.Msgsize()
+	}
+	bts, _ := v.MarshalHash()
+	return BytesPrefixSize + len(bts)
+}
+
+// ReadHashMarshalerBytes reads a value written
+// by AppendIntfHash for a HashMarshaler into the
+// value dst points to, which implements
+// HashUnmarshaler, or is a pointer which does,
+// allocated if nil. A nil object sets it to
+// its zero value.
+func ReadHashMarshalerBytes(b []byte, dst interface{}) (o []byte, err error) {
+	v := reflect.ValueOf(dst).Elem()
+	if IsNil(b) {
+		v.Set(reflect.Zero(v.Type()))
+		return ReadNilBytes(b)
+	}
+	var inner []byte
+	if inner, o, err = ReadBytesZC(b); err != nil {
+		return b, err
+	}
+	u, ok := dst.(HashUnmarshaler)
+	if !ok && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		u, ok = v.Interface().(HashUnmarshaler)
+	}
+	if !ok {
+		return b, &ErrUnsupportedType{T: v.Type()}
+	}
+	_, err = u.UnmarshalHash(inner)
+	return o, err
+}
+
+func isNilPtr(i interface{}) bool {
+	if i == nil {
+		return true
+	}
+	v := reflect.ValueOf(i)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,51 @@
+package marshalhash
+
+import (
+	"testing"
+)
+
+func (h *hashPtr) UnmarshalHash(b []byte) (o []byte, err error) {
+	h.s, o, err = ReadStringBytes(b)
+	return
+}
+
+func TestReadHashMarshalerBytes(t *testing.T) {
+	var p *hashPtr
+	b, err := AppendIntfHash(nil, &hashPtr{s: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := HashSize(&hashPtr{s: "x"}); n < len(b) {
+		t.Errorf("HashSize() = %d, encoded %d bytes", n, len(b))
+	}
+	// the nil pointer is allocated
+	left, err := ReadHashMarshalerBytes(b, &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 0 || p == nil || p.s != "x" {
+		t.Fatalf("decoded %v, %d bytes left", p, len(left))
+	}
+
+	// nil resets it
+	b, err = AppendIntfHash(nil, (*hashPtr)(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := HashSize((*hashPtr)(nil)); n != len(b) {
+		t.Errorf("HashSize(nil) = %d, encoded %d bytes", n, len(b))
+	}
+	if _, err = ReadHashMarshalerBytes(b, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p != nil {
+		t.Fatalf("decoded %v, want nil", p)
+	}
+
+	// hashValue has no UnmarshalHash
+	var v hashValue
+	b, _ = AppendIntfHash(nil, v)
+	if _, err = ReadHashMarshalerBytes(b, &v); err == nil {
+		t.Fatal("expected an unsupported type error")
+	}
+}
@@ -0,0 +1,222 @@
+package marshalhash
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GoldenUpdateEnv is the environment variable which,
+// set to 1, makes CheckGolden record the samples again
+// instead of reporting the changes.
+const GoldenUpdateEnv = "HSP_UPDATE_GOLDEN"
+
+// GoldenSample is a value checked by CheckGolden.
+type GoldenSample struct {
+	Name  string      // unique for the type
+	Value interface{} // recorded as text, see FormatValue
+	Hash  []byte      // MarshalHash output of Value
+}
+
+// TB is the part of testing.TB used by CheckGolden.
+type TB interface {
+	Helper()
+	Logf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+type goldenEntry struct {
+	Value string `json:"value"`
+	Hash  string `json:"hash"`
+}
+
+// CheckGolden compares the samples of the type typ with
+// the ones recorded in the JSON file, and reports an error
+// when the MarshalHash output of a sample changed, which
+// changes the hashes of the values already stored, or when
+// the sample itself changed. The samples missing from the
+// file are recorded, as are all of them when GoldenUpdateEnv
+// is set to 1. It is used by the tests generated with -golden.
+func CheckGolden(t TB, file, typ string, samples []GoldenSample) {
+	t.Helper()
+	entries := make(map[string]goldenEntry)
+	data, err := ioutil.ReadFile(file)
+	if err == nil {
+		err = json.Unmarshal(data, &entries)
+	} else if os.IsNotExist(err) {
+		err = nil
+	}
+	if err != nil {
+		t.Fatalf("%s: %s", file, err)
+	}
+
+	update := os.Getenv(GoldenUpdateEnv) == "1"
+	changed := false
+	if update {
+		// drop the samples that are gone
+		for key := range entries {
+			if strings.HasPrefix(key, typ+"/") {
+				delete(entries, key)
+				changed = true
+			}
+		}
+	}
+	for _, s := range samples {
+		key := typ + "/" + s.Name
+		got := goldenEntry{Value: FormatValue(s.Value), Hash: fmt.Sprintf("%x", s.Hash)}
+		want, ok := entries[key]
+		switch {
+		case ok && want == got:
+		case !ok:
+			entries[key] = got
+			changed = true
+			t.Logf("%s: recorded %s", file, key)
+		case want.Value != got.Value:
+			t.Errorf("%s: sample %s changed, set %s=1 to record it\nrecorded: %s\ncurrent:  %s",
+				file, key, GoldenUpdateEnv, want.Value, got.Value)
+		default:
+			t.Errorf("%s: hash of sample %s changed, set %s=1 to record it\nvalue:    %s\nrecorded: %s\ncurrent:  %s",
+				file, key, GoldenUpdateEnv, got.Value, want.Hash, got.Hash)
+		}
+	}
+	if !changed {
+		return
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "\t")
+	err = enc.Encode(entries)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(file), 0755)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(file, buf.Bytes(), 0644)
+	}
+	if err != nil {
+		t.Fatalf("%s: %s", file, err)
+	}
+}
+
+// FormatValue returns the text recorded for a value
+// by CheckGolden. Unlike the fmt package, it follows
+// pointers, and it doesn't depend on the location of
+// times or on the order of maps. Unexported struct
+// fields are left out.
+func FormatValue(i interface{}) string {
+	var buf bytes.Buffer
+	formatValue(&buf, reflect.ValueOf(i))
+	return buf.String()
+}
+
+func formatValue(w *bytes.Buffer, v reflect.Value) {
+	if !v.IsValid() {
+		w.WriteString("nil")
+		return
+	}
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			w.WriteString("time{}")
+		} else {
+			w.WriteString(t.UTC().Format(time.RFC3339Nano))
+		}
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		w.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		w.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		w.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32:
+		w.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 32))
+	case reflect.Float64:
+		w.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Complex64, reflect.Complex128:
+		fmt.Fprint(w, v.Complex())
+	case reflect.String:
+		w.WriteString(strconv.Quote(v.String()))
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			w.WriteString("nil")
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			w.WriteByte('&')
+		}
+		formatValue(w, v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			w.WriteString("nil")
+			return
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			w.WriteString("0x")
+			for i := 0; i < v.Len(); i++ {
+				fmt.Fprintf(w, "%02x", v.Index(i).Uint())
+			}
+			return
+		}
+		w.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			formatValue(w, v.Index(i))
+		}
+		w.WriteByte(']')
+	case reflect.Map:
+		if v.IsNil() {
+			w.WriteString("nil")
+			return
+		}
+		type entry struct{ k, v string }
+		entries := make([]entry, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			var kb, vb bytes.Buffer
+			formatValue(&kb, k)
+			formatValue(&vb, v.MapIndex(k))
+			entries = append(entries, entry{kb.String(), vb.String()})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].k < entries[j].k })
+		w.WriteString("map[")
+		for i, e := range entries {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteString(e.k + ": " + e.v)
+		}
+		w.WriteByte(']')
+	case reflect.Struct:
+		// unexported fields are not hashed
+		w.WriteByte('{')
+		n := 0
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			if n > 0 {
+				w.WriteString(", ")
+			}
+			n++
+			w.WriteString(f.Name + ": ")
+			formatValue(w, v.Field(i))
+		}
+		w.WriteByte('}')
+	default:
+		fmt.Fprintf(w, "<%s>", v.Type())
+	}
+}
@@ -0,0 +1,101 @@
+package marshalhash
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// goldenTB records the errors of CheckGolden
+type goldenTB struct {
+	errors []string
+}
+
+func (g *goldenTB) Helper()                                 {}
+func (g *goldenTB) Logf(format string, args ...interface{}) {}
+func (g *goldenTB) Errorf(format string, args ...interface{}) {
+	g.errors = append(g.errors, fmt.Sprintf(format, args...))
+}
+func (g *goldenTB) Fatalf(format string, args ...interface{}) { panic(fmt.Sprintf(format, args...)) }
+
+func TestCheckGolden(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "testdata", "x.golden")
+	os.Unsetenv(GoldenUpdateEnv)
+
+	check := func(samples ...GoldenSample) []string {
+		tb := &goldenTB{}
+		CheckGolden(tb, file, "T", samples)
+		return tb.errors
+	}
+	a := GoldenSample{Name: "a", Value: 1, Hash: []byte{1}}
+	b := GoldenSample{Name: "b", Value: "b", Hash: []byte{2}}
+
+	// recorded the first time
+	if errs := check(a); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if errs := check(a, b); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if errs := check(a, b); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+
+	a.Hash = []byte{3}
+	b.Value = "c"
+	if errs := check(a, b); len(errs) != 2 {
+		t.Fatalf("want 2 errors, got %q", errs)
+	}
+
+	os.Setenv(GoldenUpdateEnv, "1")
+	errs := check(a)
+	os.Unsetenv(GoldenUpdateEnv)
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if errs := check(a); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	// b was dropped by the update
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n\t\"T/a\": {\n\t\t\"value\": \"1\",\n\t\t\"hash\": \"03\"\n\t}\n}\n"
+	if string(data) != want {
+		t.Errorf("golden file:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	type inner struct {
+		B []byte
+		s string
+	}
+	loc := time.FixedZone("X", 3600)
+	v := struct {
+		P *inner
+		N *inner
+		M map[string]int
+		T time.Time
+		Z time.Time
+		I interface{}
+	}{
+		P: &inner{B: []byte{0xca, 0xfe}, s: "hidden"},
+		M: map[string]int{"b": 2, "a": 1},
+		T: time.Date(2018, 1, 1, 1, 0, 0, 5, loc),
+		I: []interface{}{1.5, nil},
+	}
+	want := `{P: &{B: 0xcafe}, N: nil, M: map["a": 1, "b": 2], T: 2018-01-01T00:00:00.000000005Z, Z: time{}, I: [1.5, nil]}`
+	if got := FormatValue(v); got != want {
+		t.Errorf("FormatValue:\n got %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,218 @@
+package marshalhash
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var hashMarshalerType = reflect.TypeOf((*HashMarshaler)(nil)).Elem()
+
+// AppendIntfHash appends the canonical encoding of
+// the concrete value of 'i', used by the generated
+// MarshalHash methods for interface{} fields. Unlike
+// AppendIntf, the output only depends on the value:
+//
+//   - nil is written as nil
+//   - a type with a MarshalHash method (on the value
+//     or on a pointer to it) is written as a 'bin' object
+//     holding the MarshalHash output, as a nested field
+//     of that type is; this takes precedence over
+//     hsp.Marshaler and hsp.Extension
+//   - a type satisfying the hsp.Marshaler or the
+//     hsp.Extension interface is written as AppendIntf does
+//   - time.Time is written as AppendTime does
+//   - other values are written by their reflect.Kind,
+//     so a named type is written as its underlying type:
+//     bools, strings, floats and complexes as such, all
+//     integers as int64 or uint64, byte slices and byte
+//     arrays as 'bin' objects, other slices and arrays as
+//     arrays, pointers as nil or as the value they point to
+//   - maps are written with sorted keys: string keys
+//     byte-wise, integer keys by value, and other keys,
+//     or keys with a MarshalHash method, by their encoding
+//   - structs, channels and funcs are rejected
+//     with an ErrUnsupportedType
+func AppendIntfHash(b []byte, i interface{}) ([]byte, error) {
+	if i == nil {
+		return AppendNil(b), nil
+	}
+
+	// unnamed types, which have no methods
+	switch i := i.(type) {
+	case bool:
+		return AppendBool(b, i), nil
+	case string:
+		return AppendString(b, i), nil
+	case []byte:
+		return AppendBytes(b, i), nil
+	case int:
+		return AppendInt64(b, int64(i)), nil
+	case int64:
+		return AppendInt64(b, i), nil
+	case uint64:
+		return AppendUint64(b, i), nil
+	case float64:
+		return AppendFloat64(b, i), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(i))
+		for k := range i {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = AppendMapHeader(b, uint32(len(keys)))
+		var err error
+		for _, k := range keys {
+			b = AppendString(b, k)
+			if b, err = AppendIntfHash(b, i[k]); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	case map[string]string:
+		keys := make([]string, 0, len(i))
+		for k := range i {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = AppendMapHeader(b, uint32(len(keys)))
+		for _, k := range keys {
+			b = AppendString(b, k)
+			b = AppendString(b, i[k])
+		}
+		return b, nil
+	case []interface{}:
+		b = AppendArrayHeader(b, uint32(len(i)))
+		var err error
+		for _, v := range i {
+			if b, err = AppendIntfHash(b, v); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	}
+
+	v := reflect.ValueOf(i)
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return AppendNil(b), nil
+	}
+	if m, ok := i.(HashMarshaler); ok {
+		return appendHashMarshaler(b, m)
+	}
+	if v.Kind() != reflect.Ptr && reflect.PtrTo(v.Type()).Implements(hashMarshalerType) {
+		// MarshalHash on a pointer receiver
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return appendHashMarshaler(b, p.Interface().(HashMarshaler))
+	}
+
+	switch i := i.(type) {
+	case Marshaler:
+		return i.MarshalMsg(b)
+	case Extension:
+		return AppendExtension(b, i)
+	case time.Time:
+		return AppendTime(b, i), nil
+	}
+
+	return appendValueHash(b, v)
+}
+
+func appendHashMarshaler(b []byte, m HashMarshaler) ([]byte, error) {
+	o, err := m.MarshalHash()
+	if err != nil {
+		return b, err
+	}
+	return AppendBytes(b, o), nil
+}
+
+// appendValueHash writes v by its kind, following
+// the rules of AppendIntfHash; the types with
+// methods have been handled by the caller
+func appendValueHash(b []byte, v reflect.Value) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		return AppendBool(b, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return AppendInt64(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return AppendUint64(b, v.Uint()), nil
+	case reflect.Float32:
+		return AppendFloat32(b, float32(v.Float())), nil
+	case reflect.Float64:
+		return AppendFloat64(b, v.Float()), nil
+	case reflect.Complex64:
+		return AppendComplex64(b, complex64(v.Complex())), nil
+	case reflect.Complex128:
+		return AppendComplex128(b, v.Complex()), nil
+	case reflect.String:
+		return AppendString(b, v.String()), nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			bts := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(bts), v)
+			return AppendBytes(b, bts), nil
+		}
+		l := v.Len()
+		b = AppendArrayHeader(b, uint32(l))
+		var err error
+		for i := 0; i < l; i++ {
+			if b, err = AppendIntfHash(b, v.Index(i).Interface()); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return AppendNil(b), nil
+		}
+		return AppendIntfHash(b, v.Elem().Interface())
+	case reflect.Map:
+		return appendMapHash(b, v)
+	default:
+		return b, &ErrUnsupportedType{T: v.Type()}
+	}
+}
+
+// appendMapHash writes the entries
+// of v in canonical key order
+func appendMapHash(b []byte, v reflect.Value) ([]byte, error) {
+	type entry struct {
+		key reflect.Value
+		enc []byte
+	}
+	keys := v.MapKeys()
+	entries := make([]entry, len(keys))
+	var err error
+	for i, k := range keys {
+		entries[i].key = k
+		if entries[i].enc, err = AppendIntfHash(nil, k.Interface()); err != nil {
+			return b, err
+		}
+	}
+
+	kt := v.Type().Key()
+	var less func(i, j int) bool
+	switch kt.Kind() {
+	case reflect.String:
+		less = func(i, j int) bool { return entries[i].key.String() < entries[j].key.String() }
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less = func(i, j int) bool { return entries[i].key.Int() < entries[j].key.Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		less = func(i, j int) bool { return entries[i].key.Uint() < entries[j].key.Uint() }
+	}
+	if less == nil || kt.Implements(hashMarshalerType) || reflect.PtrTo(kt).Implements(hashMarshalerType) {
+		less = func(i, j int) bool { return bytes.Compare(entries[i].enc, entries[j].enc) < 0 }
+	}
+	sort.Slice(entries, less)
+
+	b = AppendMapHeader(b, uint32(len(entries)))
+	for _, e := range entries {
+		b = append(b, e.enc...)
+		if b, err = AppendIntfHash(b, v.MapIndex(e.key).Interface()); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
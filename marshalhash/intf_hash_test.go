@@ -0,0 +1,107 @@
+package marshalhash
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type hashValue struct{ n int64 }
+
+func (h hashValue) MarshalHash() ([]byte, error) { return AppendInt64(nil, h.n), nil }
+
+// MarshalMsg must not be used
+func (h hashValue) MarshalMsg(b []byte) ([]byte, error) { return AppendNil(b), nil }
+
+type hashPtr struct{ s string }
+
+func (h *hashPtr) MarshalHash() ([]byte, error) { return AppendString(nil, h.s), nil }
+
+type named string
+
+type namedBytes [4]byte
+
+func TestAppendIntfHashStable(t *testing.T) {
+	m := map[string]interface{}{}
+	ints := map[int]string{}
+	nested := map[named]interface{}{}
+	for i := 0; i < 64; i++ {
+		m[string(rune('a'+i))] = i
+		ints[i*7-200] = "v"
+		nested[named(rune('A'+i))] = map[string]string{"x": "y", "z": "w"}
+	}
+	v := []interface{}{m, ints, nested}
+
+	want, err := AppendIntfHash(nil, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 32; i++ {
+		got, err := AppendIntfHash(nil, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatal("AppendIntfHash output is not stable")
+		}
+	}
+}
+
+func TestAppendIntfHashRules(t *testing.T) {
+	inner := AppendInt64(nil, 7)
+	ptrInner := AppendString(nil, "s")
+	tm := time.Unix(1500000000, 1)
+
+	cases := []struct {
+		name string
+		in   interface{}
+		want []byte
+	}{
+		{"nil", nil, AppendNil(nil)},
+		{"nil pointer", (*hashPtr)(nil), AppendNil(nil)},
+		{"MarshalHash over MarshalMsg", hashValue{7}, AppendBytes(nil, inner)},
+		{"MarshalHash on pointer receiver", hashPtr{"s"}, AppendBytes(nil, ptrInner)},
+		{"pointer with MarshalHash", &hashPtr{"s"}, AppendBytes(nil, ptrInner)},
+		{"named string", named("x"), AppendString(nil, "x")},
+		{"int8", int8(-3), AppendInt64(nil, -3)},
+		{"uint16", uint16(300), AppendUint64(nil, 300)},
+		{"float32", float32(1.5), AppendFloat32(nil, 1.5)},
+		{"byte array", namedBytes{1, 2, 3, 4}, AppendBytes(nil, []byte{1, 2, 3, 4})},
+		{"time", tm, AppendTime(nil, tm)},
+		{"zero time", time.Time{}, AppendNil(nil)},
+		{"pointer", &tm, AppendTime(nil, tm)},
+		{"slice", []int{1, 2}, AppendInt64(AppendInt64(AppendArrayHeader(nil, 2), 1), 2)},
+		{
+			"string keys",
+			map[named]bool{"b": true, "a": false, "ab": true},
+			AppendBool(AppendString(AppendBool(AppendString(AppendBool(AppendString(
+				AppendMapHeader(nil, 3), "a"), false), "ab"), true), "b"), true),
+		},
+		{
+			"integer keys",
+			map[int16]bool{10: true, -1: false},
+			AppendBool(AppendInt64(AppendBool(AppendInt64(
+				AppendMapHeader(nil, 2), -1), false), 10), true),
+		},
+		{
+			"MarshalHash keys",
+			map[hashValue]bool{{300}: true, {2}: false},
+			AppendBool(AppendBytes(AppendBool(AppendBytes(
+				AppendMapHeader(nil, 2), AppendInt64(nil, 2)), false), AppendInt64(nil, 300)), true),
+		},
+	}
+	for _, c := range cases {
+		got, err := AppendIntfHash(nil, c.in)
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		if !bytes.Equal(got, c.want) {
+			t.Errorf("%s: got %x, want %x", c.name, got, c.want)
+		}
+	}
+
+	if _, err := AppendIntfHash(nil, struct{ A int }{1}); err == nil {
+		t.Error("expected an error for a struct without MarshalHash")
+	}
+}
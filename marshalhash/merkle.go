@@ -0,0 +1,160 @@
+package marshalhash
+
+import (
+	"errors"
+)
+
+// The slice fields tagged merkle are written as the
+// root of a Merkle tree instead of their elements, so
+// that an element can be proven to be part of a value
+// with the hash of the value and a proof of
+// log2(len(slice)) digests, see MerkleProof. The tree
+// is the one of RFC 6962: its leaves are the digests
+// of 0x00 followed by the MarshalHash output of the
+// elements, and its nodes the digests of 0x01
+// followed by their children.
+
+// MerkleHash computes the digests of
+// a Merkle tree, e.g. sha256.Sum256
+type MerkleHash func(data []byte) [32]byte
+
+// MerkleLeaf returns the leaf of the
+// MarshalHash output data of an element
+func MerkleLeaf(h MerkleHash, data []byte) [32]byte {
+	b := make([]byte, 1+len(data))
+	copy(b[1:], data)
+	return h(b)
+}
+
+func merkleNode(h MerkleHash, left, right [32]byte) [32]byte {
+	var b [65]byte
+	b[0] = 1
+	copy(b[1:], left[:])
+	copy(b[33:], right[:])
+	return h(b[:])
+}
+
+// MerkleLeaves returns the leaves of the elements of vs.
+func MerkleLeaves[T any, P interface {
+	*T
+	HashMarshaler
+}](h MerkleHash, vs []T) ([][32]byte, error) {
+	leaves := make([][32]byte, len(vs))
+	for i := range vs {
+		bts, err := P(&vs[i]).MarshalHash()
+		if err != nil {
+			return nil, err
+		}
+		leaves[i] = MerkleLeaf(h, bts)
+	}
+	return leaves, nil
+}
+
+// merkleSplit returns the size of the left
+// subtree of n > 1 leaves: the largest power
+// of 2 smaller than n
+func merkleSplit(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// MerkleRoot returns the root of the tree
+// of leaves, or the digest of nothing if
+// there are none
+func MerkleRoot(h MerkleHash, leaves [][32]byte) [32]byte {
+	switch len(leaves) {
+	case 0:
+		return h(nil)
+	case 1:
+		return leaves[0]
+	}
+	k := merkleSplit(len(leaves))
+	return merkleNode(h, MerkleRoot(h, leaves[:k]), MerkleRoot(h, leaves[k:]))
+}
+
+// AppendMerkleRoot appends the root of the
+// tree of leaves to b as a 'bin' object.
+func AppendMerkleRoot(b []byte, h MerkleHash, leaves [][32]byte) []byte {
+	root := MerkleRoot(h, leaves)
+	return AppendBytes(b, root[:])
+}
+
+// ReadMerkleRootBytes reads a root
+// written by AppendMerkleRoot.
+func ReadMerkleRootBytes(b []byte) (root [32]byte, o []byte, err error) {
+	var v []byte
+	if v, o, err = ReadBytesZC(b); err != nil {
+		return root, b, err
+	}
+	if len(v) != len(root) {
+		return root, b, ArrayError{Wanted: uint32(len(root)), Got: uint32(len(v))}
+	}
+	copy(root[:], v)
+	return root, o, nil
+}
+
+// ErrMerkleIndex is returned by NewMerkleProof
+// for an element that is not in the tree
+var ErrMerkleIndex = errors.New("hsp: Merkle tree element index out of range")
+
+// MerkleProof is the proof that an element
+// is part of the tree of a merkle field.
+type MerkleProof struct {
+	Index int        // of the element
+	Count int        // of the elements of the tree
+	Path  [][32]byte // the sibling subtrees, from the leaf up
+}
+
+// NewMerkleProof returns the proof that
+// leaves[i] is part of the tree of leaves.
+func NewMerkleProof(h MerkleHash, leaves [][32]byte, i int) (MerkleProof, error) {
+	if i < 0 || i >= len(leaves) {
+		return MerkleProof{}, ErrMerkleIndex
+	}
+	p := MerkleProof{Index: i, Count: len(leaves)}
+	p.Path = merklePath(h, leaves, i)
+	return p, nil
+}
+
+func merklePath(h MerkleHash, leaves [][32]byte, i int) [][32]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := merkleSplit(len(leaves))
+	if i < k {
+		return append(merklePath(h, leaves[:k], i), MerkleRoot(h, leaves[k:]))
+	}
+	return append(merklePath(h, leaves[k:], i-k), MerkleRoot(h, leaves[:k]))
+}
+
+// Verify returns whether p proves that leaf is the
+// element p.Index of the tree of p.Count elements
+// whose root is root.
+func (p MerkleProof) Verify(h MerkleHash, root, leaf [32]byte) bool {
+	if p.Index < 0 || p.Index >= p.Count {
+		return false
+	}
+	// see RFC 9162, 2.1.3.2
+	fn, sn := p.Index, p.Count-1
+	r := leaf
+	for _, sibling := range p.Path {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = merkleNode(h, sibling, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNode(h, r, sibling)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && r == root
+}
@@ -0,0 +1,73 @@
+package marshalhash
+
+import (
+	"crypto/sha256"
+	"testing"
+)
+
+func TestMerkleRoot(t *testing.T) {
+	h := MerkleHash(sha256.Sum256)
+	var leaves [][32]byte
+	for i := 0; i < 3; i++ {
+		leaves = append(leaves, MerkleLeaf(h, AppendInt(nil, i)))
+	}
+	if got, want := MerkleRoot(h, nil), sha256.Sum256(nil); got != want {
+		t.Errorf("empty tree: got %x, want %x", got, want)
+	}
+	if got := MerkleRoot(h, leaves[:1]); got != leaves[0] {
+		t.Errorf("single leaf: got %x, want %x", got, leaves[0])
+	}
+	// the left subtrees are perfect
+	want := merkleNode(h, merkleNode(h, leaves[0], leaves[1]), leaves[2])
+	if got := MerkleRoot(h, leaves); got != want {
+		t.Errorf("got %x, want %x", got, want)
+	}
+
+	b := AppendMerkleRoot(nil, h, leaves)
+	if len(b) != MerkleRootSize {
+		t.Errorf("got %d bytes, want %d", len(b), MerkleRootSize)
+	}
+	root, o, err := ReadMerkleRootBytes(b)
+	if err != nil || len(o) != 0 || root != want {
+		t.Errorf("got %x, %d bytes left, %v", root, len(o), err)
+	}
+	if _, _, err = ReadMerkleRootBytes(AppendBytes(nil, root[:31])); err == nil {
+		t.Error("read a 31 byte root")
+	}
+}
+
+func TestMerkleProof(t *testing.T) {
+	h := MerkleHash(sha256.Sum256)
+	for n := 1; n <= 17; n++ {
+		leaves := make([][32]byte, n)
+		for i := range leaves {
+			leaves[i] = MerkleLeaf(h, AppendInt(nil, i))
+		}
+		root := MerkleRoot(h, leaves)
+		for i := range leaves {
+			p, err := NewMerkleProof(h, leaves, i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !p.Verify(h, root, leaves[i]) {
+				t.Errorf("%d of %d: valid proof rejected", i, n)
+			}
+			if n > 1 && p.Verify(h, root, leaves[(i+1)%n]) {
+				t.Errorf("%d of %d: proof of another leaf accepted", i, n)
+			}
+			if n > 1 {
+				p.Path[len(p.Path)-1][0] ^= 1
+				if p.Verify(h, root, leaves[i]) {
+					t.Errorf("%d of %d: altered proof accepted", i, n)
+				}
+			}
+			p.Index = n
+			if p.Verify(h, root, leaves[i]) {
+				t.Errorf("%d of %d: proof of an index out of range accepted", i, n)
+			}
+		}
+		if _, err := NewMerkleProof(h, leaves, n); err != ErrMerkleIndex {
+			t.Errorf("got %v for an index out of range", err)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package marshalhash
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The MarshalHash output has no keys, so
+// Locate and Replace can't find its fields.
+// The functions below find them by a path
+// instead, e.g. "Header.Producer", "Txs[1]"
+// or `Weights["ss"]`, as listed by Diff
+// without the type name, using the schema of
+// the type: the fields, elements and map
+// entries written before the part are
+// skipped, and nothing else is read.
+
+// binSpan is a bin object holding
+// a nested type, see locatePath
+type binSpan struct {
+	start int // of the prefix
+	data  int // of the MarshalHash output
+	end   int
+}
+
+// splitPath returns the elements of path: the
+// field names, and the indexes and map keys in
+// brackets, with the strings quoted as by Dump
+func splitPath(path string) ([]string, error) {
+	var elems []string
+	p := path
+	for len(p) > 0 {
+		switch {
+		case p[0] == '[' && strings.HasPrefix(p, `["`):
+			q, err := strconv.QuotedPrefix(p[1:])
+			if err != nil || !strings.HasPrefix(p[1+len(q):], "]") {
+				return nil, fmt.Errorf("hsp: invalid path %q", path)
+			}
+			key, _ := strconv.Unquote(q)
+			elems = append(elems, "["+strconv.Quote(key)+"]")
+			p = p[2+len(q):]
+		case p[0] == '[':
+			i := strings.IndexByte(p, ']')
+			if i < 2 {
+				return nil, fmt.Errorf("hsp: invalid path %q", path)
+			}
+			elems = append(elems, p[:i+1])
+			p = p[i+1:]
+		default:
+			if len(elems) > 0 {
+				if p[0] != '.' {
+					return nil, fmt.Errorf("hsp: invalid path %q", path)
+				}
+				p = p[1:]
+			}
+			i := strings.IndexAny(p, ".[")
+			if i < 0 {
+				i = len(p)
+			}
+			if i == 0 {
+				return nil, fmt.Errorf("hsp: invalid path %q", path)
+			}
+			elems = append(elems, p[:i])
+			p = p[i:]
+		}
+	}
+	return elems, nil
+}
+
+// locatePath returns the start and end of the part of raw,
+// the encoding of typ, at path, its schema, and the bin
+// objects holding it, from the outermost one
+func locatePath(fs *FileSchema, typ, path string, raw []byte) (start, end int, s *Schema, bins []binSpan, err error) {
+	elems, err := splitPath(path)
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	if s = fs.Lookup(typ); s == nil {
+		return 0, 0, nil, nil, fmt.Errorf("hsp: no schema for type %s", typ)
+	}
+	fail := func(at int, format string, args ...interface{}) error {
+		return fmt.Errorf("hsp: %s.%s at offset %#x: %s", typ, path, at, fmt.Sprintf(format, args...))
+	}
+
+	// the part starts at pos, in a
+	// (nested) object ending at lim
+	pos, lim := 0, len(raw)
+	for _, el := range elems {
+		// the pointers and nested types
+		// holding the part
+		for s.Kind == "pointer" || s.Kind == "named" {
+			b := raw[pos:lim]
+			switch {
+			case s.Kind == "pointer":
+				if IsNil(b) {
+					return 0, 0, nil, nil, fail(pos, "nil pointer")
+				}
+				s = s.Elem
+				continue
+			case s.Nesting == "intf":
+				return 0, 0, nil, nil, fail(pos, "type parameter")
+			}
+			inner := fs.Lookup(s.Type)
+			if inner == nil {
+				return 0, 0, nil, nil, fail(pos, "no schema for type %s", s.Type)
+			}
+			if s.Nesting != "inline" {
+				v, o, err := ReadBytesZC(b)
+				if err != nil {
+					return 0, 0, nil, nil, fail(pos, "%v", err)
+				}
+				end := lim - len(o)
+				bins = append(bins, binSpan{start: pos, data: end - len(v), end: end})
+				pos, lim = end-len(v), end
+			}
+			s = inner
+		}
+
+		b := raw[pos:lim]
+		var o []byte
+		var found bool
+		switch {
+		case s.Kind == "struct" && el[0] != '[':
+			o, s, found, err = locateField(fs, s, b, el)
+		case (s.Kind == "slice" || s.Kind == "array") && s.Encoding != "Bytes" && el[0] == '[':
+			s = s.Elem
+			o, found, err = locateElem(fs, s, b, el)
+		case s.Kind == "map" && el[0] == '[':
+			s = s.Elem
+			o, found, err = locateEntry(fs, s, b, el)
+		default:
+			return 0, 0, nil, nil, fail(pos, "no %s in %s", el, schemaType(s))
+		}
+		if err != nil {
+			return 0, 0, nil, nil, fail(lim-len(o), "%v", err)
+		}
+		if !found {
+			return 0, 0, nil, nil, fail(pos, "no %s", el)
+		}
+		pos = lim - len(o)
+	}
+
+	o, err := skipSchema(fs, s, raw[pos:lim])
+	if err != nil {
+		return 0, 0, nil, nil, fail(pos, "%v", err)
+	}
+	return pos, lim - len(o), s, bins, nil
+}
+
+// locateField returns the bytes starting with the
+// field name of the struct s at the start of b
+func locateField(fs *FileSchema, s *Schema, b []byte, name string) ([]byte, *Schema, bool, error) {
+	sz, o, err := readStructHeader(s, b)
+	if err != nil {
+		return o, nil, false, err
+	}
+	// an empty omitempty field is not found
+	var typ *Schema
+	o, err = eachField(s, sz, o, func(f SchemaField, o []byte) ([]byte, bool, error) {
+		if f.Name == name {
+			typ = f.Type
+			return o, true, nil
+		}
+		o, err := skipSchema(fs, f.Type, o)
+		return o, false, err
+	})
+	return o, typ, typ != nil && err == nil, err
+}
+
+// locateElem returns the bytes starting with
+// the element idx, as "[i]", of the array of
+// elem at the start of b
+func locateElem(fs *FileSchema, elem *Schema, b []byte, idx string) ([]byte, bool, error) {
+	i, err := strconv.ParseUint(idx[1:len(idx)-1], 10, 32)
+	if err != nil {
+		return b, false, nil
+	}
+	sz, o, err := ReadArrayHeaderBytes(b)
+	if err != nil || i >= uint64(sz) {
+		return o, false, err
+	}
+	for ; i > 0; i-- {
+		if o, err = skipSchema(fs, elem, o); err != nil {
+			return o, false, err
+		}
+	}
+	return o, true, nil
+}
+
+// locateEntry returns the bytes starting with the
+// value of the key, as "[key]" formatted by Dump,
+// of the map of elem at the start of b
+func locateEntry(fs *FileSchema, elem *Schema, b []byte, key string) ([]byte, bool, error) {
+	sz, o, err := ReadMapHeaderBytes(b)
+	if err != nil {
+		return o, false, err
+	}
+	for i := uint32(0); i < sz; i++ {
+		var k string
+		if k, o, err = formatPrimitive("", o); err != nil {
+			return o, false, err
+		}
+		if "["+k+"]" == key {
+			return o, true, nil
+		}
+		if o, err = skipSchema(fs, elem, o); err != nil {
+			return o, false, err
+		}
+	}
+	return o, false, nil
+}
+
+// skipSchema skips the object written as s at the
+// start of b. Skip would read the header of a struct
+// in map mode as the one of a map of twice as many
+// objects, as the keys of the fields are not written.
+func skipSchema(fs *FileSchema, s *Schema, b []byte) ([]byte, error) {
+	switch s.Kind {
+	case "pointer":
+		if IsNil(b) {
+			return ReadNilBytes(b)
+		}
+		return skipSchema(fs, s.Elem, b)
+	case "named":
+		if s.Nesting != "inline" {
+			return Skip(b)
+		}
+		inner := fs.Lookup(s.Type)
+		if inner == nil {
+			return b, fmt.Errorf("no schema for type %s", s.Type)
+		}
+		return skipSchema(fs, inner, b)
+	case "slice", "array":
+		if s.Encoding == "Bytes" {
+			return Skip(b)
+		}
+		sz, o, err := ReadArrayHeaderBytes(b)
+		for i := uint32(0); i < sz && err == nil; i++ {
+			o, err = skipSchema(fs, s.Elem, o)
+		}
+		return o, err
+	case "map":
+		sz, o, err := ReadMapHeaderBytes(b)
+		for i := uint32(0); i < sz && err == nil; i++ {
+			if o, err = Skip(o); err == nil {
+				o, err = skipSchema(fs, s.Elem, o)
+			}
+		}
+		return o, err
+	case "struct":
+		sz, o, err := readStructHeader(s, b)
+		if err != nil {
+			return o, err
+		}
+		return eachField(s, sz, o, func(f SchemaField, o []byte) ([]byte, bool, error) {
+			o, err := skipSchema(fs, f.Type, o)
+			return o, false, err
+		})
+	}
+	return Skip(b)
+}
+
+// LocatePath returns the part of raw, the MarshalHash output
+// of a value of the type typ described by fs, at path, e.g.
+// "Header.Producer", "Txs[1]" or `Weights["ss"]`: the encoding
+// of a field, element or map value, as written in raw (a bin
+// object for the nested types, unless they are inlined). The
+// returned []byte points to a sub-slice of raw. It returns an
+// error if there is no such part, e.g. for the omitempty
+// fields left out, or if raw doesn't match the schema.
+func LocatePath(fs *FileSchema, typ, path string, raw []byte) ([]byte, error) {
+	start, end, _, _, err := locatePath(fs, typ, path, raw)
+	if err != nil {
+		return nil, err
+	}
+	return raw[start:end], nil
+}
+
+// ExtractPath works like LocatePath, but returns the
+// MarshalHash output of the part, e.g. the one held by
+// the bin object of a nested type, which the type can
+// unmarshal, or compare with its own.
+func ExtractPath(fs *FileSchema, typ, path string, raw []byte) ([]byte, error) {
+	start, end, s, _, err := locatePath(fs, typ, path, raw)
+	if err != nil {
+		return nil, err
+	}
+	part := raw[start:end]
+	if s.Kind == "pointer" && !IsNil(part) {
+		s = s.Elem
+	}
+	if s.Kind == "named" && s.Nesting != "inline" {
+		part, _, err = ReadBytesZC(part)
+	}
+	return part, err
+}
+
+// ReplacePath replaces the part of raw at path, see
+// LocatePath, with val, written as the part is, and
+// updates the prefixes of the bin objects holding it.
+// The returned []byte may point to the same memory as
+// raw, of which it may use up to the full capacity, as
+// with Replace. ReplacePath makes no effort to check
+// the validity of val.
+func ReplacePath(fs *FileSchema, typ, path string, raw, val []byte) ([]byte, error) {
+	return replacePath(fs, typ, path, raw, val, true)
+}
+
+// CopyReplacePath works like ReplacePath, but the
+// returned []byte doesn't point to the memory of raw.
+func CopyReplacePath(fs *FileSchema, typ, path string, raw, val []byte) ([]byte, error) {
+	return replacePath(fs, typ, path, raw, val, false)
+}
+
+func replacePath(fs *FileSchema, typ, path string, raw, val []byte, inplace bool) ([]byte, error) {
+	start, end, _, bins, err := locatePath(fs, typ, path, raw)
+	if err != nil {
+		return nil, err
+	}
+	// rewrite the bin objects whose
+	// length changes, inner ones first
+	for i := len(bins) - 1; i >= 0 && len(val) != end-start; i-- {
+		bin := bins[i]
+		data := make([]byte, 0, bin.end-bin.data+len(val)-(end-start))
+		data = append(data, raw[bin.data:start]...)
+		data = append(data, val...)
+		data = append(data, raw[end:bin.end]...)
+		start, end, val = bin.start, bin.end, AppendBytes(nil, data)
+	}
+	return replace(raw, start, end, val, inplace), nil
+}
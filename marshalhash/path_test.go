@@ -0,0 +1,161 @@
+package marshalhash
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLocatePath(t *testing.T) {
+	raw := appendBlock(nil, "genesis", [][]byte{{0xca, 0xfe}, nil}, 3, 0x02)
+	for _, c := range []struct {
+		path string
+		want []byte
+	}{
+		{"", raw},
+		{"Header.Height", AppendInt64(nil, 7)},
+		{"Header.Note", AppendString(nil, "genesis")},
+		{"Txs[0]", AppendBytes(nil, []byte{0xca, 0xfe})},
+		{"Txs[1]", AppendBytes(nil, nil)},
+		{`Weights["ss"]`, AppendUint32(nil, 3)},
+		{"Hash", AppendBytes(nil, []byte{0x01, 0x02})},
+	} {
+		got, err := LocatePath(testSchema(), "Block", c.path, raw)
+		if err != nil {
+			t.Errorf("%s: %v", c.path, err)
+			continue
+		}
+		if !bytes.Equal(got, c.want) {
+			t.Errorf("%s: got %x, want %x", c.path, got, c.want)
+		}
+	}
+
+	for _, c := range []struct {
+		path string
+		want string
+	}{
+		{"Header.Nope", "no Nope"},
+		{"Txs[2]", "no [2]"},
+		{`Weights["s"]`, `no ["s"]`},
+		{"Hash[0]", "no [0] in [2]byte"},
+		{"Txs[", "invalid path"},
+		{"Txs..", "invalid path"},
+	} {
+		_, err := LocatePath(testSchema(), "Block", c.path, raw)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: got error %v, want %q", c.path, err, c.want)
+		}
+	}
+
+	// the empty omitempty fields are not written
+	noNote := appendBlock(nil, "", nil, 3, 0x02)
+	if _, err := LocatePath(testSchema(), "Block", "Header.Note", noNote); err == nil {
+		t.Error("found an omitted field")
+	}
+}
+
+func TestExtractPath(t *testing.T) {
+	raw := appendBlock(nil, "genesis", nil, 3, 0x02)
+	header, err := LocatePath(testSchema(), "Block", "Header", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ExtractPath(testSchema(), "Block", "Header", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _, _ := ReadBytesZC(header)
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %x, want %x", got, want)
+	}
+}
+
+func TestReplacePath(t *testing.T) {
+	txs := [][]byte{{0xca, 0xfe}}
+	raw := appendBlock(nil, "genesis", txs, 3, 0x02)
+	orig := append([]byte(nil), raw...)
+
+	// the bin object of the Header grows
+	note := strings.Repeat("n", 300)
+	want := appendBlock(nil, note, txs, 3, 0x02)
+	got, err := CopyReplacePath(testSchema(), "Block", "Header.Note", raw, AppendString(nil, note))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %x, want %x", got, want)
+	}
+	if !bytes.Equal(raw, orig) {
+		t.Error("CopyReplacePath changed raw")
+	}
+
+	want = appendBlock(nil, "genesis", txs, 4, 0x02)
+	got, err = ReplacePath(testSchema(), "Block", `Weights["ss"]`, raw, AppendUint32(nil, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %x, want %x", got, want)
+	}
+
+	if _, err = ReplacePath(testSchema(), "Block", "Txs[1]", raw, nil); err == nil {
+		t.Error("replaced a missing element")
+	}
+}
+
+func TestLocatePathSkipStruct(t *testing.T) {
+	// Pair{A struct{X, Y int64}, B int64}, where
+	// A is written as map(2) followed by 2 values
+	i64 := &Schema{Kind: "primitive", Type: "int64", Encoding: "Int64"}
+	fs := &FileSchema{Types: map[string]*Schema{
+		"Pair": {Kind: "struct", Type: "Pair", Mode: "map", Fields: []SchemaField{
+			{Name: "A", Tag: "A", Type: &Schema{Kind: "struct", Mode: "map", Fields: []SchemaField{
+				{Name: "X", Tag: "X", Type: i64},
+				{Name: "Y", Tag: "Y", Type: i64},
+			}}},
+			{Name: "B", Tag: "B", Type: i64},
+		}},
+	}}
+	raw := AppendMapHeader(nil, 2)
+	raw = AppendMapHeader(raw, 2)
+	raw = AppendInt64(raw, 1)
+	raw = AppendInt64(raw, 2)
+	raw = AppendInt64(raw, 3)
+
+	got, err := LocatePath(fs, "Pair", "B", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := AppendInt64(nil, 3); !bytes.Equal(got, want) {
+		t.Errorf("got %x, want %x", got, want)
+	}
+}
+
+func TestLocatePathDomain(t *testing.T) {
+	// Vote{A, B int64} in the domain "vote/v1",
+	// counted by its header
+	i64 := &Schema{Kind: "primitive", Type: "int64", Encoding: "Int64"}
+	fs := &FileSchema{Types: map[string]*Schema{
+		"Vote": {Kind: "struct", Type: "Vote", Mode: "map", Domain: "vote/v1", Fields: []SchemaField{
+			{Name: "A", Tag: "0", Type: i64},
+			{Name: "B", Tag: "1", Type: i64},
+		}},
+	}}
+	raw := AppendMapHeader(nil, 3)
+	raw = AppendDomain(raw, "vote/v1")
+	raw = AppendInt64(raw, 1)
+	raw = AppendInt64(raw, 2)
+
+	got, err := LocatePath(fs, "Vote", "B", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := AppendInt64(nil, 2); !bytes.Equal(got, want) {
+		t.Errorf("got %x, want %x", got, want)
+	}
+
+	fs.Types["Vote"].Domain = "tx/v1"
+	if _, err = LocatePath(fs, "Vote", "B", raw); err == nil {
+		t.Error("located a field of a value of another domain")
+	}
+}
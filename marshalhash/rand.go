@@ -0,0 +1,185 @@
+package marshalhash
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RandMaxDepth is the depth of the
+// nested values populated by a Rand.
+const RandMaxDepth = 6
+
+// Rand draws the values used by the generated
+// tests to populate the types. The values only
+// depend on the seed, or on the data, so that a
+// failure can be reproduced; they cover the whole
+// range of each type, including NaNs, and leave
+// pointers nil and containers empty now and then.
+type Rand struct {
+	r     *rand.Rand
+	depth int
+	long  int // elements left to draw, see Long
+}
+
+// NewRand returns a Rand drawing
+// its values from the seed.
+func NewRand(seed int64) *Rand {
+	return &Rand{r: rand.New(rand.NewSource(seed))}
+}
+
+// NewRandBytes returns a Rand drawing its values
+// from data, as the generated fuzz targets do; once
+// data is exhausted, the values are zeros.
+func NewRandBytes(data []byte) *Rand {
+	return &Rand{r: rand.New(&byteSource{data: data})}
+}
+
+// byteSource is a rand.Source
+// reading from a byte slice
+type byteSource struct {
+	data []byte
+}
+
+func (s *byteSource) Uint64() uint64 {
+	var b [8]byte
+	n := copy(b[:], s.data)
+	s.data = s.data[n:]
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+func (s *byteSource) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+func (s *byteSource) Seed(int64) {}
+
+// Enter reports whether a nested value can
+// be populated; every call returning true must
+// be paired with a call to Leave.
+func (r *Rand) Enter() bool {
+	if r.depth >= RandMaxDepth {
+		return false
+	}
+	r.depth++
+	return true
+}
+
+// Leave ends a nested value, see Enter.
+func (r *Rand) Leave() { r.depth-- }
+
+// Long makes r draw slices and maps of up to n
+// elements in total, instead of up to 3 each, so
+// that the sizes of their elements add up beyond
+// the ones of the headers, and returns r.
+func (r *Rand) Long(n int) *Rand {
+	r.long = n
+	return r
+}
+
+// Len returns the length of a slice or map,
+// which is 0 beyond the maximum depth.
+func (r *Rand) Len() int {
+	if r.depth >= RandMaxDepth {
+		return 0
+	}
+	if r.long > 0 {
+		n := r.r.Intn(r.long + 1)
+		r.long -= n
+		return n
+	}
+	return r.r.Intn(4)
+}
+
+// Nil reports whether a pointer is left nil,
+// which it always is beyond the maximum depth.
+func (r *Rand) Nil() bool {
+	return r.depth >= RandMaxDepth || r.r.Intn(4) == 0
+}
+
+// Read fills p with random bytes.
+func (r *Rand) Read(p []byte) {
+	for i := range p {
+		p[i] = byte(r.r.Uint32())
+	}
+}
+
+func (r *Rand) Bool() bool     { return r.r.Intn(2) == 1 }
+func (r *Rand) Int() int       { return int(r.r.Uint64()) }
+func (r *Rand) Int8() int8     { return int8(r.r.Uint32()) }
+func (r *Rand) Int16() int16   { return int16(r.r.Uint32()) }
+func (r *Rand) Int32() int32   { return int32(r.r.Uint32()) }
+func (r *Rand) Int64() int64   { return int64(r.r.Uint64()) }
+func (r *Rand) Uint() uint     { return uint(r.r.Uint64()) }
+func (r *Rand) Uint8() uint8   { return uint8(r.r.Uint32()) }
+func (r *Rand) Byte() byte     { return byte(r.r.Uint32()) }
+func (r *Rand) Uint16() uint16 { return uint16(r.r.Uint32()) }
+func (r *Rand) Uint32() uint32 { return r.r.Uint32() }
+func (r *Rand) Uint64() uint64 { return r.r.Uint64() }
+
+func (r *Rand) Float32() float32 { return math.Float32frombits(r.r.Uint32()) }
+func (r *Rand) Float64() float64 { return math.Float64frombits(r.r.Uint64()) }
+
+func (r *Rand) Complex64() complex64 { return complex(r.Float32(), r.Float32()) }
+
+func (r *Rand) Complex128() complex128 { return complex(r.Float64(), r.Float64()) }
+
+// Bytes returns up to 32 random bytes.
+func (r *Rand) Bytes() []byte {
+	b := make([]byte, r.r.Intn(33))
+	r.Read(b)
+	return b
+}
+
+// String returns up to 32 random bytes,
+// which are not always valid UTF-8.
+func (r *Rand) String() string { return string(r.Bytes()) }
+
+// Time returns a time between year 1970
+// and 2514, in UTC, or the zero time.
+func (r *Rand) Time() time.Time {
+	if r.r.Intn(8) == 0 {
+		return time.Time{}
+	}
+	return time.Unix(r.r.Int63n(1<<34), r.r.Int63n(1e9)).UTC()
+}
+
+// Intf returns a value of one of the types
+// decoded by ReadIntfBytes: nil, bool, int64,
+// uint64, float64, string, []byte, and slices
+// and maps of those.
+func (r *Rand) Intf() interface{} {
+	n := 9
+	if !r.Enter() {
+		n = 7
+	} else {
+		defer r.Leave()
+	}
+	switch r.r.Intn(n) {
+	case 0:
+		return nil
+	case 1:
+		return r.Bool()
+	case 2:
+		return r.Int64()
+	case 3:
+		return r.Uint64()
+	case 4:
+		return r.Float64()
+	case 5:
+		return r.String()
+	case 6:
+		return r.Bytes()
+	case 7:
+		s := make([]interface{}, r.Len())
+		for i := range s {
+			s[i] = r.Intf()
+		}
+		return s
+	default:
+		m := make(map[string]interface{})
+		for n := r.Len(); n > 0; n-- {
+			m[r.String()] = r.Intf()
+		}
+		return m
+	}
+}
@@ -0,0 +1,78 @@
+package marshalhash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRandDeterministic(t *testing.T) {
+	draw := func(r *Rand) []interface{} {
+		return []interface{}{r.String(), r.Int64(), r.Float64(), r.Time(), r.Intf(), r.Bytes()}
+	}
+	if a, b := draw(NewRand(7)), draw(NewRand(7)); !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed, different values: %v %v", a, b)
+	}
+	data := []byte("some fuzzer input")
+	if a, b := draw(NewRandBytes(data)), draw(NewRandBytes(data)); !reflect.DeepEqual(a, b) {
+		t.Errorf("same data, different values: %v %v", a, b)
+	}
+
+	// exhausted data draws zeros
+	r := NewRandBytes(nil)
+	if v := r.Uint64(); v != 0 {
+		t.Errorf("Uint64() = %d, want 0", v)
+	}
+	if s := r.String(); s != "" {
+		t.Errorf("String() = %q, want empty", s)
+	}
+}
+
+func TestRandDepth(t *testing.T) {
+	r := NewRand(1)
+	for i := 0; i < RandMaxDepth; i++ {
+		if !r.Enter() {
+			t.Fatalf("Enter() false at depth %d", i)
+		}
+	}
+	if r.Enter() {
+		t.Error("Enter() true beyond RandMaxDepth")
+	}
+	if !r.Nil() || r.Len() != 0 {
+		t.Error("values populated beyond RandMaxDepth")
+	}
+	r.Leave()
+	if !r.Enter() {
+		t.Error("Enter() false after Leave()")
+	}
+}
+
+func TestRandLong(t *testing.T) {
+	r := NewRand(3).Long(100)
+	total, longest := 0, 0
+	for i := 0; i < 100; i++ {
+		n := r.Len()
+		total += n
+		if n > longest {
+			longest = n
+		}
+	}
+	if total > 100+3*100 {
+		t.Errorf("drew %d elements, more than allowed", total)
+	}
+	if longest <= 3 {
+		t.Errorf("longest container has %d elements, want more than 3", longest)
+	}
+}
+
+func TestGuessSizeNested(t *testing.T) {
+	// as drawn by Intf with long containers
+	var v []interface{}
+	for i := 0; i < 100; i++ {
+		v = append(v, map[string]interface{}{"k": []interface{}{"0123456789", int64(i)}})
+	}
+	if b, err := AppendIntf(nil, v); err != nil {
+		t.Fatal(err)
+	} else if s := GuessSize(v); s < len(b) {
+		t.Errorf("GuessSize() = %d, less than the %d bytes of MarshalHash", s, len(b))
+	}
+}
@@ -323,6 +323,49 @@ func ReadFloat32Bytes(b []byte) (f float32, o []byte, err error) {
 	return
 }
 
+// ReadFloat64CanonicalBytes reads a float64 written
+// by AppendFloat64Canonical, AppendFloat64Integral or
+// their float32 counterparts, which is a float64, a
+// float32 or an integer, and returns the value and
+// the remaining bytes.
+// Possible errors:
+// - ErrShortBytes (too few bytes)
+// - TypeError{} (not a number)
+func ReadFloat64CanonicalBytes(b []byte) (f float64, o []byte, err error) {
+	switch NextType(b) {
+	case IntType:
+		var i int64
+		i, o, err = ReadInt64Bytes(b)
+		f = float64(i)
+	case UintType:
+		var u uint64
+		u, o, err = ReadUint64Bytes(b)
+		f = float64(u)
+	default:
+		f, o, err = ReadFloat64Bytes(b)
+	}
+	return
+}
+
+// ReadFloat32CanonicalBytes reads a float32 written by
+// AppendFloat32Canonical, see ReadFloat64CanonicalBytes.
+func ReadFloat32CanonicalBytes(b []byte) (float32, []byte, error) {
+	f, o, err := ReadFloat64CanonicalBytes(b)
+	return float32(f), o, err
+}
+
+// ReadFloat64IntegralBytes reads a float64 written by
+// AppendFloat64Integral, see ReadFloat64CanonicalBytes.
+func ReadFloat64IntegralBytes(b []byte) (float64, []byte, error) {
+	return ReadFloat64CanonicalBytes(b)
+}
+
+// ReadFloat32IntegralBytes reads a float32 written by
+// AppendFloat32Integral, see ReadFloat64CanonicalBytes.
+func ReadFloat32IntegralBytes(b []byte) (float32, []byte, error) {
+	return ReadFloat32CanonicalBytes(b)
+}
+
 // ReadBoolBytes tries to read a float64
 // from 'b' and return the value and the remaining bytes.
 // Possible errors:
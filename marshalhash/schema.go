@@ -0,0 +1,82 @@
+package marshalhash
+
+import (
+	"strings"
+)
+
+// Schema is the machine-readable description of
+// the encoding of a type, written as JSON by 'hsp
+// schema', so that the hashes can be reproduced
+// in other languages, and used by Dump to name the
+// parts of an encoding. Its Kind is one of:
+//
+//   - "primitive": written by hsp.Append{Encoding},
+//     e.g. AppendInt64 or AppendTimeUnixMilli
+//   - "named": a type with its own MarshalHash
+//     method, nested as a 'bin' object holding its
+//     output, in place ("inline", see //hsp:nesting),
+//     or by AppendIntfHash for type parameters
+//   - "pointer": nil, or Elem
+//   - "slice": an array header, then the Elem values
+//   - "merkle": a slice tagged merkle, written as a
+//     'bin' object holding the Merkle root of its Elem
+//     values, computed with Digest, see MerkleLeaf
+//   - "array": the same, or a 'bin' object for arrays
+//     of bytes, whose Encoding is then "Bytes"
+//   - "map": a map header, then the keys and values,
+//     the keys sorted as KeyOrder says
+//   - "struct": a map or array header (Mode), its
+//     Domain, if any, as a string (see //hsp:domain)
+//     counted by the header, then the values of the
+//     Fields, in the order they are written (sorted by
+//     tag, but for the structs inlined into another
+//     type, which keep their declaration order), but
+//     the omitempty ones, which follow in a map from
+//     their tag to their value when they are not empty;
+//     the header counts this map as one more field
+type Schema struct {
+	Kind         string        `json:"kind"`
+	Type         string        `json:"type,omitempty"`          // Go type
+	Encoding     string        `json:"encoding,omitempty"`      // primitives and byte arrays
+	Nesting      string        `json:"nesting,omitempty"`       // named: "bin", "inline" or "intf"
+	Size         string        `json:"size,omitempty"`          // arrays
+	KeyOrder     string        `json:"key_order,omitempty"`     // maps: "string", "numeric", "bytes" or "encoded"
+	Key          *Schema       `json:"key,omitempty"`           // maps
+	Elem         *Schema       `json:"elem,omitempty"`          // pointers, slices, arrays, map values
+	Digest       string        `json:"digest,omitempty"`        // merkle: "sha256", "sha512_256" or "blake2b"
+	Mode         string        `json:"mode,omitempty"`          // structs: "map" or "tuple"
+	Domain       string        `json:"domain,omitempty"`        // structs, see AppendDomain
+	Fields       []SchemaField `json:"fields,omitempty"`        // structs
+	VersionField string        `json:"version_field,omitempty"` // versioned structs
+	Versions     []string      `json:"versions,omitempty"`      // version hashes, by number
+	Version      *int          `json:"version,omitempty"`       // current version number
+}
+
+// SchemaField is a field of a struct Schema.
+type SchemaField struct {
+	Name      string  `json:"name"`
+	Tag       string  `json:"tag"`
+	OmitEmpty bool    `json:"omitempty,omitempty"`
+	Type      *Schema `json:"type"`
+}
+
+// FileSchema describes the types parsed by
+// a //go:generate hsp directive, as written
+// in a JSON array by 'hsp schema'.
+type FileSchema struct {
+	File    string             `json:"file"`
+	Package string             `json:"package"`
+	Nesting string             `json:"nesting"`           // "bin" or "inline", see //hsp:nesting
+	Digests map[string]string  `json:"digests,omitempty"` // see //hsp:digest
+	Types   map[string]*Schema `json:"types"`
+}
+
+// Lookup returns the schema of the type
+// name, which may be a generic type
+// instantiation, or nil if fs has none.
+func (fs *FileSchema) Lookup(name string) *Schema {
+	if i := strings.IndexByte(name, '['); i > 0 {
+		name = name[:i]
+	}
+	return fs.Types[name]
+}
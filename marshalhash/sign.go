@@ -0,0 +1,117 @@
+package marshalhash
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// The structs with a field tagged signature, and
+// one tagged signer, e.g. `hsp:",signature"`, are
+// hashed without them, and signed by the generated
+// SignHash method: it signs their Digest with a
+// crypto.Signer, which VerifyHash checks using the
+// Verifier registered for the type of its public key.
+
+// Verifier verifies the signatures of digests made
+// by the crypto.Signer whose public keys have one
+// type, see RegisterVerifier.
+type Verifier interface {
+	// SignerOpts returns the options passed to
+	// crypto.Signer.Sign to sign a digest computed
+	// with h: h itself, or crypto.Hash(0) for the
+	// signers taking the digest as the message.
+	SignerOpts(h crypto.Hash) crypto.SignerOpts
+
+	// Verify returns whether sig is a signature
+	// by pub of digest, computed with h.
+	Verify(pub crypto.PublicKey, digest, sig []byte, h crypto.Hash) bool
+}
+
+var (
+	// ErrInvalidSignature is returned by VerifyDigest
+	// for a signature that is not the one of the digest
+	ErrInvalidSignature = errors.New("hsp: invalid signature")
+
+	// ErrUnknownKey is returned by SignDigest and
+	// VerifyDigest for the public keys of a type
+	// with no Verifier
+	ErrUnknownKey = errors.New("hsp: no verifier for public key")
+)
+
+var verifiers = struct {
+	sync.RWMutex
+	m map[reflect.Type]Verifier
+}{m: map[reflect.Type]Verifier{
+	reflect.TypeOf(ed25519.PublicKey(nil)):  ed25519Verifier{},
+	reflect.TypeOf((*ecdsa.PublicKey)(nil)): ecdsaVerifier{},
+}}
+
+// RegisterVerifier registers v for the public
+// keys of the type of pub, e.g. a secp256k1 key,
+// replacing the one registered before, if any.
+// The ed25519 and ecdsa keys are registered by
+// default.
+func RegisterVerifier(pub crypto.PublicKey, v Verifier) {
+	verifiers.Lock()
+	verifiers.m[reflect.TypeOf(pub)] = v
+	verifiers.Unlock()
+}
+
+func lookupVerifier(pub crypto.PublicKey) (Verifier, error) {
+	verifiers.RLock()
+	v, ok := verifiers.m[reflect.TypeOf(pub)]
+	verifiers.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w of type %T", ErrUnknownKey, pub)
+	}
+	return v, nil
+}
+
+// SignDigest signs digest, computed with h, with priv,
+// whose public key must have a registered Verifier.
+func SignDigest(priv crypto.Signer, digest []byte, h crypto.Hash) ([]byte, error) {
+	v, err := lookupVerifier(priv.Public())
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(rand.Reader, digest, v.SignerOpts(h))
+}
+
+// VerifyDigest returns ErrInvalidSignature unless sig is
+// a signature by pub of digest, computed with h.
+func VerifyDigest(pub crypto.PublicKey, digest, sig []byte, h crypto.Hash) error {
+	v, err := lookupVerifier(pub)
+	if err != nil {
+		return err
+	}
+	if !v.Verify(pub, digest, sig, h) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ed25519 signs the digest as a message
+type ed25519Verifier struct{}
+
+func (ed25519Verifier) SignerOpts(crypto.Hash) crypto.SignerOpts { return crypto.Hash(0) }
+
+func (ed25519Verifier) Verify(pub crypto.PublicKey, digest, sig []byte, _ crypto.Hash) bool {
+	k := pub.(ed25519.PublicKey)
+	return len(k) == ed25519.PublicKeySize && ed25519.Verify(k, digest, sig)
+}
+
+// ecdsa signatures are ASN.1 encoded
+type ecdsaVerifier struct{}
+
+func (ecdsaVerifier) SignerOpts(h crypto.Hash) crypto.SignerOpts { return h }
+
+func (ecdsaVerifier) Verify(pub crypto.PublicKey, digest, sig []byte, _ crypto.Hash) bool {
+	k := pub.(*ecdsa.PublicKey)
+	return k != nil && ecdsa.VerifyASN1(k, digest, sig)
+}
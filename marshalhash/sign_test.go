@@ -0,0 +1,86 @@
+package marshalhash
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSignDigest(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("hsp"))
+	other := sha256.Sum256([]byte("other"))
+
+	for _, priv := range []crypto.Signer{edKey, ecKey} {
+		sig, err := SignDigest(priv, digest[:], crypto.SHA256)
+		if err != nil {
+			t.Fatalf("%T: %v", priv, err)
+		}
+		if err = VerifyDigest(priv.Public(), digest[:], sig, crypto.SHA256); err != nil {
+			t.Errorf("%T: %v", priv, err)
+		}
+		if err = VerifyDigest(priv.Public(), other[:], sig, crypto.SHA256); err != ErrInvalidSignature {
+			t.Errorf("%T: got %v, want ErrInvalidSignature", priv, err)
+		}
+	}
+	if err = VerifyDigest(ed25519.PublicKey(nil), digest[:], nil, crypto.SHA256); err != ErrInvalidSignature {
+		t.Errorf("nil key: got %v, want ErrInvalidSignature", err)
+	}
+}
+
+// hmacKey signs with a shared secret,
+// its public key being the secret itself
+type hmacKey []byte
+
+type hmacPublicKey []byte
+
+func (k hmacKey) Public() crypto.PublicKey { return hmacPublicKey(k) }
+
+func (k hmacKey) Sign(_ io.Reader, digest []byte, _ crypto.SignerOpts) ([]byte, error) {
+	m := hmac.New(sha256.New, k)
+	m.Write(digest)
+	return m.Sum(nil), nil
+}
+
+type hmacVerifier struct{}
+
+func (hmacVerifier) SignerOpts(h crypto.Hash) crypto.SignerOpts { return h }
+
+func (hmacVerifier) Verify(pub crypto.PublicKey, digest, sig []byte, _ crypto.Hash) bool {
+	want, _ := hmacKey(pub.(hmacPublicKey)).Sign(nil, digest, nil)
+	return hmac.Equal(sig, want)
+}
+
+func TestRegisterVerifier(t *testing.T) {
+	key := hmacKey("secret")
+	digest := sha256.Sum256([]byte("hsp"))
+	if _, err := SignDigest(key, digest[:], crypto.SHA256); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("got %v, want ErrUnknownKey", err)
+	}
+
+	RegisterVerifier(hmacPublicKey(nil), hmacVerifier{})
+	sig, err := SignDigest(key, digest[:], crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyDigest(key.Public(), digest[:], sig, crypto.SHA256); err != nil {
+		t.Error(err)
+	}
+	if err = VerifyDigest(hmacPublicKey("other"), digest[:], sig, crypto.SHA256); err != ErrInvalidSignature {
+		t.Errorf("got %v, want ErrInvalidSignature", err)
+	}
+}
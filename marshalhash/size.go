@@ -25,10 +25,26 @@ const (
 	Complex64Size  = 10
 	Complex128Size = 18
 
+	// floats widened to float64, or integers
+	Float64CanonicalSize = Float64Size
+	Float32CanonicalSize = Float64Size
+	Float64IntegralSize  = Float64Size
+	Float32IntegralSize  = Float64Size
+
 	TimeSize = 15
 	BoolSize = 1
 	NilSize  = 1
 
+	// times as integers, or as strings
+	// of years of up to 12 digits
+	TimeUnixSize      = Int64Size
+	TimeUnixMilliSize = Int64Size
+	TimeUnixMicroSize = Int64Size
+	TimeRFC3339Size   = StringPrefixSize + 39
+
+	// a 'bin' object holding a digest
+	MerkleRootSize = 2 + 32
+
 	MapHeaderSize   = 5
 	ArrayHeaderSize = 5
 
@@ -0,0 +1,194 @@
+package marshalhash
+
+import (
+	"time"
+)
+
+// AppendTime writes a time as an extension holding
+// its seconds and nanoseconds. The functions below
+// write it with the precision of the values stored
+// elsewhere, e.g. by a database, so that they keep
+// their hash: as the number of seconds, milliseconds
+// or microseconds since the Unix epoch, rounded down,
+// or as an RFC 3339 string in UTC, with nanoseconds.
+// As with AppendTime, the zero time is written as nil,
+// and the location of the time is not kept.
+
+// AppendTimeUnix appends a time as
+// the number of seconds since the epoch.
+func AppendTimeUnix(b []byte, t time.Time) []byte {
+	if t.IsZero() {
+		return AppendNil(b)
+	}
+	return AppendInt64(b, t.Unix())
+}
+
+// AppendTimeUnixMilli appends a time as the
+// number of milliseconds since the epoch.
+func AppendTimeUnixMilli(b []byte, t time.Time) []byte {
+	if t.IsZero() {
+		return AppendNil(b)
+	}
+	return AppendInt64(b, t.UnixMilli())
+}
+
+// AppendTimeUnixMicro appends a time as the
+// number of microseconds since the epoch.
+func AppendTimeUnixMicro(b []byte, t time.Time) []byte {
+	if t.IsZero() {
+		return AppendNil(b)
+	}
+	return AppendInt64(b, t.UnixMicro())
+}
+
+// AppendTimeRFC3339 appends a time as an
+// RFC 3339 string in UTC, with nanoseconds.
+// RFC 3339 only has the years 0000 to 9999:
+// the others are written with more digits or
+// a sign, and can't be read back.
+func AppendTimeRFC3339(b []byte, t time.Time) []byte {
+	if t.IsZero() {
+		return AppendNil(b)
+	}
+	return AppendString(b, t.UTC().Format(time.RFC3339Nano))
+}
+
+// WriteTimeUnix writes a time as the number
+// of seconds since the epoch, see AppendTimeUnix.
+func (mw *Writer) WriteTimeUnix(t time.Time) error {
+	if t.IsZero() {
+		return mw.WriteNil()
+	}
+	return mw.WriteInt64(t.Unix())
+}
+
+// WriteTimeUnixMilli writes a time as the number of
+// milliseconds since the epoch, see AppendTimeUnixMilli.
+func (mw *Writer) WriteTimeUnixMilli(t time.Time) error {
+	if t.IsZero() {
+		return mw.WriteNil()
+	}
+	return mw.WriteInt64(t.UnixMilli())
+}
+
+// WriteTimeUnixMicro writes a time as the number of
+// microseconds since the epoch, see AppendTimeUnixMicro.
+func (mw *Writer) WriteTimeUnixMicro(t time.Time) error {
+	if t.IsZero() {
+		return mw.WriteNil()
+	}
+	return mw.WriteInt64(t.UnixMicro())
+}
+
+// WriteTimeRFC3339 writes a time as an RFC 3339
+// string in UTC, see AppendTimeRFC3339.
+func (mw *Writer) WriteTimeRFC3339(t time.Time) error {
+	if t.IsZero() {
+		return mw.WriteNil()
+	}
+	return mw.WriteString(t.UTC().Format(time.RFC3339Nano))
+}
+
+func unixSec(i int64) time.Time { return time.Unix(i, 0) }
+
+// ReadTimeUnixBytes reads a time written by
+// AppendTimeUnix and returns the remaining bytes.
+// The returned time's location will be set to time.Local.
+func ReadTimeUnixBytes(b []byte) (time.Time, []byte, error) {
+	return readUnixBytes(b, unixSec)
+}
+
+// ReadTimeUnixMilliBytes reads a time written by
+// AppendTimeUnixMilli, see ReadTimeUnixBytes.
+func ReadTimeUnixMilliBytes(b []byte) (time.Time, []byte, error) {
+	return readUnixBytes(b, time.UnixMilli)
+}
+
+// ReadTimeUnixMicroBytes reads a time written by
+// AppendTimeUnixMicro, see ReadTimeUnixBytes.
+func ReadTimeUnixMicroBytes(b []byte) (time.Time, []byte, error) {
+	return readUnixBytes(b, time.UnixMicro)
+}
+
+func readUnixBytes(b []byte, unix func(int64) time.Time) (t time.Time, o []byte, err error) {
+	if IsNil(b) {
+		o, err = ReadNilBytes(b)
+		return
+	}
+	var i int64
+	i, o, err = ReadInt64Bytes(b)
+	if err == nil {
+		t = unix(i).Local()
+	}
+	return
+}
+
+// ReadTimeRFC3339Bytes reads a time written by
+// AppendTimeRFC3339, see ReadTimeUnixBytes. It
+// returns an error for the years beyond 0000 to
+// 9999, and the zero time with it.
+func ReadTimeRFC3339Bytes(b []byte) (t time.Time, o []byte, err error) {
+	if IsNil(b) {
+		o, err = ReadNilBytes(b)
+		return
+	}
+	var s []byte
+	s, o, err = ReadStringZC(b)
+	if err != nil {
+		return
+	}
+	if t, err = time.Parse(time.RFC3339Nano, string(s)); err == nil {
+		t = t.Local()
+	}
+	return
+}
+
+// ReadTimeUnix reads a time written by
+// WriteTimeUnix from the reader.
+// The returned time's location will be set to time.Local.
+func (m *Reader) ReadTimeUnix() (time.Time, error) {
+	return m.readUnix(unixSec)
+}
+
+// ReadTimeUnixMilli reads a time written
+// by WriteTimeUnixMilli, see ReadTimeUnix.
+func (m *Reader) ReadTimeUnixMilli() (time.Time, error) {
+	return m.readUnix(time.UnixMilli)
+}
+
+// ReadTimeUnixMicro reads a time written
+// by WriteTimeUnixMicro, see ReadTimeUnix.
+func (m *Reader) ReadTimeUnixMicro() (time.Time, error) {
+	return m.readUnix(time.UnixMicro)
+}
+
+func (m *Reader) readUnix(unix func(int64) time.Time) (t time.Time, err error) {
+	if m.IsNil() {
+		err = m.ReadNil()
+		return
+	}
+	var i int64
+	i, err = m.ReadInt64()
+	if err == nil {
+		t = unix(i).Local()
+	}
+	return
+}
+
+// ReadTimeRFC3339 reads a time written
+// by WriteTimeRFC3339, see ReadTimeUnix.
+func (m *Reader) ReadTimeRFC3339() (t time.Time, err error) {
+	if m.IsNil() {
+		err = m.ReadNil()
+		return
+	}
+	var s string
+	s, err = m.ReadString()
+	if err != nil {
+		return
+	}
+	if t, err = time.Parse(time.RFC3339Nano, s); err == nil {
+		t = t.Local()
+	}
+	return
+}
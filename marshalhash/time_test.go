@@ -0,0 +1,86 @@
+package marshalhash
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeEncodings(t *testing.T) {
+	at := time.Date(2018, 10, 20, 8, 30, 15, 123456789, time.FixedZone("CST", 8*3600))
+	for _, c := range []struct {
+		name  string
+		app   func([]byte, time.Time) []byte
+		write func(*Writer, time.Time) error
+		read  func([]byte) (time.Time, []byte, error)
+		rread func(*Reader) (time.Time, error)
+		want  time.Time
+		size  int
+	}{
+		{"unix", AppendTimeUnix, (*Writer).WriteTimeUnix, ReadTimeUnixBytes, (*Reader).ReadTimeUnix,
+			at.Truncate(time.Second), TimeUnixSize},
+		{"unix_ms", AppendTimeUnixMilli, (*Writer).WriteTimeUnixMilli, ReadTimeUnixMilliBytes, (*Reader).ReadTimeUnixMilli,
+			at.Truncate(time.Millisecond), TimeUnixMilliSize},
+		{"unix_us", AppendTimeUnixMicro, (*Writer).WriteTimeUnixMicro, ReadTimeUnixMicroBytes, (*Reader).ReadTimeUnixMicro,
+			at.Truncate(time.Microsecond), TimeUnixMicroSize},
+		{"rfc3339", AppendTimeRFC3339, (*Writer).WriteTimeRFC3339, ReadTimeRFC3339Bytes, (*Reader).ReadTimeRFC3339,
+			at, TimeRFC3339Size},
+	} {
+		for _, tm := range []time.Time{at, {}} {
+			var buf bytes.Buffer
+			en := NewWriter(&buf)
+			if err := c.write(en, tm); err != nil {
+				t.Fatal(err)
+			}
+			en.Flush()
+			bts := c.app(nil, tm)
+			if !bytes.Equal(buf.Bytes(), bts) {
+				t.Errorf("%s: encoder wrote %x; append wrote %x", c.name, buf.Bytes(), bts)
+			}
+			if len(bts) > c.size {
+				t.Errorf("%s: wrote %d bytes, more than %d", c.name, len(bts), c.size)
+			}
+
+			want := c.want
+			if tm.IsZero() {
+				want = time.Time{}
+			}
+			got, o, err := c.read(bts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(o) != 0 || !got.Equal(want) {
+				t.Errorf("%s: read %s, want %s", c.name, got, want)
+			}
+			got, err = c.rread(NewReader(&buf))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("%s: Reader read %s, want %s", c.name, got, want)
+			}
+			if !EqualTimeAs(tm, want, c.app) {
+				t.Errorf("%s: %s and %s should compare equal", c.name, tm, want)
+			}
+		}
+	}
+}
+
+func TestTimeRFC3339Size(t *testing.T) {
+	far := time.Date(-292277022399, 1, 1, 0, 0, 0, 999999999, time.UTC)
+	if n := len(AppendTimeRFC3339(nil, far)); n > TimeRFC3339Size {
+		t.Errorf("wrote %d bytes, more than %d", n, TimeRFC3339Size)
+	}
+}
+
+func TestTimeRFC3339Years(t *testing.T) {
+	for _, year := range []int{-1, 10000} {
+		tm := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		got, _, err := ReadTimeRFC3339Bytes(AppendTimeRFC3339(nil, tm))
+		if err == nil {
+			t.Errorf("year %d: read %s, want an error", year, got)
+		} else if !got.IsZero() || got.Location() != time.UTC {
+			t.Errorf("year %d: got %s with the error, want the zero time", year, got)
+		}
+	}
+}
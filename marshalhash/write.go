@@ -31,8 +31,19 @@ var (
 			return &Writer{buf: make([]byte, 2048)}
 		},
 	}
+	hashWriterPool = sync.Pool{
+		New: func() interface{} {
+			return &Writer{buf: make([]byte, HashWriterSize)}
+		},
+	}
 )
 
+// HashWriterSize is the size of the scratch
+// buffer used by NewHashWriter. Small writes are
+// gathered in the buffer, large strings and byte
+// slices are written through.
+const HashWriterSize = 256
+
 func popWriter(w io.Writer) *Writer {
 	wr := writerPool.Get().(*Writer)
 	wr.Reset(w)
@@ -98,6 +109,14 @@ type Encodable interface {
 	EncodeMsg(*Writer) error
 }
 
+// HashEncodable is the interface implemented
+// by the types generated with -encode, which
+// write the output of MarshalHash using a
+// *hsp.Writer.
+type HashEncodable interface {
+	EncodeHash(*Writer) error
+}
+
 // Writer is a buffered writer
 // that can be used to write
 // MessagePack objects to an io.Writer.
@@ -118,6 +137,35 @@ func NewWriter(w io.Writer) *Writer {
 	return popWriter(w)
 }
 
+// NewHashWriter returns a *Writer with a small
+// pooled buffer, used by the generated WriteHash
+// methods to stream their encoding into w, usually
+// a hash.Hash. If w is already a *Writer it is
+// returned as is. The writer must be handed back
+// with ReleaseHashWriter.
+func NewHashWriter(w io.Writer) *Writer {
+	if wr, ok := w.(*Writer); ok {
+		return wr
+	}
+	wr := hashWriterPool.Get().(*Writer)
+	wr.Reset(w)
+	return wr
+}
+
+// ReleaseHashWriter flushes wr, returned by
+// NewHashWriter(w), and puts it back in the pool.
+// If wr is w itself, it is owned by the caller
+// and left untouched: the caller flushes it.
+func ReleaseHashWriter(wr *Writer, w io.Writer) error {
+	if ww, ok := w.(*Writer); ok && ww == wr {
+		return nil
+	}
+	err := wr.Flush()
+	wr.w = nil
+	hashWriterPool.Put(wr)
+	return err
+}
+
 // NewWriterSize returns a writer with a custom buffer size.
 func NewWriterSize(w io.Writer, sz int) *Writer {
 	// we must be able to require() 18
@@ -144,6 +192,18 @@ func Encode(w io.Writer, e Encodable) error {
 	return err
 }
 
+// EncodeHash encodes the MarshalHash output
+// of a HashEncodable to an io.Writer.
+func EncodeHash(w io.Writer, e HashEncodable) error {
+	wr := NewWriter(w)
+	err := e.EncodeHash(wr)
+	if err == nil {
+		err = wr.Flush()
+	}
+	freeW(wr)
+	return err
+}
+
 func (mw *Writer) flush() error {
 	if mw.wloc == 0 {
 		return nil
@@ -285,8 +345,22 @@ func (mw *Writer) writeString(s string) error {
 			return err
 		}
 		if l > len(mw.buf) {
-			_, err := io.WriteString(mw.w, s)
-			return err
+			if sw, ok := mw.w.(io.StringWriter); ok {
+				_, err := sw.WriteString(s)
+				return err
+			}
+			// copy through the buffer rather than
+			// converting s, hash.Hash has no WriteString
+			for {
+				mw.wloc = copy(mw.buf, s)
+				s = s[mw.wloc:]
+				if len(s) == 0 {
+					return nil
+				}
+				if err := mw.flush(); err != nil {
+					return err
+				}
+			}
 		}
 	}
 	mw.wloc += copy(mw.buf[mw.wloc:], s)
@@ -341,6 +415,33 @@ func (mw *Writer) WriteFloat32(f float32) error {
 	return mw.prefix32(mfloat32, math.Float32bits(f))
 }
 
+// WriteFloat64Canonical writes the canonical form
+// of a float64, see AppendFloat64Canonical
+func (mw *Writer) WriteFloat64Canonical(f float64) error {
+	return mw.WriteFloat64(CanonicalFloat64(f))
+}
+
+// WriteFloat32Canonical writes a float32 widened
+// to a float64, see AppendFloat32Canonical
+func (mw *Writer) WriteFloat32Canonical(f float32) error {
+	return mw.WriteFloat64Canonical(float64(f))
+}
+
+// WriteFloat64Integral writes a float64, or a whole
+// float64 as an integer, see AppendFloat64Integral
+func (mw *Writer) WriteFloat64Integral(f float64) error {
+	if i, ok := wholeInt64(f); ok {
+		return mw.WriteInt64(i)
+	}
+	return mw.WriteFloat64Canonical(f)
+}
+
+// WriteFloat32Integral writes a float32 widened
+// to a float64, see AppendFloat32Integral
+func (mw *Writer) WriteFloat32Integral(f float32) error {
+	return mw.WriteFloat64Integral(float64(f))
+}
+
 // WriteInt64 writes an int64 to the writer
 func (mw *Writer) WriteInt64(i int64) error {
 	if i >= 0 {
@@ -839,6 +940,12 @@ func GuessSize(i interface{}) int {
 			s += 2*StringPrefixSize + len(key) + len(val)
 		}
 		return s
+	case []interface{}:
+		s := ArrayHeaderSize
+		for _, val := range i {
+			s += GuessSize(val)
+		}
+		return s
 	default:
 		return 512
 	}
@@ -73,6 +73,62 @@ func AppendFloat32(b []byte, f float32) []byte {
 	return o
 }
 
+// canonicalNaN is the bit pattern of the only
+// NaN written by AppendFloat64Canonical
+const canonicalNaN = 0x7ff8000000000000
+
+// CanonicalFloat64 returns f with its NaNs collapsed
+// to a single quiet NaN, and -0 mapped to +0, which
+// is the value written by AppendFloat64Canonical.
+func CanonicalFloat64(f float64) float64 {
+	switch {
+	case f != f:
+		return math.Float64frombits(canonicalNaN)
+	case f == 0:
+		return 0
+	}
+	return f
+}
+
+// wholeInt64 returns f as an int64 if it is
+// a whole number within the range of an int64
+func wholeInt64(f float64) (int64, bool) {
+	if f >= -(1<<63) && f < 1<<63 && f == math.Trunc(f) {
+		return int64(f), true
+	}
+	return 0, false
+}
+
+// AppendFloat64Canonical appends the canonical
+// form of a float64, see CanonicalFloat64
+func AppendFloat64Canonical(b []byte, f float64) []byte {
+	return AppendFloat64(b, CanonicalFloat64(f))
+}
+
+// AppendFloat32Canonical appends a float32 widened
+// to a float64, so that it is written as the same
+// value held by a float64, see CanonicalFloat64
+func AppendFloat32Canonical(b []byte, f float32) []byte {
+	return AppendFloat64Canonical(b, float64(f))
+}
+
+// AppendFloat64Integral appends a float64 as
+// AppendFloat64Canonical does, except for the
+// whole numbers within the range of an int64,
+// which are appended as integers
+func AppendFloat64Integral(b []byte, f float64) []byte {
+	if i, ok := wholeInt64(f); ok {
+		return AppendInt64(b, i)
+	}
+	return AppendFloat64Canonical(b, f)
+}
+
+// AppendFloat32Integral appends a float32 widened
+// to a float64, see AppendFloat64Integral
+func AppendFloat32Integral(b []byte, f float32) []byte {
+	return AppendFloat64Integral(b, float64(f))
+}
+
 // AppendInt64 appends an int64 to the slice
 func AppendInt64(b []byte, i int64) []byte {
 	if i >= 0 {
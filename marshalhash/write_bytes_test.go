@@ -317,3 +317,79 @@ func BenchmarkAppendTime(b *testing.B) {
 		AppendTime(buf[0:0], t)
 	}
 }
+
+func TestAppendFloatCanonical(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	nan := math.Float64frombits(0x7ff8000000000001)
+	for _, c := range []struct {
+		f    float64
+		want float64
+	}{
+		{negZero, 0},
+		{nan, math.NaN()},
+		{math.Inf(-1), math.Inf(-1)},
+		{1.5, 1.5},
+	} {
+		var buf bytes.Buffer
+		en := NewWriter(&buf)
+		en.WriteFloat64Canonical(c.f)
+		en.Flush()
+		bts := AppendFloat64Canonical(nil, c.f)
+		if !bytes.Equal(buf.Bytes(), bts) {
+			t.Errorf("for float %v, encoder wrote %x; append wrote %x", c.f, buf.Bytes(), bts)
+		}
+		if want := AppendFloat64Canonical(nil, c.want); !bytes.Equal(bts, want) {
+			t.Errorf("for float %v, append wrote %x; want %x", c.f, bts, want)
+		}
+		if want := AppendFloat32Canonical(nil, float32(c.f)); !bytes.Equal(bts, want) {
+			t.Errorf("for float32 %v, append wrote %x; want %x", c.f, want, bts)
+		}
+		f, _, err := ReadFloat64CanonicalBytes(bts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !EqualFloatCanonical(f, c.f) {
+			t.Errorf("read %v, want %v", f, c.f)
+		}
+	}
+}
+
+func TestAppendFloatIntegral(t *testing.T) {
+	for _, c := range []struct {
+		f     float64
+		whole bool
+	}{
+		{0, true},
+		{math.Copysign(0, -1), true},
+		{-1, true},
+		{1e18, true},
+		{-(1 << 63), true},
+		{1 << 63, false},
+		{0.5, false},
+		{math.NaN(), false},
+		{math.Inf(1), false},
+	} {
+		var buf bytes.Buffer
+		en := NewWriter(&buf)
+		en.WriteFloat64Integral(c.f)
+		en.Flush()
+		bts := AppendFloat64Integral(nil, c.f)
+		if !bytes.Equal(buf.Bytes(), bts) {
+			t.Errorf("for float %v, encoder wrote %x; append wrote %x", c.f, buf.Bytes(), bts)
+		}
+		want := AppendFloat64Canonical(nil, c.f)
+		if c.whole {
+			want = AppendInt64(nil, int64(c.f))
+		}
+		if !bytes.Equal(bts, want) {
+			t.Errorf("for float %v, append wrote %x; want %x", c.f, bts, want)
+		}
+		f, o, err := ReadFloat64IntegralBytes(bts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(o) != 0 || !EqualFloatCanonical(f, c.f) {
+			t.Errorf("read %v, want %v", f, c.f)
+		}
+	}
+}
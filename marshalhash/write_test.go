@@ -2,6 +2,7 @@ package marshalhash
 
 import (
 	"bytes"
+	"io"
 	"math"
 	"math/rand"
 	"testing"
@@ -403,3 +404,75 @@ func BenchmarkWriteTime(b *testing.B) {
 		wr.WriteTime(t)
 	}
 }
+
+func TestHashWriter(t *testing.T) {
+	var buf bytes.Buffer
+	str := string(RandBytes(3 * HashWriterSize))
+	bin := RandBytes(HashWriterSize / 2)
+
+	want := AppendMapHeader(nil, 3)
+	want = AppendString(want, str)
+	want = AppendBytes(want, bin)
+	want = AppendFloat64(want, math.Pi)
+
+	// hide WriteString, as hash.Hash does
+	w := struct{ io.Writer }{&buf}
+	wr := NewHashWriter(w)
+	if len(wr.buf) != HashWriterSize {
+		t.Fatalf("buffer size %d, want %d", len(wr.buf), HashWriterSize)
+	}
+	wr.WriteMapHeader(3)
+	wr.WriteString(str)
+	wr.WriteBytes(bin)
+	wr.WriteFloat64(math.Pi)
+	if err := ReleaseHashWriter(wr, w); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("got %x, want %x", buf.Bytes(), want)
+	}
+
+	// a *Writer is used as is and
+	// left to its owner to flush
+	buf.Reset()
+	own := NewWriter(&buf)
+	if wr = NewHashWriter(own); wr != own {
+		t.Fatal("NewHashWriter did not reuse the *Writer")
+	}
+	wr.WriteNil()
+	if err := ReleaseHashWriter(wr, own); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 || own.wloc != 1 {
+		t.Fatalf("ReleaseHashWriter flushed a caller owned *Writer")
+	}
+}
+
+// hashPair writes its fields as MarshalHash does
+type hashPair struct {
+	A string
+	B uint32
+}
+
+func (p *hashPair) EncodeHash(w *Writer) error {
+	if err := w.WriteMapHeader(2); err != nil {
+		return err
+	}
+	if err := w.WriteString(p.A); err != nil {
+		return err
+	}
+	return w.WriteUint32(p.B)
+}
+
+func TestEncodeHashEncodable(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeHash(&buf, &hashPair{A: "a", B: 300}); err != nil {
+		t.Fatal(err)
+	}
+	want := AppendMapHeader(nil, 2)
+	want = AppendString(want, "a")
+	want = AppendUint32(want, 300)
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("got %x, want %x", buf.Bytes(), want)
+	}
+}
@@ -0,0 +1,92 @@
+package parse
+
+import (
+	"go/ast"
+	"strconv"
+
+	"github.com/CovenantSQL/HashStablePack/gen"
+)
+
+const marshalhashPath = "github.com/CovenantSQL/HashStablePack/marshalhash"
+
+// digestCacheType returns the name of the
+// hsp.DigestCache type as imported by f, or ""
+// if f doesn't import the marshalhash package
+func digestCacheType(imports []*ast.ImportSpec) string {
+	return marshalhashType(imports, "DigestCache")
+}
+
+// marshalhashType returns the name of the type
+// name of the marshalhash package as imported
+// by f, or "" if f doesn't import the package
+func marshalhashType(imports []*ast.ImportSpec, name string) string {
+	for _, imp := range imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == marshalhashPath {
+			if imp.Name != nil {
+				return imp.Name.Name + "." + name
+			}
+			return "marshalhash." + name
+		}
+	}
+	return ""
+}
+
+// isDigestCache returns whether a field has the
+// hsp.DigestCache type; such fields are never hashed
+func (fs *FileSet) isDigestCache(f *ast.Field) bool {
+	typ := digestCacheType(fs.Imports)
+	return typ != "" && stringify(f.Type) == typ
+}
+
+// findCacheFields records the hsp.DigestCache field
+// of the struct types declared in f, see //hsp:cache.
+// It runs before unexported fields are dropped, so
+// that the cache field can stay unexported.
+func (fs *FileSet) findCacheFields(f *ast.File) {
+	typ := digestCacheType(f.Imports)
+	if typ == "" {
+		return
+	}
+	for _, d := range f.Decls {
+		g, ok := d.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, s := range g.Specs {
+			ts, ok := s.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || st.Fields == nil {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				if len(field.Names) == 1 && stringify(field.Type) == typ {
+					if fs.CacheFields == nil {
+						fs.CacheFields = make(map[string]string)
+					}
+					fs.CacheFields[ts.Name.Name] = field.Names[0].Name
+				}
+			}
+		}
+	}
+}
+
+// cacheDigests sets the digest algorithm of
+// the cached types that have none to sha256,
+// CachedDigest being built on Digest
+func (fs *FileSet) cacheDigests() {
+	for name, el := range fs.Identities {
+		if st, ok := el.(*gen.Struct); !ok || st.CacheField == "" {
+			continue
+		}
+		if fs.Digests[name] != "" || fs.Digests[""] != "" {
+			continue
+		}
+		if fs.Digests == nil {
+			fs.Digests = make(map[string]string)
+		}
+		fs.Digests[name] = "sha256"
+	}
+}
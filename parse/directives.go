@@ -3,6 +3,7 @@ package parse
 import (
 	"fmt"
 	"go/ast"
+	"strconv"
 	"strings"
 
 	"github.com/CovenantSQL/HashStablePack/gen"
@@ -21,9 +22,15 @@ type passDirective func(gen.Method, []string, *gen.Printer) error
 // to add a directive, define a func([]string, *FileSet) error
 // and then add it to this list.
 var directives = map[string]directive{
-	"shim":   applyShim,
-	"ignore": ignore,
-	"tuple":  astuple,
+	"shim":    applyShim,
+	"ignore":  ignore,
+	"tuple":   astuple,
+	"nesting": nesting,
+	"digest":  digest,
+	"cache":   cache,
+	"golden":  goldenSamples,
+	"float":   floatMode,
+	"domain":  domain,
 }
 
 var passDirectives = map[string]passDirective{
@@ -128,3 +135,179 @@ func astuple(text []string, f *FileSet) error {
 	}
 	return nil
 }
+
+//hsp:nesting {bin|inline}
+func nesting(text []string, f *FileSet) error {
+	if len(text) != 2 {
+		return fmt.Errorf("nesting directive should have 1 argument; found %d", len(text)-1)
+	}
+	switch mode := strings.TrimSpace(text[1]); mode {
+	case "bin":
+		f.NestInline = false
+	case "inline":
+		f.NestInline = true
+	default:
+		return fmt.Errorf("invalid nesting mode; found %s, expected 'bin' or 'inline'", mode)
+	}
+	infoln(text[1])
+	return nil
+}
+
+//hsp:digest {sha256|sha512_256|blake2b} {TypeA} {TypeB}...
+func digest(text []string, f *FileSet) error {
+	// without type names, the algorithm is used by all types
+	if len(text) < 2 {
+		return fmt.Errorf("digest directive should have at least 1 argument; found %d", len(text)-1)
+	}
+	algo := strings.TrimSpace(text[1])
+	if gen.DigestImport(algo) == "" {
+		return fmt.Errorf("invalid digest algorithm; found %s, expected 'sha256', 'sha512_256' or 'blake2b'", algo)
+	}
+	if f.Digests == nil {
+		f.Digests = make(map[string]string)
+	}
+	if len(text) == 2 {
+		f.Digests[""] = algo
+		infoln(algo)
+		return nil
+	}
+	for _, item := range text[2:] {
+		name := strings.TrimSpace(item)
+		if _, ok := f.Identities[name]; !ok {
+			return fmt.Errorf("%s: no such type", name)
+		}
+		f.Digests[name] = algo
+		infof("%s: %s\n", name, algo)
+	}
+	return nil
+}
+
+//hsp:cache {TypeA} {TypeB}... with:setters
+func cache(text []string, f *FileSet) error {
+	if len(text) < 2 {
+		return fmt.Errorf("cache directive should have at least 1 argument; found 0")
+	}
+	names := text[1:]
+	setters := false
+	if last := strings.TrimSpace(names[len(names)-1]); strings.HasPrefix(last, "with:") {
+		if last != "with:setters" {
+			return fmt.Errorf("invalid cache option; found %s, expected 'with:setters'", last)
+		}
+		setters = true
+		names = names[:len(names)-1]
+		if len(names) == 0 {
+			return fmt.Errorf("cache directive should name at least 1 type")
+		}
+	}
+	for _, item := range names {
+		name := strings.TrimSpace(item)
+		el, ok := f.Identities[name]
+		if !ok {
+			return fmt.Errorf("%s: no such type", name)
+		}
+		st, ok := el.(*gen.Struct)
+		if !ok {
+			return fmt.Errorf("%s: only structs can be cached", name)
+		}
+		field, ok := f.CacheFields[name]
+		if !ok {
+			return fmt.Errorf("%s: declare a field of type hsp.DigestCache to cache its digest", name)
+		}
+		st.CacheField = field
+		st.CacheSetters = setters
+		infoln(name)
+	}
+	return nil
+}
+
+//hsp:golden {Type} {FuncA} {FuncB}...
+func goldenSamples(text []string, f *FileSet) error {
+	if len(text) < 3 {
+		return fmt.Errorf("golden directive should have at least 2 arguments; found %d", len(text)-1)
+	}
+	name := strings.TrimSpace(text[1])
+	if _, ok := f.Identities[name]; !ok {
+		return fmt.Errorf("%s: no such type", name)
+	}
+	if f.GoldenSamples == nil {
+		f.GoldenSamples = make(map[string][]string)
+	}
+	for _, item := range text[2:] {
+		f.GoldenSamples[name] = append(f.GoldenSamples[name], strings.TrimSpace(item))
+	}
+	infof("%s: %s\n", name, strings.Join(f.GoldenSamples[name], ", "))
+	return nil
+}
+
+//hsp:float {canonical|integral} {TypeA} {TypeB}...
+func floatMode(text []string, f *FileSet) error {
+	// without type names, the mode is used by all types
+	if len(text) < 2 {
+		return fmt.Errorf("float directive should have at least 1 argument; found %d", len(text)-1)
+	}
+	var mode gen.FloatMode
+	switch m := strings.TrimSpace(text[1]); m {
+	case "canonical":
+		mode = gen.CanonicalFloat
+	case "integral":
+		mode = gen.IntegralFloat
+	default:
+		return fmt.Errorf("invalid float mode; found %s, expected 'canonical' or 'integral'", m)
+	}
+	if len(text) == 2 {
+		for _, el := range f.Identities {
+			gen.SetFloatMode(el, mode)
+		}
+		infoln(text[1])
+		return nil
+	}
+	for _, item := range text[2:] {
+		name := strings.TrimSpace(item)
+		el, ok := f.Identities[name]
+		if !ok {
+			return fmt.Errorf("%s: no such type", name)
+		}
+		if !gen.SetFloatMode(el, mode) {
+			warnf("%s: holds no float\n", name)
+			continue
+		}
+		infof("%s: %s\n", name, text[1])
+	}
+	return nil
+}
+
+//hsp:domain {Type} {"domain"}
+func domain(text []string, f *FileSet) error {
+	if len(text) < 3 {
+		return fmt.Errorf("domain directive should have 2 arguments; found %d", len(text)-1)
+	}
+	name := strings.TrimSpace(text[1])
+	// the domain may hold spaces
+	tag := strings.TrimSpace(strings.Join(text[2:], " "))
+	if s, err := strconv.Unquote(tag); err == nil {
+		tag = s
+	}
+	if tag == "" {
+		return fmt.Errorf("%s: empty domain", name)
+	}
+	el, ok := f.Identities[name]
+	if !ok {
+		return fmt.Errorf("%s: no such type", name)
+	}
+	st, ok := el.(*gen.Struct)
+	if !ok {
+		return fmt.Errorf("%s: only structs can have a domain", name)
+	}
+	for other, el := range f.Identities {
+		if o, ok := el.(*gen.Struct); ok && other != name && o.Domain == tag {
+			return fmt.Errorf("%s: domain %q is also the one of %s", name, tag, other)
+		}
+	}
+	st.Domain = tag
+	// the domain changes the encoding
+	if st.Versioning {
+		st.ComputeVersion()
+	}
+	infof("%s: %q\n", name, tag)
+	return nil
+}
@@ -66,11 +66,17 @@ func ParseOldGenFile(f string, versionTypes []*gen.Struct) (err error) {
 		}
 	}
 
+	// types already converted to new version
+	converted := map[*gen.Struct]bool{}
+	for _, st := range versionTypes {
+		converted[st] = len(st.VersionList) > 0
+	}
+
 	for i := range fl.Decls {
 		if fk, ok := fl.Decls[i].(*ast.FuncDecl); ok {
 			fn := fk.Name.String()
 
-			if fn != "MarshalHash" && fn != "Msgsize" {
+			if fn != "MarshalHash" && fn != "Msgsize" && fn != "AppendHash" {
 				continue
 			}
 
@@ -90,7 +96,7 @@ func ParseOldGenFile(f string, versionTypes []*gen.Struct) (err error) {
 					continue
 				}
 
-				if len(genType.VersionList) > 0 {
+				if converted[genType] {
 					// already converted to new version
 					continue
 				}
@@ -102,14 +108,19 @@ func ParseOldGenFile(f string, versionTypes []*gen.Struct) (err error) {
 					genType.OldMarshalBody = fBytes.String()
 				} else if fn == "Msgsize" {
 					genType.OldMsgSizeBody = fBytes.String()
-				}
-
-				if genType.OldMarshalBody != "" && genType.OldMsgSizeBody != "" {
-					genType.VersionList = append(genType.VersionList, "oldver")
+				} else if fn == "AppendHash" {
+					// generated with //hsp:nesting inline
+					genType.OldAppendBody = fBytes.String()
 				}
 			}
 		}
 	}
 
+	for _, st := range versionTypes {
+		if !converted[st] && st.OldMarshalBody != "" && st.OldMsgSizeBody != "" {
+			st.VersionList = append(st.VersionList, "oldver")
+		}
+	}
+
 	return
 }
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/ttacon/chalk"
+	"golang.org/x/tools/go/packages"
 
 	"github.com/CovenantSQL/HashStablePack/gen"
 )
@@ -19,11 +20,22 @@ import (
 // A FileSet is the in-memory representation of a
 // parsed file.
 type FileSet struct {
-	Package    string              // package name
-	Specs      map[string]ast.Expr // type specs in file
-	Identities map[string]gen.Elem // processed from specs
-	Directives []string            // raw preprocessor directives
-	Imports    []*ast.ImportSpec   // imports
+	Package       string              // package name
+	Specs         map[string]ast.Expr // type specs in file
+	Identities    map[string]gen.Elem // processed from specs
+	Directives    []string            // raw preprocessor directives
+	Imports       []*ast.ImportSpec   // imports
+	NestInline    bool                // append nested types in place, see //hsp:nesting
+	Digests       map[string]string   // digest algorithm per type name, "" for all, see //hsp:digest
+	CacheFields   map[string]string   // hsp.DigestCache field per struct name, see //hsp:cache
+	GoldenSamples map[string][]string // sample functions per type name, see //hsp:golden
+	MerkleDigests map[string]bool     // digest algorithms of the fields tagged merkle
+	signed        map[string]signSpec // fields tagged signature and signer per struct name
+	pkg           *packages.Package   // type checked package, see TypedFile
+	flattening    map[string]bool     // embedded structs being flattened, see flatten
+	generics      map[string][]string // type parameters per generic type name
+	params        map[string]bool     // type parameters of the spec being parsed
+	err           error               // first error found in the specs
 }
 
 // File parses a file at the relative path
@@ -33,6 +45,24 @@ type FileSet struct {
 // If unexport is false, only exported identifiers are included in the FileSet.
 // If the resulting FileSet would be empty, an error is returned.
 func File(name string, unexported bool) (*FileSet, error) {
+	return parseFile(name, unexported, false)
+}
+
+// TypedFile is like File, but the identifiers that
+// are not declared in the parsed files are resolved
+// by type checking their package: named types with
+// a simple underlying type, like
+//
+//	type NodeID string
+//
+// are written as that type, and an error is returned
+// for the types of other packages that have no
+// MarshalHash method.
+func TypedFile(name string, unexported bool) (*FileSet, error) {
+	return parseFile(name, unexported, true)
+}
+
+func parseFile(name string, unexported, typed bool) (*FileSet, error) {
 	pushstate(name)
 	defer popstate()
 	fs := &FileSet{
@@ -40,6 +70,14 @@ func File(name string, unexported bool) (*FileSet, error) {
 		Identities: make(map[string]gen.Elem),
 	}
 
+	if typed {
+		pkg, err := loadPackage(name)
+		if err != nil {
+			return nil, err
+		}
+		fs.pkg = pkg
+	}
+
 	fset := token.NewFileSet()
 	finfo, err := os.Stat(name)
 	if err != nil {
@@ -62,6 +100,8 @@ func File(name string, unexported bool) (*FileSet, error) {
 		for _, fl := range one.Files {
 			pushstate(fl.Name.Name)
 			fs.Directives = append(fs.Directives, yieldComments(fl.Comments)...)
+			fs.findCacheFields(fl)
+			fs.findSignFields(fl)
 			if !unexported {
 				ast.FileExports(fl)
 			}
@@ -75,6 +115,8 @@ func File(name string, unexported bool) (*FileSet, error) {
 		}
 		fs.Package = f.Name.Name
 		fs.Directives = yieldComments(f.Comments)
+		fs.findCacheFields(f)
+		fs.findSignFields(f)
 		if !unexported {
 			ast.FileExports(f)
 		}
@@ -86,8 +128,22 @@ func File(name string, unexported bool) (*FileSet, error) {
 	}
 
 	fs.process()
+	if fs.err != nil {
+		return nil, fs.err
+	}
+	if fs.pkg != nil {
+		if err := fs.resolveTypes(); err != nil {
+			return nil, err
+		}
+	}
 	fs.applyDirectives()
+	fs.cacheDigests()
+	fs.signTypes()
+	fs.merkleDigests()
 	fs.propInline()
+	if fs.err != nil {
+		return nil, fs.err
+	}
 
 	return fs, nil
 }
@@ -102,9 +158,8 @@ func (f *FileSet) applyDirectives() {
 		if len(chunks) > 0 {
 			if fn, ok := directives[chunks[0]]; ok {
 				pushstate(chunks[0])
-				err := fn(chunks, f)
-				if err != nil {
-					warnln(err.Error())
+				if err := fn(chunks, f); err != nil {
+					f.fail(err)
 				}
 				popstate()
 			} else {
@@ -179,12 +234,29 @@ parse:
 	for _, name := range names {
 		def := f.Specs[name]
 		pushstate(name)
+		params := f.generics[name]
+		f.params = make(map[string]bool, len(params))
+		for _, p := range params {
+			f.params[p] = true
+		}
 		el := f.parseExpr(def)
+		f.params = nil
 		if el == nil {
 			warnln("failed to parse")
 			popstate()
 			continue parse
 		}
+		if len(params) > 0 {
+			// see gen/generic.go
+			if st, ok := el.(*gen.Struct); ok && st.Versioning {
+				warnln("versioning is not supported for generic types, ignored.")
+				st.Versioning = false
+			}
+			el.Alias(name + "[" + strings.Join(params, ", ") + "]")
+			f.Identities[name] = el
+			popstate()
+			continue parse
+		}
 		// push unresolved identities into
 		// the graph of links and resolve after
 		// we've handled every possible named type.
@@ -211,12 +283,21 @@ func strToMethod(s string) gen.Method {
 		return gen.Size
 	case "marshal":
 		return gen.Marshal
+	case "unmarshal":
+		return gen.Unmarshal
 	default:
 		return 0
 	}
 }
 
 func (f *FileSet) applyDirs(p *gen.Printer) {
+	for name, algo := range f.Digests {
+		p.Digest(name, algo)
+	}
+	for name, funcs := range f.GoldenSamples {
+		p.GoldenSamples(name, funcs)
+	}
+
 	// apply directives of the form
 	//
 	// 	//hsp:encode ignore {{TypeName}}
@@ -262,6 +343,46 @@ func (f *FileSet) PrintVersion(s *gen.Struct, p *gen.Printer, v string) error {
 	return err
 }
 
+// DigestImports returns the quoted import paths
+// needed by the Digest methods of the file, and
+// by the Merkle trees of the fields tagged merkle.
+func (f *FileSet) DigestImports() []string {
+	var out []string
+	for _, algo := range f.Digests {
+		out = append(out, gen.DigestImport(algo))
+	}
+	for algo := range f.MerkleDigests {
+		out = append(out, gen.DigestImport(algo))
+	}
+	// for the crypto.Signer of SignHash
+	if len(f.signed) > 0 {
+		out = append(out, `"crypto"`)
+	}
+	return out
+}
+
+// merkleDigests sets the digest algorithm of the
+// Merkle trees of the fields tagged merkle to the
+// one of their type, or sha256 if it has none
+func (fs *FileSet) merkleDigests() {
+	for name, el := range fs.Identities {
+		algo := fs.Digests[name]
+		if algo == "" {
+			algo = fs.Digests[""]
+		}
+		if algo == "" {
+			algo = "sha256"
+		}
+		if !gen.SetMerkleDigest(el, algo) {
+			continue
+		}
+		if fs.MerkleDigests == nil {
+			fs.MerkleDigests = make(map[string]bool)
+		}
+		fs.MerkleDigests[algo] = true
+	}
+}
+
 func (f *FileSet) PrintTo(p *gen.Printer) error {
 	f.applyDirs(p)
 	names := make([]string, 0, len(f.Identities))
@@ -310,7 +431,9 @@ func (fs *FileSet) getTypeSpecs(f *ast.File) {
 						*ast.MapType,
 						*ast.Ident:
 						fs.Specs[ts.Name.Name] = ts.Type
-
+						if ts.TypeParams != nil {
+							fs.getTypeParams(f, ts)
+						}
 					}
 				}
 			}
@@ -339,18 +462,139 @@ func (fs *FileSet) parseFieldList(fl *ast.FieldList) []gen.StructField {
 		fds := fs.getField(field)
 		if len(fds) > 0 {
 			out = append(out, fds...)
-		} else {
+		} else if signOption(field) == "" {
 			warnln("ignored.")
 		}
 		popstate()
 	}
+
+	// the flattened fields, named by their path,
+	// must not take the place of another field
+	tags := make(map[string]string, len(out))
+	for _, sf := range out {
+		other, ok := tags[sf.FieldTag]
+		if ok && (strings.Contains(sf.FieldName, ".") || strings.Contains(other, ".")) {
+			fs.fail(fmt.Errorf("inline: tag %q of %s collides with %s", sf.FieldTag, sf.FieldName, other))
+		}
+		tags[sf.FieldTag] = sf.FieldName
+	}
 	return out
 }
 
+// flatten returns the fields of the struct embedded
+// by f as fields of the parent struct, named by their
+// path, e.g. Header.Height
+func (fs *FileSet) flatten(f *ast.Field) []gen.StructField {
+	name := embedded(f.Type)
+	_, isIdent := f.Type.(*ast.Ident)
+	st, isStruct := fs.Specs[name].(*ast.StructType)
+	if len(f.Names) != 0 || !isIdent || !isStruct {
+		fs.fail(fmt.Errorf("inline: %s is not an embedded struct declared alongside", fieldName(f)))
+		return nil
+	}
+	if fs.flattening[name] {
+		fs.fail(fmt.Errorf("inline: %s embeds itself", name))
+		return nil
+	}
+	if fs.flattening == nil {
+		fs.flattening = make(map[string]bool)
+	}
+	fs.flattening[name] = true
+	defer delete(fs.flattening, name)
+
+	fields := fs.parseFieldList(st.Fields)
+	for i := range fields {
+		fields[i].FieldName = name + "." + fields[i].FieldName
+	}
+	return fields
+}
+
+// fail records the first error
+// found while parsing the specs
+func (fs *FileSet) fail(err error) {
+	warnln(err.Error())
+	if fs.err == nil {
+		fs.err = err
+	}
+}
+
+// getTypeParams records the type parameters of
+// the generic type ts of f, whose constraint must
+// implement hsp.HashMarshaler
+func (fs *FileSet) getTypeParams(f *ast.File, ts *ast.TypeSpec) {
+	if fs.generics == nil {
+		fs.generics = make(map[string][]string)
+	}
+	var params []string
+	for _, field := range ts.TypeParams.List {
+		for _, nm := range field.Names {
+			if !isHashMarshaler(f, field.Type, nil) {
+				fs.fail(fmt.Errorf("%s: type parameter %s: constraint %s doesn't implement hsp.HashMarshaler",
+					ts.Name.Name, nm.Name, stringify(field.Type)))
+			}
+			params = append(params, nm.Name)
+		}
+	}
+	fs.generics[ts.Name.Name] = params
+}
+
+// isHashMarshaler returns whether the constraint c
+// is hsp.HashMarshaler, as imported by f, or an
+// interface of f embedding it; seen holds the
+// interfaces being resolved
+func isHashMarshaler(f *ast.File, c ast.Expr, seen map[string]bool) bool {
+	if typ := marshalhashType(f.Imports, "HashMarshaler"); typ != "" && stringify(c) == typ {
+		return true
+	}
+	id, ok := c.(*ast.Ident)
+	if !ok || seen[id.Name] {
+		return false
+	}
+	it := interfaceSpec(f, id.Name)
+	if it == nil {
+		return false
+	}
+	if seen == nil {
+		seen = make(map[string]bool)
+	}
+	seen[id.Name] = true
+	for _, m := range it.Methods.List {
+		// embedded interfaces have no names
+		if len(m.Names) == 0 && isHashMarshaler(f, m.Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// interfaceSpec returns the interface
+// type name declared in f, or nil
+func interfaceSpec(f *ast.File, name string) *ast.InterfaceType {
+	for _, d := range f.Decls {
+		g, ok := d.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, s := range g.Specs {
+			if ts, ok := s.(*ast.TypeSpec); ok && ts.Name.Name == name {
+				it, _ := ts.Type.(*ast.InterfaceType)
+				return it
+			}
+		}
+	}
+	return nil
+}
+
 // translate *ast.Field into []gen.StructField
 func (fs *FileSet) getField(f *ast.Field) []gen.StructField {
+	// the digest cache is not part of the hash
+	if fs.isDigestCache(f) {
+		return nil
+	}
 	sf := make([]gen.StructField, 1)
-	var extension bool
+	var extension, omitempty, inline, merkle, signed bool
+	var float gen.FloatMode
+	var clock string
 	// parse tag; otherwise field name is field tag
 	if f.Tag != nil {
 		body := reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("hsp")
@@ -358,16 +602,38 @@ func (fs *FileSet) getField(f *ast.Field) []gen.StructField {
 			body = reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("hspack")
 		}
 		tags := strings.Split(body, ",")
-		if len(tags) == 2 && tags[1] == "extension" {
-			extension = true
-		}
-		if len(tags) == 2 && tags[1] == "version" {
-			sf[0].VersionField = true
+		for _, opt := range tags[1:] {
+			switch opt {
+			case "extension":
+				extension = true
+			case "version":
+				sf[0].VersionField = true
+			case "omitempty":
+				omitempty = true
+			case "inline":
+				inline = true
+			case "canonical":
+				float = gen.CanonicalFloat
+			case "integral":
+				float = gen.IntegralFloat
+			case "merkle":
+				merkle = true
+			case "signature", "signer":
+				signed = true
+			default:
+				if strings.HasPrefix(opt, "time=") {
+					clock = strings.TrimPrefix(opt, "time=")
+				}
+			}
 		}
-		// ignore "-" fields
-		if tags[0] == "-" {
+		// ignore "-" fields, and the signature
+		// fields, see findSignFields
+		if tags[0] == "-" || signed {
 			return nil
 		}
+		if inline {
+			return fs.flatten(f)
+		}
 		sf[0].FieldTag = tags[0]
 		sf[0].RawTag = f.Tag.Value
 	}
@@ -376,6 +642,26 @@ func (fs *FileSet) getField(f *ast.Field) []gen.StructField {
 	if ex == nil {
 		return nil
 	}
+	if float != gen.RawFloat && !gen.SetFloatMode(ex, float) {
+		warnf("%s holds no float, %s ignored.\n", ex.TypeName(), strings.ToLower(float.String()))
+	}
+	if merkle {
+		if gen.CanMerkle(ex) {
+			// the algorithm is set by merkleDigests
+			ex.(*gen.Slice).Merkle = "sha256"
+		} else {
+			warnf("%s is not a slice of types with MarshalHash, merkle ignored.\n", ex.TypeName())
+		}
+	}
+	if clock != "" {
+		mode, ok := gen.ParseTimeMode(clock)
+		switch {
+		case !ok:
+			warnf("invalid time encoding %s, expected 'unix', 'unix_ms', 'unix_us' or 'rfc3339'.\n", clock)
+		case !gen.SetTimeMode(ex, mode):
+			warnf("%s holds no time.Time, time=%s ignored.\n", ex.TypeName(), clock)
+		}
+	}
 
 	// parse field name
 	switch len(f.Names) {
@@ -401,6 +687,18 @@ func (fs *FileSet) getField(f *ast.Field) []gen.StructField {
 		sf[0].FieldTag = sf[0].FieldName
 	}
 
+	// validate omitempty
+	if omitempty {
+		switch {
+		case sf[0].VersionField:
+			warnln("version field can't be omitted, omitempty ignored.")
+		case extension || !gen.CanOmitEmpty(ex):
+			warnf("%s has no empty value, omitempty ignored.\n", ex.TypeName())
+		default:
+			sf[0].OmitEmpty = true
+		}
+	}
+
 	// validate extension
 	if extension {
 		switch ex := ex.(type) {
@@ -462,6 +760,14 @@ func stringify(e ast.Expr) string {
 		if e.Methods == nil || e.Methods.NumFields() == 0 {
 			return "interface{}"
 		}
+	case *ast.IndexExpr:
+		return stringify(e.X) + "[" + stringify(e.Index) + "]"
+	case *ast.IndexListExpr:
+		args := make([]string, len(e.Indices))
+		for i := range e.Indices {
+			args[i] = stringify(e.Indices[i])
+		}
+		return stringify(e.X) + "[" + strings.Join(args, ", ") + "]"
 	}
 	return "<BAD>"
 }
@@ -474,26 +780,35 @@ func stringify(e ast.Expr) string {
 // - *ast.StarExpr (*T)
 // - *ast.StructType (struct {})
 // - *ast.SelectorExpr (a.B)
+// - *ast.IndexExpr, *ast.IndexListExpr (A[T], A[K, V])
 // - *ast.InterfaceType (interface {})
 func (fs *FileSet) parseExpr(e ast.Expr) gen.Elem {
 	switch e := e.(type) {
 
 	case *ast.MapType:
-		if k, ok := e.Key.(*ast.Ident); ok && k.Name == "string" {
-			if in := fs.parseExpr(e.Value); in != nil {
-				return &gen.Map{Value: in}
-			}
+		key := fs.parseExpr(e.Key)
+		if key == nil || gen.MapKeyOrder(key) == gen.InvalidKeyOrder {
+			warnf("unsupported map key type: %s\n", stringify(e.Key))
+			return nil
+		}
+		if in := fs.parseExpr(e.Value); in != nil {
+			return &gen.Map{Key: key, Value: in}
 		}
 		return nil
 
 	case *ast.Ident:
+		if fs.params[e.Name] {
+			b := gen.Ident(e.Name)
+			b.TypeParam = true
+			return b
+		}
 		b := gen.Ident(e.Name)
 
 		// work to resove this expression
 		// can be done later, once we've resolved
 		// everything else.
 		if b.Value == gen.IDENT {
-			if _, ok := fs.Specs[e.Name]; !ok {
+			if _, ok := fs.Specs[e.Name]; !ok && fs.pkg == nil {
 				warnf("non-local identifier: %s\n", e.Name)
 			}
 		}
@@ -568,6 +883,10 @@ func (fs *FileSet) parseExpr(e ast.Expr) gen.Elem {
 	case *ast.SelectorExpr:
 		return gen.Ident(stringify(e))
 
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		// instantiation of a generic type
+		return gen.Ident(stringify(e))
+
 	case *ast.InterfaceType:
 		// support `interface{}`
 		if len(e.Methods.List) == 0 {
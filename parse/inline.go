@@ -50,6 +50,7 @@ func (f *FileSet) findShim(id string, be *gen.BaseElem) {
 		case *gen.Slice:
 			f.nextShim(&el.Els, id, be)
 		case *gen.Map:
+			f.nextShim(&el.Key, id, be)
 			f.nextShim(&el.Value, id, be)
 		case *gen.Ptr:
 			f.nextShim(&el.Value, id, be)
@@ -76,6 +77,7 @@ func (f *FileSet) nextShim(ref *gen.Elem, id string, be *gen.BaseElem) {
 		case *gen.Slice:
 			f.nextShim(&el.Els, id, be)
 		case *gen.Map:
+			f.nextShim(&el.Key, id, be)
 			f.nextShim(&el.Value, id, be)
 		case *gen.Ptr:
 			f.nextShim(&el.Value, id, be)
@@ -103,6 +105,7 @@ func (f *FileSet) propInline() {
 		case *gen.Slice:
 			f.nextInline(&el.Els, name)
 		case *gen.Map:
+			f.nextInline(&el.Key, name)
 			f.nextInline(&el.Value, name)
 		case *gen.Ptr:
 			f.nextInline(&el.Value, name)
@@ -122,8 +125,13 @@ func (f *FileSet) nextInline(ref *gen.Elem, root string) {
 		// ensure that we're not inlining
 		// a type into itself
 		typ := el.TypeName()
-		if el.Value == gen.IDENT && typ != root {
-			if node, ok := f.Identities[typ]; ok && node.Complexity() < maxComplex {
+		if el.Value == gen.IDENT && !el.TypeParam {
+			node, ok := f.Identities[gen.BaseName(typ)]
+			if ok && gen.BaseName(typ) != typ {
+				// instantiations of the generic types
+				// are never inlined, see gen.BaseName
+				el.Local = true
+			} else if ok && typ != root && node.Complexity() < maxComplex {
 				infof("inlining %s\n", typ)
 
 				// This should never happen; it will cause
@@ -134,10 +142,16 @@ func (f *FileSet) nextInline(ref *gen.Elem, root string) {
 
 				*ref = node.Copy()
 				f.nextInline(ref, node.TypeName())
-			} else if !ok && !el.Resolved() {
+			} else if ok {
+				// a processed type that is too complex
+				// to inline; its methods are generated
+				// alongside the caller's
+				el.Local = true
+			} else if !el.Resolved() && (f.pkg == nil || f.lookupType(gen.BaseName(typ)) == nil) {
 				// this is the point at which we're sure that
 				// we've got a type that isn't a primitive,
-				// a library builtin, or a processed type
+				// a library builtin, a processed type, or a
+				// type checked one, see resolveTypes
 				warnf("unresolved identifier: %s\n", typ)
 			}
 		}
@@ -150,6 +164,7 @@ func (f *FileSet) nextInline(ref *gen.Elem, root string) {
 	case *gen.Slice:
 		f.nextInline(&el.Els, root)
 	case *gen.Map:
+		f.nextInline(&el.Key, root)
 		f.nextInline(&el.Value, root)
 	case *gen.Ptr:
 		f.nextInline(&el.Value, root)
@@ -0,0 +1,122 @@
+package parse
+
+import (
+	"go/ast"
+	"reflect"
+	"strings"
+
+	"github.com/CovenantSQL/HashStablePack/gen"
+)
+
+// signSpec names the fields of a struct tagged
+// signature and signer, see findSignFields
+type signSpec struct {
+	signature  string
+	signer     string
+	signerType string
+}
+
+// signOption returns the option of the hsp tag
+// of f marking it as the signature or the signer
+// of its struct, or ""
+func signOption(f *ast.Field) string {
+	if f.Tag == nil {
+		return ""
+	}
+	tag := reflect.StructTag(strings.Trim(f.Tag.Value, "`"))
+	body := tag.Get("hsp")
+	if body == "" {
+		body = tag.Get("hspack")
+	}
+	for _, opt := range strings.Split(body, ",")[1:] {
+		if opt == "signature" || opt == "signer" {
+			return opt
+		}
+	}
+	return ""
+}
+
+// findSignFields records the fields tagged signature
+// and signer of the struct types declared in f, which
+// are not hashed, and set by SignHash. Like
+// findCacheFields, it runs before unexported fields
+// are dropped.
+func (fs *FileSet) findSignFields(f *ast.File) {
+	for _, d := range f.Decls {
+		g, ok := d.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, s := range g.Specs {
+			ts, ok := s.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || st.Fields == nil {
+				continue
+			}
+			name := ts.Name.Name
+			var sf signSpec
+			valid := true
+			for _, field := range st.Fields.List {
+				opt := signOption(field)
+				if opt == "" {
+					continue
+				}
+				if len(field.Names) != 1 {
+					warnf("%s: the %s field must have a name\n", name, opt)
+					valid = false
+					continue
+				}
+				switch {
+				case opt == "signature" && sf.signature != "", opt == "signer" && sf.signer != "":
+					warnf("%s: more than one %s field\n", name, opt)
+					valid = false
+				case opt == "signature" && stringify(field.Type) != "[]byte":
+					warnf("%s: the signature field %s is not a []byte\n", name, field.Names[0].Name)
+					valid = false
+				case opt == "signature":
+					sf.signature = field.Names[0].Name
+				default:
+					sf.signer = field.Names[0].Name
+					sf.signerType = stringify(field.Type)
+				}
+			}
+			if !valid || sf == (signSpec{}) {
+				continue
+			}
+			if sf.signature == "" || sf.signer == "" {
+				warnf("%s: a signature field needs a signer field, and conversely\n", name)
+				continue
+			}
+			if fs.signed == nil {
+				fs.signed = make(map[string]signSpec)
+			}
+			fs.signed[name] = sf
+		}
+	}
+}
+
+// signTypes sets the signature fields of the
+// signed structs, and the digest algorithm of
+// the ones that have none to sha256, SignHash
+// being built on Digest
+func (fs *FileSet) signTypes() {
+	for name, sf := range fs.signed {
+		st, ok := fs.Identities[name].(*gen.Struct)
+		if !ok {
+			continue
+		}
+		st.SignatureField = sf.signature
+		st.SignerField = sf.signer
+		st.SignerType = sf.signerType
+		if fs.Digests[name] != "" || fs.Digests[""] != "" {
+			continue
+		}
+		if fs.Digests == nil {
+			fs.Digests = make(map[string]string)
+		}
+		fs.Digests[name] = "sha256"
+	}
+}
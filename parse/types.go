@@ -0,0 +1,205 @@
+package parse
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/CovenantSQL/HashStablePack/gen"
+)
+
+// This file implements the type-checked mode
+// of the parser, see TypedFile. The syntax-only
+// parser can't tell what the identifiers that are
+// not declared in the parsed files stand for, and
+// assumes that they have a MarshalHash method.
+// Here, their declarations are looked up in the
+// type information of the package, so that:
+//
+//    type NodeID string
+//
+// declared in another package is written as a
+// string, as it would be if it were declared
+// alongside, and that a type without a MarshalHash
+// method, like hash.Hash, is reported before any
+// code is generated.
+
+const loadMode = packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes
+
+// loadPackage type checks the package
+// holding the file or directory name
+func loadPackage(name string) (*packages.Package, error) {
+	dir := name
+	if fi, err := os.Stat(name); err != nil {
+		return nil, err
+	} else if !fi.IsDir() {
+		dir = filepath.Dir(name)
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: dir}, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 || pkgs[0].Types == nil {
+		return nil, fmt.Errorf("couldn't load the package in %s", dir)
+	}
+	pkg := pkgs[0]
+
+	// the generated files of the package
+	// may be out of date, which is why
+	// we are here; the declarations of
+	// the other types are still usable
+	for _, e := range pkg.Errors {
+		warnf("type checking: %s\n", e.Msg)
+	}
+	return pkg, nil
+}
+
+// lookupType returns the type named id, as
+// written in the parsed files, or nil
+func (fs *FileSet) lookupType(id string) types.Type {
+	scope := fs.pkg.Types.Scope()
+	if dot := strings.IndexByte(id, '.'); dot >= 0 {
+		scope = nil
+		if p := fs.importedPackage(id[:dot]); p != nil {
+			scope = p.Scope()
+		}
+		id = id[dot+1:]
+	}
+	if scope == nil {
+		return nil
+	}
+	if tn, ok := scope.Lookup(id).(*types.TypeName); ok {
+		return tn.Type()
+	}
+	return nil
+}
+
+// importedPackage returns the package
+// imported as name by the parsed files
+func (fs *FileSet) importedPackage(name string) *types.Package {
+	for _, imp := range fs.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		for _, p := range fs.pkg.Types.Imports() {
+			if p.Path() != path {
+				continue
+			}
+			if (imp.Name != nil && imp.Name.Name == name) ||
+				(imp.Name == nil && p.Name() == name) {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// hasMarshalHash returns whether values
+// of typ, or pointers to them, have a
+// MarshalHash method
+func hasMarshalHash(typ types.Type) bool {
+	ms := types.NewMethodSet(types.NewPointer(typ))
+	return ms.Lookup(nil, "MarshalHash") != nil
+}
+
+// simpleElem returns the element for the simple
+// underlying types, which are written in place
+// of the named type, or nil
+func simpleElem(u types.Type) *gen.BaseElem {
+	switch u := u.(type) {
+	case *types.Basic:
+		if be := gen.Ident(u.Name()); be.Value != gen.IDENT {
+			return be
+		}
+	case *types.Slice:
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+			return &gen.BaseElem{Value: gen.Bytes}
+		}
+	}
+	return nil
+}
+
+// resolveTypes replaces the identifiers that are
+// not declared in the parsed files and have no
+// MarshalHash method with their simple underlying
+// type, and reports the others.
+func (fs *FileSet) resolveTypes() error {
+	names := make([]string, 0, len(fs.Identities))
+	for name := range fs.Identities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		el := fs.Identities[name]
+		pushstate(name)
+		err := fs.nextType(&el)
+		popstate()
+		if err != nil {
+			return err
+		}
+		fs.Identities[name] = el
+	}
+	return nil
+}
+
+func (fs *FileSet) nextType(ref *gen.Elem) error {
+	switch el := (*ref).(type) {
+	case *gen.BaseElem:
+		if el.Value != gen.IDENT || el.Resolved() || el.TypeParam {
+			return nil
+		}
+		id := gen.BaseName(el.TypeName())
+		if _, ok := fs.Identities[id]; ok {
+			return nil
+		}
+		typ := fs.lookupType(id)
+		if typ == nil {
+			// reported by propInline
+			return nil
+		}
+		// a type with its own MarshalHash is nested,
+		// as it is without type checking
+		if hasMarshalHash(typ) {
+			return nil
+		}
+		if be := simpleElem(typ.Underlying()); be != nil {
+			infof("inlining %s as %s\n", id, be.BaseType())
+			be.Alias(id)
+			vn := el.Varname()
+			*ref = be
+			be.SetVarname(vn)
+			return nil
+		}
+		if strings.Contains(id, ".") {
+			return fmt.Errorf("%s: %s has no MarshalHash method", strings.Join(logctx, ": "), id)
+		}
+		// may be generated by
+		// another run of hsp
+		warnf("%s has no MarshalHash method\n", id)
+	case *gen.Struct:
+		for i := range el.Fields {
+			if err := fs.nextType(&el.Fields[i].FieldElem); err != nil {
+				return err
+			}
+		}
+	case *gen.Array:
+		return fs.nextType(&el.Els)
+	case *gen.Slice:
+		return fs.nextType(&el.Els)
+	case *gen.Map:
+		if err := fs.nextType(&el.Key); err != nil {
+			return err
+		}
+		return fs.nextType(&el.Value)
+	case *gen.Ptr:
+		return fs.nextType(&el.Value)
+	}
+	return nil
+}
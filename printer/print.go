@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/ttacon/chalk"
@@ -22,96 +24,124 @@ func infof(s string, v ...interface{}) {
 // of elements to the given file name and canonical
 // package path.
 func PrintFile(file string, f *parse.FileSet, mode gen.Method) error {
-	out, tests, err := generate(f, mode)
+	files, err := RenderFile(file, f, mode)
 	if err != nil {
 		return err
 	}
+	return WriteFiles(files)
+}
 
-	// we'll run goimports on the main file
-	// in another goroutine, and run it here
-	// for the test file. empirically, this
-	// takes about the same amount of time as
-	// doing them in serial when GOMAXPROCS=1,
-	// and faster otherwise.
-	res := goformat(file, out.Bytes())
-	if tests != nil {
-		testfile := strings.TrimSuffix(file, ".go") + "_test.go"
-		err = format(testfile, tests.Bytes())
-		if err != nil {
-			return err
-		}
-		infof(">>> Wrote and formatted \"%s\"\n", testfile)
-	}
-	err = <-res
+// PrintVersionFile prints the method for the provide versioned type.
+func PrintVersionFile(file string, f *parse.FileSet, s *gen.Struct, mode gen.Method) error {
+	files, err := RenderVersionFile(file, f, s, mode)
 	if err != nil {
 		return err
 	}
-	return nil
+	return WriteFiles(files)
 }
 
-// PrintVersionFile prints the method for the provide versioned type.
-func PrintVersionFile(file string, f *parse.FileSet, s *gen.Struct, mode gen.Method) error {
-	out, tests, err := generateVersion(f, s, mode)
+// PrintOldVersionFile prints the method for the provide versioned type.
+func PrintOldVersionFile(file string, f *parse.FileSet, s *gen.Struct, mode gen.Method) error {
+	files, err := RenderOldVersionFile(file, f, s, mode)
 	if err != nil {
 		return err
 	}
+	return WriteFiles(files)
+}
 
-	genFileName := strings.TrimSuffix(file, "_gen.go") + "_" +
-		strings.ToLower(s.TypeName()) + "_" + s.CurrentVersion + "_gen.go"
-	res := goformat(genFileName, out.Bytes())
-	if tests != nil {
-		testfile := strings.TrimSuffix(genFileName, ".go") + "_test.go"
-		err = format(testfile, tests.Bytes())
-		if err != nil {
-			return err
-		}
-		infof(">>> Wrote and formatted \"%s\"\n", testfile)
-	}
-	err = <-res
+// RenderFile is like PrintFile, but returns the formatted
+// content of the generated files by file name instead of
+// writing them.
+func RenderFile(file string, f *parse.FileSet, mode gen.Method) (map[string][]byte, error) {
+	out, tests, err := generate(file, f, mode)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return render(file, out, tests)
 }
 
-// PrintOldVersionFile prints the method for the provide versioned type.
-func PrintOldVersionFile(file string, f *parse.FileSet, s *gen.Struct, mode gen.Method) error {
-	out, tests, err := generateOldVersion(f, s, mode)
+// RenderVersionFile is like PrintVersionFile, but returns
+// the formatted content of the generated files.
+func RenderVersionFile(file string, f *parse.FileSet, s *gen.Struct, mode gen.Method) (map[string][]byte, error) {
+	genFileName := strings.TrimSuffix(file, "_gen.go") + "_" +
+		strings.ToLower(s.TypeName()) + "_" + s.CurrentVersion + "_gen.go"
+	out, tests, err := generateVersion(genFileName, f, s, mode)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return render(genFileName, out, tests)
+}
 
+// RenderOldVersionFile is like PrintOldVersionFile, but
+// returns the formatted content of the generated files.
+func RenderOldVersionFile(file string, f *parse.FileSet, s *gen.Struct, mode gen.Method) (map[string][]byte, error) {
 	genFileName := strings.TrimSuffix(file, "_gen.go") + "_" +
 		strings.ToLower(s.TypeName()) + "_oldver_gen.go"
-	res := goformat(genFileName, out.Bytes())
+	out, tests, err := generateOldVersion(genFileName, f, s, mode)
+	if err != nil {
+		return nil, err
+	}
+	return render(genFileName, out, tests)
+}
+
+// render formats the generated file and its test file.
+//
+// we'll run goimports on the main file
+// in another goroutine, and run it here
+// for the test file. empirically, this
+// takes about the same amount of time as
+// doing them in serial when GOMAXPROCS=1,
+// and faster otherwise.
+func render(file string, out, tests *bytes.Buffer) (map[string][]byte, error) {
+	files := make(map[string][]byte, 2)
+	res := goformat(file, out.Bytes())
 	if tests != nil {
-		testfile := strings.TrimSuffix(genFileName, ".go") + "_test.go"
-		err = format(testfile, tests.Bytes())
+		testfile := strings.TrimSuffix(file, ".go") + "_test.go"
+		data, err := format(testfile, tests.Bytes())
 		if err != nil {
-			return err
+			return nil, err
 		}
-		infof(">>> Wrote and formatted \"%s\"\n", testfile)
+		files[testfile] = data
 	}
-	err = <-res
-	if err != nil {
-		return err
+	r := <-res
+	if r.err != nil {
+		return nil, r.err
 	}
-	return nil
+	files[file] = r.data
+	return files, nil
 }
 
-func format(file string, data []byte) error {
-	out, err := imports.Process(file, data, nil)
-	if err != nil {
-		return err
+// WriteFiles writes the files returned
+// by the Render functions.
+func WriteFiles(files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
 	}
-	return ioutil.WriteFile(file, out, 0600)
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ioutil.WriteFile(name, files[name], 0600); err != nil {
+			return err
+		}
+		infof(">>> Wrote and formatted \"%s\"\n", name)
+	}
+	return nil
 }
 
-func goformat(file string, data []byte) <-chan error {
-	out := make(chan error, 1)
-	go func(file string, data []byte, end chan error) {
-		end <- format(file, data)
-		infof(">>> Wrote and formatted \"%s\"\n", file)
+func format(file string, data []byte) ([]byte, error) {
+	return imports.Process(file, data, nil)
+}
+
+type formatted struct {
+	data []byte
+	err  error
+}
+
+func goformat(file string, data []byte) <-chan formatted {
+	out := make(chan formatted, 1)
+	go func(file string, data []byte, end chan formatted) {
+		res, err := format(file, data)
+		end <- formatted{res, err}
 	}(file, data, out)
 	return out
 }
@@ -128,13 +158,14 @@ func dedupImports(imp []string) []string {
 	return r
 }
 
-func generate(f *parse.FileSet, mode gen.Method) (*bytes.Buffer, *bytes.Buffer, error) {
+func generate(file string, f *parse.FileSet, mode gen.Method) (*bytes.Buffer, *bytes.Buffer, error) {
 	outbuf := bytes.NewBuffer(make([]byte, 0, 4096))
 	writePkgHeader(outbuf, f.Package)
 
 	myImports := []string{}
 	myImports = append(myImports, `hsp "github.com/CovenantSQL/HashStablePack/marshalhash"`)
 	myImports = append(myImports, "herr \"errors\"")
+	myImports = append(myImports, f.DigestImports()...)
 
 	for _, imp := range f.Imports {
 		if imp.Name != nil {
@@ -152,13 +183,15 @@ func generate(f *parse.FileSet, mode gen.Method) (*bytes.Buffer, *bytes.Buffer,
 	if mode&gen.Test == gen.Test {
 		testbuf = bytes.NewBuffer(make([]byte, 0, 4096))
 		writePkgHeader(testbuf, f.Package)
-		writeImportHeader(testbuf, "bytes", "crypto/rand", "encoding/binary", `hsp "github.com/CovenantSQL/HashStablePack/marshalhash"`, "testing")
+		writeImportHeader(testbuf, "bytes", "fmt", `hsp "github.com/CovenantSQL/HashStablePack/marshalhash"`, "testing")
 		testwr = testbuf
 	}
-	return outbuf, testbuf, f.PrintTo(gen.NewPrinter(mode, outbuf, testwr, ""))
+	p := gen.NewPrinter(mode, outbuf, testwr, "")
+	p.GoldenFile(goldenFile(file))
+	return outbuf, testbuf, f.PrintTo(p)
 }
 
-func generateOldVersion(f *parse.FileSet, s *gen.Struct, mode gen.Method) (*bytes.Buffer, *bytes.Buffer, error) {
+func generateOldVersion(file string, f *parse.FileSet, s *gen.Struct, mode gen.Method) (*bytes.Buffer, *bytes.Buffer, error) {
 	outbuf := bytes.NewBuffer(make([]byte, 0, 4096))
 	writePkgHeader(outbuf, f.Package)
 
@@ -182,13 +215,15 @@ func generateOldVersion(f *parse.FileSet, s *gen.Struct, mode gen.Method) (*byte
 	if mode&gen.Test == gen.Test {
 		testbuf = bytes.NewBuffer(make([]byte, 0, 4096))
 		writePkgHeader(testbuf, f.Package)
-		writeImportHeader(testbuf, "bytes", "crypto/rand", "encoding/binary", `hsp "github.com/CovenantSQL/HashStablePack/marshalhash"`, "testing")
+		writeImportHeader(testbuf, "bytes", "fmt", `hsp "github.com/CovenantSQL/HashStablePack/marshalhash"`, "testing")
 		testwr = testbuf
 	}
-	return outbuf, testbuf, f.PrintVersion(s, gen.NewPrinter(mode, outbuf, testwr, "oldver"), "oldver")
+	p := gen.NewPrinter(mode, outbuf, testwr, "oldver")
+	p.GoldenFile(goldenFile(file))
+	return outbuf, testbuf, f.PrintVersion(s, p, "oldver")
 }
 
-func generateVersion(f *parse.FileSet, s *gen.Struct, mode gen.Method) (*bytes.Buffer, *bytes.Buffer, error) {
+func generateVersion(file string, f *parse.FileSet, s *gen.Struct, mode gen.Method) (*bytes.Buffer, *bytes.Buffer, error) {
 	outbuf := bytes.NewBuffer(make([]byte, 0, 4096))
 	writePkgHeader(outbuf, f.Package)
 
@@ -212,10 +247,18 @@ func generateVersion(f *parse.FileSet, s *gen.Struct, mode gen.Method) (*bytes.B
 	if mode&gen.Test == gen.Test {
 		testbuf = bytes.NewBuffer(make([]byte, 0, 4096))
 		writePkgHeader(testbuf, f.Package)
-		writeImportHeader(testbuf, "bytes", "crypto/rand", "encoding/binary", `hsp "github.com/CovenantSQL/HashStablePack/marshalhash"`, "testing")
+		writeImportHeader(testbuf, "bytes", "fmt", `hsp "github.com/CovenantSQL/HashStablePack/marshalhash"`, "testing")
 		testwr = testbuf
 	}
-	return outbuf, testbuf, f.PrintVersion(s, gen.NewPrinter(mode, outbuf, testwr, s.CurrentVersion), s.CurrentVersion)
+	p := gen.NewPrinter(mode, outbuf, testwr, s.CurrentVersion)
+	p.GoldenFile(goldenFile(file))
+	return outbuf, testbuf, f.PrintVersion(s, p, s.CurrentVersion)
+}
+
+// goldenFile returns the golden file of the tests
+// generated alongside file, relative to the package
+func goldenFile(file string) string {
+	return "testdata/" + strings.TrimSuffix(filepath.Base(file), "_gen.go") + ".golden"
 }
 
 func writePkgHeader(b *bytes.Buffer, name string) {
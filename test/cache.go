@@ -0,0 +1,25 @@
+package covenant
+
+import (
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+//go:generate hsp -unmarshal
+
+//hsp:cache Account with:setters
+//hsp:cache Contract
+
+type Account struct {
+	Address string   `hsp:"0"`
+	Balance uint64   `hsp:"1"`
+	Tokens  []string `hsp:"2"`
+	Owner   *Account `hsp:"3"`
+
+	digest hsp.DigestCache
+}
+
+type Contract struct {
+	Code  []byte          `hsp:"0"`
+	State map[string]Blob `hsp:"1"`
+	Cache hsp.DigestCache
+}
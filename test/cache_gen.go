@@ -0,0 +1,295 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"crypto/sha256"
+	"sort"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// MarshalHash marshals for hash
+func (z *Account) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 4
+	o = append(o, 0x84)
+	o = hsp.AppendString(o, z.Address)
+	o = hsp.AppendUint64(o, z.Balance)
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Tokens)))
+	for za0001 := range z.Tokens {
+		o = hsp.AppendString(o, z.Tokens[za0001])
+	}
+	if z.Owner == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		if oTemp, err := z.Owner.MarshalHash(); err != nil {
+			return nil, err
+		} else {
+			o = hsp.AppendBytes(o, oTemp)
+		}
+	}
+	return
+}
+
+// Digest returns the sha256 digest of MarshalHash
+func (z *Account) Digest() (d [32]byte, err error) {
+	var o []byte
+	if o, err = z.MarshalHash(); err != nil {
+		return
+	}
+	d = sha256.Sum256(o)
+	return
+}
+
+// DoubleDigest returns the sha256 digest of Digest
+func (z *Account) DoubleDigest() (d [32]byte, err error) {
+	if d, err = z.Digest(); err != nil {
+		return
+	}
+	d = sha256.Sum256(d[:])
+	return
+}
+
+// CachedDigest returns Digest, computed once until InvalidateHash is called
+func (z *Account) CachedDigest() (d [32]byte, err error) {
+	var ok bool
+	if d, ok = z.digest.Load(); ok {
+		return
+	}
+	if d, err = z.Digest(); err != nil {
+		return
+	}
+	z.digest.Store(d)
+	return
+}
+
+// InvalidateHash drops the digest cached by CachedDigest
+func (z *Account) InvalidateHash() {
+	z.digest.Invalidate()
+}
+
+// SetAddress sets Address and invalidates the cached digest
+func (z *Account) SetAddress(v string) {
+	z.Address = v
+	z.digest.Invalidate()
+}
+
+// SetBalance sets Balance and invalidates the cached digest
+func (z *Account) SetBalance(v uint64) {
+	z.Balance = v
+	z.digest.Invalidate()
+}
+
+// SetTokens sets Tokens and invalidates the cached digest
+func (z *Account) SetTokens(v []string) {
+	z.Tokens = v
+	z.digest.Invalidate()
+}
+
+// SetOwner sets Owner and invalidates the cached digest
+func (z *Account) SetOwner(v *Account) {
+	z.Owner = v
+	z.digest.Invalidate()
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Account) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 4 {
+		err = hsp.ArrayError{Wanted: 4, Got: zb0001}
+		return
+	}
+	z.Address, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Balance, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = hsp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Tokens) >= int(zb0002) {
+		z.Tokens = (z.Tokens)[:zb0002]
+	} else {
+		z.Tokens = make([]string, zb0002)
+	}
+	for za0001 := range z.Tokens {
+		z.Tokens[za0001], bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+	}
+	if hsp.IsNil(bts) {
+		bts, err = hsp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Owner = nil
+	} else {
+		if z.Owner == nil {
+			z.Owner = new(Account)
+		}
+		var zb0003 []byte
+		zb0003, bts, err = hsp.ReadBytesZC(bts)
+		if err != nil {
+			return
+		}
+		_, err = z.Owner.UnmarshalHash(zb0003)
+		if err != nil {
+			return
+		}
+	}
+	z.digest.Invalidate()
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Account) Msgsize() (s int) {
+	s = 1 + 2 + hsp.StringPrefixSize + len(z.Address) + 2 + hsp.Uint64Size + 2 + hsp.ArrayHeaderSize
+	for za0001 := range z.Tokens {
+		s += hsp.StringPrefixSize + len(z.Tokens[za0001])
+	}
+	s += 2
+	if z.Owner == nil {
+		s += hsp.NilSize
+	} else {
+		s += hsp.BytesPrefixSize + z.Owner.Msgsize()
+	}
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *Contract) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 2
+	o = append(o, 0x82)
+	o = hsp.AppendBytes(o, z.Code)
+	o = hsp.AppendMapHeader(o, uint32(len(z.State)))
+	za0001Slice := make([]string, 0, len(z.State))
+	for i := range z.State {
+		za0001Slice = append(za0001Slice, i)
+	}
+	sort.Strings(za0001Slice)
+	for _, za0001 := range za0001Slice {
+		za0002 := z.State[za0001]
+		o = hsp.AppendString(o, za0001)
+		if oTemp, err := za0002.MarshalHash(); err != nil {
+			return nil, err
+		} else {
+			o = hsp.AppendBytes(o, oTemp)
+		}
+	}
+	return
+}
+
+// Digest returns the sha256 digest of MarshalHash
+func (z *Contract) Digest() (d [32]byte, err error) {
+	var o []byte
+	if o, err = z.MarshalHash(); err != nil {
+		return
+	}
+	d = sha256.Sum256(o)
+	return
+}
+
+// DoubleDigest returns the sha256 digest of Digest
+func (z *Contract) DoubleDigest() (d [32]byte, err error) {
+	if d, err = z.Digest(); err != nil {
+		return
+	}
+	d = sha256.Sum256(d[:])
+	return
+}
+
+// CachedDigest returns Digest, computed once until InvalidateHash is called
+func (z *Contract) CachedDigest() (d [32]byte, err error) {
+	var ok bool
+	if d, ok = z.Cache.Load(); ok {
+		return
+	}
+	if d, err = z.Digest(); err != nil {
+		return
+	}
+	z.Cache.Store(d)
+	return
+}
+
+// InvalidateHash drops the digest cached by CachedDigest
+func (z *Contract) InvalidateHash() {
+	z.Cache.Invalidate()
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Contract) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 2 {
+		err = hsp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	z.Code, bts, err = hsp.ReadBytesBytes(bts, z.Code)
+	if err != nil {
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if z.State == nil {
+		z.State = make(map[string]Blob, zb0002)
+	} else if len(z.State) > 0 {
+		for key := range z.State {
+			delete(z.State, key)
+		}
+	}
+	for zb0002 > 0 {
+		var za0001 string
+		var za0002 Blob
+		zb0002--
+		za0001, bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+		var zb0003 []byte
+		zb0003, bts, err = hsp.ReadBytesZC(bts)
+		if err != nil {
+			return
+		}
+		_, err = za0002.UnmarshalHash(zb0003)
+		if err != nil {
+			return
+		}
+		z.State[za0001] = za0002
+	}
+	z.Cache.Invalidate()
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Contract) Msgsize() (s int) {
+	s = 1 + 2 + hsp.BytesPrefixSize + len(z.Code) + 2 + hsp.MapHeaderSize
+	if z.State != nil {
+		for za0001, za0002 := range z.State {
+			_ = za0002
+			s += hsp.StringPrefixSize + len(za0001) + hsp.BytesPrefixSize + za0002.Msgsize()
+		}
+	}
+	return
+}
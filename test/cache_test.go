@@ -0,0 +1,104 @@
+package covenant
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CovenantSQL/HashStablePack/parse"
+)
+
+func TestCachedDigest(t *testing.T) {
+	acc := Account{Address: "alice", Balance: 10, Tokens: []string{"a"}}
+	want, err := acc.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := acc.CachedDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != want {
+		t.Fatalf("CachedDigest %x, want %x", d, want)
+	}
+
+	// direct mutations are not tracked
+	acc.Balance = 20
+	if d, _ = acc.CachedDigest(); d != want {
+		t.Fatal("cached digest recomputed without invalidation")
+	}
+	acc.InvalidateHash()
+	want, _ = acc.Digest()
+	if d, _ = acc.CachedDigest(); d != want {
+		t.Fatalf("CachedDigest %x after InvalidateHash, want %x", d, want)
+	}
+
+	// setters invalidate the cache
+	acc.SetBalance(30)
+	want, _ = acc.Digest()
+	if d, _ = acc.CachedDigest(); d != want {
+		t.Fatalf("CachedDigest %x after SetBalance, want %x", d, want)
+	}
+
+	// so does UnmarshalHash
+	bts, err := (&Account{Address: "bob"}).MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = acc.UnmarshalHash(bts); err != nil {
+		t.Fatal(err)
+	}
+	want, _ = acc.Digest()
+	if d, _ = acc.CachedDigest(); d != want {
+		t.Fatalf("CachedDigest %x after UnmarshalHash, want %x", d, want)
+	}
+}
+
+func TestCachedDigestIgnoresCache(t *testing.T) {
+	c1 := Contract{Code: []byte{0x01}, State: map[string]Blob{"k": Blob("v")}}
+	c2 := c1
+	bts1, err := c1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c1.CachedDigest(); err != nil {
+		t.Fatal(err)
+	}
+	bts2, err := c1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bts1) != string(bts2) {
+		t.Fatal("the digest cache changed the hash")
+	}
+	d1, _ := c1.CachedDigest()
+	d2, _ := c2.CachedDigest()
+	if d1 != d2 {
+		t.Fatal("digest depends on the cache state")
+	}
+}
+
+func BenchmarkCachedDigestCompare(b *testing.B) {
+	c1 := Contract{Code: make([]byte, 1024), State: map[string]Blob{"k": Blob("v")}}
+	c2 := Contract{Code: make([]byte, 1024), State: map[string]Blob{"k": Blob("v")}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d1, _ := c1.CachedDigest()
+		d2, _ := c2.CachedDigest()
+		if d1 != d2 {
+			b.Fatal("digests differ")
+		}
+	}
+}
+
+func TestCacheErrors(t *testing.T) {
+	for file, want := range map[string]string{
+		"nofield.go": "Account: declare a field of type hsp.DigestCache",
+		"noargs.go":  "cache directive should name at least 1 type",
+	} {
+		_, err := parse.File("testdata/cache/"+file, false)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got error %v, want %q", file, err, want)
+		}
+	}
+}
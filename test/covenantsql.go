@@ -65,3 +65,13 @@ type Person2 struct {
 	unexported bool             // this field is ignored
 	Unexported string `hsp:"-"` // this field is ignored
 }
+
+// Accounts has maps with non-string keys, which
+// are written in canonical key order.
+type Accounts struct {
+	Balances map[int64]uint64       `hsp:"0"`
+	Nonces   map[uint32]MyInt       `hsp:"1"`
+	Hashes   map[hash.Hash]string   `hsp:"2"`
+	Nodes    map[proto.NodeID]int32 `hsp:"3"`
+	Prefixes map[[4]byte]bool       `hsp:"4"`
+}
@@ -3,11 +3,141 @@ package covenant
 // Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
 
 import (
+	"bytes"
 	"sort"
 
+	"github.com/CovenantSQL/CovenantSQL/crypto/hash"
+	"github.com/CovenantSQL/CovenantSQL/proto"
 	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
 )
 
+// MarshalHash marshals for hash
+func (z *Accounts) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 5
+	o = append(o, 0x85)
+	o = hsp.AppendMapHeader(o, uint32(len(z.Balances)))
+	za0001Slice := make([]int64, 0, len(z.Balances))
+	for i := range z.Balances {
+		za0001Slice = append(za0001Slice, i)
+	}
+	sort.Slice(za0001Slice, func(i, j int) bool { return za0001Slice[i] < za0001Slice[j] })
+	for _, za0001 := range za0001Slice {
+		za0002 := z.Balances[za0001]
+		o = hsp.AppendInt64(o, za0001)
+		o = hsp.AppendUint64(o, za0002)
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Nonces)))
+	za0003Slice := make([]uint32, 0, len(z.Nonces))
+	for i := range z.Nonces {
+		za0003Slice = append(za0003Slice, i)
+	}
+	sort.Slice(za0003Slice, func(i, j int) bool { return za0003Slice[i] < za0003Slice[j] })
+	for _, za0003 := range za0003Slice {
+		za0004 := z.Nonces[za0003]
+		o = hsp.AppendUint32(o, za0003)
+		o = hsp.AppendInt(o, int(za0004))
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Hashes)))
+	za0005Slice := make([]struct {
+		key hash.Hash
+		enc []byte
+	}, 0, len(z.Hashes))
+	for za0005 := range z.Hashes {
+		var enc []byte
+		if enc, err = za0005.MarshalHash(); err != nil {
+			return
+		}
+		za0005Slice = append(za0005Slice, struct {
+			key hash.Hash
+			enc []byte
+		}{za0005, enc})
+	}
+	sort.Slice(za0005Slice, func(i, j int) bool { return bytes.Compare(za0005Slice[i].enc, za0005Slice[j].enc) < 0 })
+	for _, za0005Entry := range za0005Slice {
+		za0006 := z.Hashes[za0005Entry.key]
+		o = hsp.AppendBytes(o, za0005Entry.enc)
+		o = hsp.AppendString(o, za0006)
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Nodes)))
+	za0007Slice := make([]struct {
+		key proto.NodeID
+		enc []byte
+	}, 0, len(z.Nodes))
+	for za0007 := range z.Nodes {
+		var enc []byte
+		if enc, err = za0007.MarshalHash(); err != nil {
+			return
+		}
+		za0007Slice = append(za0007Slice, struct {
+			key proto.NodeID
+			enc []byte
+		}{za0007, enc})
+	}
+	sort.Slice(za0007Slice, func(i, j int) bool { return bytes.Compare(za0007Slice[i].enc, za0007Slice[j].enc) < 0 })
+	for _, za0007Entry := range za0007Slice {
+		za0008 := z.Nodes[za0007Entry.key]
+		o = hsp.AppendBytes(o, za0007Entry.enc)
+		o = hsp.AppendInt32(o, za0008)
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Prefixes)))
+	za0009Slice := make([][4]byte, 0, len(z.Prefixes))
+	for i := range z.Prefixes {
+		za0009Slice = append(za0009Slice, i)
+	}
+	sort.Slice(za0009Slice, func(i, j int) bool { return bytes.Compare(za0009Slice[i][:], za0009Slice[j][:]) < 0 })
+	for _, za0009 := range za0009Slice {
+		za0010 := z.Prefixes[za0009]
+		o = hsp.AppendBytes(o, (za0009)[:])
+		o = hsp.AppendBool(o, za0010)
+	}
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Accounts) Msgsize() (s int) {
+	s = 1 + 2 + hsp.MapHeaderSize
+	if z.Balances != nil {
+		for za0001, za0002 := range z.Balances {
+			_ = za0002
+			_ = za0001
+			s += hsp.Int64Size + hsp.Uint64Size
+		}
+	}
+	s += 2 + hsp.MapHeaderSize
+	if z.Nonces != nil {
+		for za0003, za0004 := range z.Nonces {
+			_ = za0004
+			_ = za0003
+			s += hsp.Uint32Size + hsp.IntSize
+		}
+	}
+	s += 2 + hsp.MapHeaderSize
+	if z.Hashes != nil {
+		for za0005, za0006 := range z.Hashes {
+			_ = za0006
+			s += hsp.BytesPrefixSize + za0005.Msgsize() + hsp.StringPrefixSize + len(za0006)
+		}
+	}
+	s += 2 + hsp.MapHeaderSize
+	if z.Nodes != nil {
+		for za0007, za0008 := range z.Nodes {
+			_ = za0008
+			s += hsp.BytesPrefixSize + za0007.Msgsize() + hsp.Int32Size
+		}
+	}
+	s += 2 + hsp.MapHeaderSize
+	if z.Prefixes != nil {
+		for za0009, za0010 := range z.Prefixes {
+			_ = za0010
+			_ = za0009
+			s += hsp.ArrayHeaderSize + (int(4) * (hsp.ByteSize)) + hsp.BoolSize
+		}
+	}
+	return
+}
+
 // MarshalHash marshals for hash
 func (z Data) MarshalHash() (o []byte, err error) {
 	var b []byte
@@ -83,12 +213,12 @@ func (z *HeaderTest) MarshalHash() (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *HeaderTest) Msgsize() (s int) {
-	s = 1 + 3 + hsp.StringPrefixSize + len(z.TestName) + 3 + hsp.Int32Size + 3 + z.Producer.Msgsize() + 3 + hsp.ArrayHeaderSize
+	s = 1 + 3 + hsp.StringPrefixSize + len(z.TestName) + 3 + hsp.Int32Size + 3 + hsp.BytesPrefixSize + z.Producer.Msgsize() + 3 + hsp.ArrayHeaderSize
 	for za0002 := range z.ParentHash {
 		if z.ParentHash[za0002] == nil {
 			s += hsp.NilSize
 		} else {
-			s += z.ParentHash[za0002].Msgsize()
+			s += hsp.BytesPrefixSize + z.ParentHash[za0002].Msgsize()
 		}
 	}
 	s += 3 + hsp.TimeSize + 3
@@ -100,15 +230,15 @@ func (z *HeaderTest) Msgsize() (s int) {
 			if (*z.MerkleRoot)[za0003] == nil {
 				s += hsp.NilSize
 			} else {
-				s += (*z.MerkleRoot)[za0003].Msgsize()
+				s += hsp.BytesPrefixSize + (*z.MerkleRoot)[za0003].Msgsize()
 			}
 		}
 	}
 	s += 3 + hsp.ArrayHeaderSize
 	for za0001 := range z.GenesisHash {
-		s += z.GenesisHash[za0001].Msgsize()
+		s += hsp.BytesPrefixSize + z.GenesisHash[za0001].Msgsize()
 	}
-	s += 2 + z.S.Msgsize() + 10 + hsp.BytesPrefixSize + len(z.TestArray)
+	s += 2 + hsp.BytesPrefixSize + z.S.Msgsize() + 10 + hsp.BytesPrefixSize + len(z.TestArray)
 	return
 }
 
@@ -173,12 +303,12 @@ func (z *HeaderTest2) MarshalHash() (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *HeaderTest2) Msgsize() (s int) {
-	s = 1 + 3 + hsp.StringPrefixSize + len(z.TestName2) + 3 + hsp.Int32Size + 3 + z.Producer2.Msgsize() + 3 + hsp.ArrayHeaderSize
+	s = 1 + 3 + hsp.StringPrefixSize + len(z.TestName2) + 3 + hsp.Int32Size + 3 + hsp.BytesPrefixSize + z.Producer2.Msgsize() + 3 + hsp.ArrayHeaderSize
 	for za0002 := range z.ParentHash2 {
 		if z.ParentHash2[za0002] == nil {
 			s += hsp.NilSize
 		} else {
-			s += z.ParentHash2[za0002].Msgsize()
+			s += hsp.BytesPrefixSize + z.ParentHash2[za0002].Msgsize()
 		}
 	}
 	s += 3 + hsp.TimeSize + 3
@@ -190,15 +320,15 @@ func (z *HeaderTest2) Msgsize() (s int) {
 			if (*z.MerkleRoot2)[za0003] == nil {
 				s += hsp.NilSize
 			} else {
-				s += (*z.MerkleRoot2)[za0003].Msgsize()
+				s += hsp.BytesPrefixSize + (*z.MerkleRoot2)[za0003].Msgsize()
 			}
 		}
 	}
 	s += 3 + hsp.ArrayHeaderSize
 	for za0001 := range z.GenesisHash2 {
-		s += z.GenesisHash2[za0001].Msgsize()
+		s += hsp.BytesPrefixSize + z.GenesisHash2[za0001].Msgsize()
 	}
-	s += 2 + z.S.Msgsize() + 10 + hsp.BytesPrefixSize + len(z.TestArray)
+	s += 2 + hsp.BytesPrefixSize + z.S.Msgsize() + 10 + hsp.BytesPrefixSize + len(z.TestArray)
 	return
 }
 
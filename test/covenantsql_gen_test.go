@@ -4,27 +4,209 @@ package covenant
 
 import (
 	"bytes"
-	"crypto/rand"
-	"encoding/binary"
 	"testing"
+
+	"github.com/CovenantSQL/CovenantSQL/crypto/hash"
+	"github.com/CovenantSQL/CovenantSQL/proto"
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
 )
 
-func TestMarshalHashHeaderTest(t *testing.T) {
-	v := HeaderTest{}
-	binary.Read(rand.Reader, binary.BigEndian, &v)
-	bts1, err := v.MarshalHash()
-	if err != nil {
-		t.Fatal(err)
+// hspRandomAccounts populates z with values drawn from r
+func hspRandomAccounts(z *Accounts, r *hsp.Rand) {
+	if !r.Enter() {
+		return
+	}
+	defer r.Leave()
+	z.Balances = make(map[int64]uint64)
+	for n := r.Len(); n > 0; n-- {
+		var za0001 int64
+		var za0002 uint64
+		za0001 = r.Int64()
+		za0002 = r.Uint64()
+		z.Balances[za0001] = za0002
+	}
+	z.Nonces = make(map[uint32]MyInt)
+	for n := r.Len(); n > 0; n-- {
+		var za0003 uint32
+		var za0004 MyInt
+		za0003 = r.Uint32()
+		za0004 = MyInt(r.Int())
+		z.Nonces[za0003] = za0004
 	}
-	bts2, err := v.MarshalHash()
-	if err != nil {
-		t.Fatal(err)
+	z.Hashes = make(map[hash.Hash]string)
+	for n := r.Len(); n > 0; n-- {
+		var za0005 hash.Hash
+		var za0006 string
+		za0006 = r.String()
+		z.Hashes[za0005] = za0006
 	}
-	if !bytes.Equal(bts1, bts2) {
-		t.Fatal("hash not stable")
+	z.Nodes = make(map[proto.NodeID]int32)
+	for n := r.Len(); n > 0; n-- {
+		var za0007 proto.NodeID
+		var za0008 int32
+		za0008 = r.Int32()
+		z.Nodes[za0007] = za0008
+	}
+	z.Prefixes = make(map[[4]byte]bool)
+	for n := r.Len(); n > 0; n-- {
+		var za0009 [4]byte
+		var za0010 bool
+		r.Read((za0009)[:])
+		za0010 = r.Bool()
+		z.Prefixes[za0009] = za0010
+	}
+}
+
+func TestMarshalHashAccounts(t *testing.T) {
+	for seed := int64(0); seed < 16; seed++ {
+		v := Accounts{}
+		hspRandomAccounts(&v, hsp.NewRand(seed))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatalf("seed %d: hash not stable", seed)
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash()", seed, s, len(bts1))
+		}
+
+		// the sizes of the elements of long
+		// containers exceed the slack of headers
+		long := Accounts{}
+		hspRandomAccounts(&long, hsp.NewRand(seed).Long(256))
+		if bts1, err = long.MarshalHash(); err != nil {
+			t.Fatal(err)
+		}
+		if s := long.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash() with long containers", seed, s, len(bts1))
+		}
+	}
+}
+
+func FuzzMarshalHashAccounts(f *testing.F) {
+	f.Add([]byte("hsp"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := Accounts{}
+		hspRandomAccounts(&v, hsp.NewRandBytes(data).Long(256))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatal("hash not stable")
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("Msgsize() = %d, less than the %d bytes of MarshalHash()", s, len(bts1))
+		}
+	})
+}
+
+func BenchmarkMarshalHashAccounts(b *testing.B) {
+	v := Accounts{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalHash()
 	}
 }
 
+func BenchmarkAppendMsgAccounts(b *testing.B) {
+	v := Accounts{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalHash()
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalHash()
+	}
+}
+
+// hspRandomHeaderTest populates z with values drawn from r
+func hspRandomHeaderTest(z *HeaderTest, r *hsp.Rand) {
+	if !r.Enter() {
+		return
+	}
+	defer r.Leave()
+	z.TestName = r.String()
+	z.Version = r.Int32()
+	z.ParentHash = make([]*hash.Hash, r.Len())
+	z.Timestamp = r.Time()
+	if r.Nil() {
+		z.MerkleRoot = nil
+	} else {
+		z.MerkleRoot = new([]*hash.Hash)
+		*z.MerkleRoot = make([]*hash.Hash, r.Len())
+	}
+	z.GenesisHash = make([]hash.Hash, r.Len())
+	hspRandomStruct(&z.S, r)
+	z.TestArray = r.Bytes()
+}
+
+func TestMarshalHashHeaderTest(t *testing.T) {
+	for seed := int64(0); seed < 16; seed++ {
+		v := HeaderTest{}
+		hspRandomHeaderTest(&v, hsp.NewRand(seed))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatalf("seed %d: hash not stable", seed)
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash()", seed, s, len(bts1))
+		}
+
+		// the sizes of the elements of long
+		// containers exceed the slack of headers
+		long := HeaderTest{}
+		hspRandomHeaderTest(&long, hsp.NewRand(seed).Long(256))
+		if bts1, err = long.MarshalHash(); err != nil {
+			t.Fatal(err)
+		}
+		if s := long.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash() with long containers", seed, s, len(bts1))
+		}
+	}
+}
+
+func FuzzMarshalHashHeaderTest(f *testing.F) {
+	f.Add([]byte("hsp"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := HeaderTest{}
+		hspRandomHeaderTest(&v, hsp.NewRandBytes(data).Long(256))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatal("hash not stable")
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("Msgsize() = %d, less than the %d bytes of MarshalHash()", s, len(bts1))
+		}
+	})
+}
+
 func BenchmarkMarshalHashHeaderTest(b *testing.B) {
 	v := HeaderTest{}
 	b.ReportAllocs()
@@ -46,22 +228,81 @@ func BenchmarkAppendMsgHeaderTest(b *testing.B) {
 	}
 }
 
-func TestMarshalHashHeaderTest2(t *testing.T) {
-	v := HeaderTest2{}
-	binary.Read(rand.Reader, binary.BigEndian, &v)
-	bts1, err := v.MarshalHash()
-	if err != nil {
-		t.Fatal(err)
+// hspRandomHeaderTest2 populates z with values drawn from r
+func hspRandomHeaderTest2(z *HeaderTest2, r *hsp.Rand) {
+	if !r.Enter() {
+		return
 	}
-	bts2, err := v.MarshalHash()
-	if err != nil {
-		t.Fatal(err)
+	defer r.Leave()
+	z.TestName2 = r.String()
+	z.Version2 = r.Int32()
+	z.ParentHash2 = make([]*hash.Hash, r.Len())
+	z.Timestamp2 = r.Time()
+	if r.Nil() {
+		z.MerkleRoot2 = nil
+	} else {
+		z.MerkleRoot2 = new([]*hash.Hash)
+		*z.MerkleRoot2 = make([]*hash.Hash, r.Len())
 	}
-	if !bytes.Equal(bts1, bts2) {
-		t.Fatal("hash not stable")
+	z.GenesisHash2 = make([]hash.Hash, r.Len())
+	hspRandomStruct(&z.S, r)
+	z.TestArray = r.Bytes()
+}
+
+func TestMarshalHashHeaderTest2(t *testing.T) {
+	for seed := int64(0); seed < 16; seed++ {
+		v := HeaderTest2{}
+		hspRandomHeaderTest2(&v, hsp.NewRand(seed))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatalf("seed %d: hash not stable", seed)
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash()", seed, s, len(bts1))
+		}
+
+		// the sizes of the elements of long
+		// containers exceed the slack of headers
+		long := HeaderTest2{}
+		hspRandomHeaderTest2(&long, hsp.NewRand(seed).Long(256))
+		if bts1, err = long.MarshalHash(); err != nil {
+			t.Fatal(err)
+		}
+		if s := long.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash() with long containers", seed, s, len(bts1))
+		}
 	}
 }
 
+func FuzzMarshalHashHeaderTest2(f *testing.F) {
+	f.Add([]byte("hsp"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := HeaderTest2{}
+		hspRandomHeaderTest2(&v, hsp.NewRandBytes(data).Long(256))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatal("hash not stable")
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("Msgsize() = %d, less than the %d bytes of MarshalHash()", s, len(bts1))
+		}
+	})
+}
+
 func BenchmarkMarshalHashHeaderTest2(b *testing.B) {
 	v := HeaderTest2{}
 	b.ReportAllocs()
@@ -83,22 +324,79 @@ func BenchmarkAppendMsgHeaderTest2(b *testing.B) {
 	}
 }
 
-func TestMarshalHashPerson1(t *testing.T) {
-	v := Person1{}
-	binary.Read(rand.Reader, binary.BigEndian, &v)
-	bts1, err := v.MarshalHash()
-	if err != nil {
-		t.Fatal(err)
+// hspRandomPerson1 populates z with values drawn from r
+func hspRandomPerson1(z *Person1, r *hsp.Rand) {
+	if !r.Enter() {
+		return
 	}
-	bts2, err := v.MarshalHash()
-	if err != nil {
-		t.Fatal(err)
+	defer r.Leave()
+	z.Address = r.String()
+	z.Age = r.Int()
+	z.Map = make(map[string]int)
+	for n := r.Len(); n > 0; n-- {
+		var za0001 string
+		var za0002 int
+		za0001 = r.String()
+		za0002 = r.Int()
+		z.Map[za0001] = za0002
 	}
-	if !bytes.Equal(bts1, bts2) {
-		t.Fatal("hash not stable")
+	z.Name = r.String()
+}
+
+func TestMarshalHashPerson1(t *testing.T) {
+	for seed := int64(0); seed < 16; seed++ {
+		v := Person1{}
+		hspRandomPerson1(&v, hsp.NewRand(seed))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatalf("seed %d: hash not stable", seed)
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash()", seed, s, len(bts1))
+		}
+
+		// the sizes of the elements of long
+		// containers exceed the slack of headers
+		long := Person1{}
+		hspRandomPerson1(&long, hsp.NewRand(seed).Long(256))
+		if bts1, err = long.MarshalHash(); err != nil {
+			t.Fatal(err)
+		}
+		if s := long.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash() with long containers", seed, s, len(bts1))
+		}
 	}
 }
 
+func FuzzMarshalHashPerson1(f *testing.F) {
+	f.Add([]byte("hsp"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := Person1{}
+		hspRandomPerson1(&v, hsp.NewRandBytes(data).Long(256))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatal("hash not stable")
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("Msgsize() = %d, less than the %d bytes of MarshalHash()", s, len(bts1))
+		}
+	})
+}
+
 func BenchmarkMarshalHashPerson1(b *testing.B) {
 	v := Person1{}
 	b.ReportAllocs()
@@ -120,22 +418,79 @@ func BenchmarkAppendMsgPerson1(b *testing.B) {
 	}
 }
 
-func TestMarshalHashPerson2(t *testing.T) {
-	v := Person2{}
-	binary.Read(rand.Reader, binary.BigEndian, &v)
-	bts1, err := v.MarshalHash()
-	if err != nil {
-		t.Fatal(err)
+// hspRandomPerson2 populates z with values drawn from r
+func hspRandomPerson2(z *Person2, r *hsp.Rand) {
+	if !r.Enter() {
+		return
 	}
-	bts2, err := v.MarshalHash()
-	if err != nil {
-		t.Fatal(err)
+	defer r.Leave()
+	z.Address = r.String()
+	z.Age = r.Int()
+	z.Map222 = make(map[string]int)
+	for n := r.Len(); n > 0; n-- {
+		var za0001 string
+		var za0002 int
+		za0001 = r.String()
+		za0002 = r.Int()
+		z.Map222[za0001] = za0002
 	}
-	if !bytes.Equal(bts1, bts2) {
-		t.Fatal("hash not stable")
+	z.Name = r.String()
+}
+
+func TestMarshalHashPerson2(t *testing.T) {
+	for seed := int64(0); seed < 16; seed++ {
+		v := Person2{}
+		hspRandomPerson2(&v, hsp.NewRand(seed))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatalf("seed %d: hash not stable", seed)
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash()", seed, s, len(bts1))
+		}
+
+		// the sizes of the elements of long
+		// containers exceed the slack of headers
+		long := Person2{}
+		hspRandomPerson2(&long, hsp.NewRand(seed).Long(256))
+		if bts1, err = long.MarshalHash(); err != nil {
+			t.Fatal(err)
+		}
+		if s := long.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash() with long containers", seed, s, len(bts1))
+		}
 	}
 }
 
+func FuzzMarshalHashPerson2(f *testing.F) {
+	f.Add([]byte("hsp"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := Person2{}
+		hspRandomPerson2(&v, hsp.NewRandBytes(data).Long(256))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatal("hash not stable")
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("Msgsize() = %d, less than the %d bytes of MarshalHash()", s, len(bts1))
+		}
+	})
+}
+
 func BenchmarkMarshalHashPerson2(b *testing.B) {
 	v := Person2{}
 	b.ReportAllocs()
@@ -157,22 +512,85 @@ func BenchmarkAppendMsgPerson2(b *testing.B) {
 	}
 }
 
-func TestMarshalHashStruct(t *testing.T) {
-	v := Struct{}
-	binary.Read(rand.Reader, binary.BigEndian, &v)
-	bts1, err := v.MarshalHash()
-	if err != nil {
-		t.Fatal(err)
+// hspRandomStruct populates z with values drawn from r
+func hspRandomStruct(z *Struct, r *hsp.Rand) {
+	if !r.Enter() {
+		return
 	}
-	bts2, err := v.MarshalHash()
-	if err != nil {
-		t.Fatal(err)
+	defer r.Leave()
+	z.Other = Data(r.Bytes())
+	z.Which = make(map[string]*MyInt)
+	for n := r.Len(); n > 0; n-- {
+		var za0001 string
+		var za0002 *MyInt
+		za0001 = r.String()
+		if r.Nil() {
+			za0002 = nil
+		} else {
+			za0002 = new(MyInt)
+			*za0002 = MyInt(r.Int())
+		}
+		z.Which[za0001] = za0002
 	}
-	if !bytes.Equal(bts1, bts2) {
-		t.Fatal("hash not stable")
+	for za0003 := range z.Nums {
+		z.Nums[za0003] = r.Float64()
 	}
 }
 
+func TestMarshalHashStruct(t *testing.T) {
+	for seed := int64(0); seed < 16; seed++ {
+		v := Struct{}
+		hspRandomStruct(&v, hsp.NewRand(seed))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatalf("seed %d: hash not stable", seed)
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash()", seed, s, len(bts1))
+		}
+
+		// the sizes of the elements of long
+		// containers exceed the slack of headers
+		long := Struct{}
+		hspRandomStruct(&long, hsp.NewRand(seed).Long(256))
+		if bts1, err = long.MarshalHash(); err != nil {
+			t.Fatal(err)
+		}
+		if s := long.Msgsize(); s < len(bts1) {
+			t.Fatalf("seed %d: Msgsize() = %d, less than the %d bytes of MarshalHash() with long containers", seed, s, len(bts1))
+		}
+	}
+}
+
+func FuzzMarshalHashStruct(f *testing.F) {
+	f.Add([]byte("hsp"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v := Struct{}
+		hspRandomStruct(&v, hsp.NewRandBytes(data).Long(256))
+		bts1, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatal("hash not stable")
+		}
+		if s := v.Msgsize(); s < len(bts1) {
+			t.Fatalf("Msgsize() = %d, less than the %d bytes of MarshalHash()", s, len(bts1))
+		}
+	})
+}
+
 func BenchmarkMarshalHashStruct(b *testing.B) {
 	v := Struct{}
 	b.ReportAllocs()
@@ -0,0 +1,21 @@
+package covenant
+
+//go:generate hsp
+
+//hsp:digest sha256
+//hsp:digest blake2b Checkpoint
+//hsp:digest sha512_256 Votes
+
+type BlockHeader struct {
+	Version  int32  `hsp:"0"`
+	Producer string `hsp:"1"`
+	Height   uint64 `hsp:"2"`
+	Root     []byte `hsp:"3"`
+}
+
+type Checkpoint struct {
+	Header BlockHeader `hsp:"0"`
+	Votes  Votes       `hsp:"1"`
+}
+
+type Votes []uint64
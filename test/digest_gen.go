@@ -0,0 +1,128 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+	"golang.org/x/crypto/blake2b"
+)
+
+// MarshalHash marshals for hash
+func (z *BlockHeader) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 4
+	o = append(o, 0x84)
+	o = hsp.AppendInt32(o, z.Version)
+	o = hsp.AppendString(o, z.Producer)
+	o = hsp.AppendUint64(o, z.Height)
+	o = hsp.AppendBytes(o, z.Root)
+	return
+}
+
+// Digest returns the sha256 digest of MarshalHash
+func (z *BlockHeader) Digest() (d [32]byte, err error) {
+	var o []byte
+	if o, err = z.MarshalHash(); err != nil {
+		return
+	}
+	d = sha256.Sum256(o)
+	return
+}
+
+// DoubleDigest returns the sha256 digest of Digest
+func (z *BlockHeader) DoubleDigest() (d [32]byte, err error) {
+	if d, err = z.Digest(); err != nil {
+		return
+	}
+	d = sha256.Sum256(d[:])
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *BlockHeader) Msgsize() (s int) {
+	s = 1 + 2 + hsp.Int32Size + 2 + hsp.StringPrefixSize + len(z.Producer) + 2 + hsp.Uint64Size + 2 + hsp.BytesPrefixSize + len(z.Root)
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *Checkpoint) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 2
+	o = append(o, 0x82)
+	if oTemp, err := z.Header.MarshalHash(); err != nil {
+		return nil, err
+	} else {
+		o = hsp.AppendBytes(o, oTemp)
+	}
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Votes)))
+	for za0001 := range z.Votes {
+		o = hsp.AppendUint64(o, z.Votes[za0001])
+	}
+	return
+}
+
+// Digest returns the blake2b digest of MarshalHash
+func (z *Checkpoint) Digest() (d [32]byte, err error) {
+	var o []byte
+	if o, err = z.MarshalHash(); err != nil {
+		return
+	}
+	d = blake2b.Sum256(o)
+	return
+}
+
+// DoubleDigest returns the blake2b digest of Digest
+func (z *Checkpoint) DoubleDigest() (d [32]byte, err error) {
+	if d, err = z.Digest(); err != nil {
+		return
+	}
+	d = blake2b.Sum256(d[:])
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Checkpoint) Msgsize() (s int) {
+	s = 1 + 2 + hsp.BytesPrefixSize + z.Header.Msgsize() + 2 + hsp.ArrayHeaderSize + (len(z.Votes) * (hsp.Uint64Size))
+	return
+}
+
+// MarshalHash marshals for hash
+func (z Votes) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	o = hsp.AppendArrayHeader(o, uint32(len(z)))
+	for za0001 := range z {
+		o = hsp.AppendUint64(o, z[za0001])
+	}
+	return
+}
+
+// Digest returns the sha512_256 digest of MarshalHash
+func (z Votes) Digest() (d [32]byte, err error) {
+	var o []byte
+	if o, err = z.MarshalHash(); err != nil {
+		return
+	}
+	d = sha512.Sum512_256(o)
+	return
+}
+
+// DoubleDigest returns the sha512_256 digest of Digest
+func (z Votes) DoubleDigest() (d [32]byte, err error) {
+	if d, err = z.Digest(); err != nil {
+		return
+	}
+	d = sha512.Sum512_256(d[:])
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z Votes) Msgsize() (s int) {
+	s = hsp.ArrayHeaderSize + (len(z) * (hsp.Uint64Size))
+	return
+}
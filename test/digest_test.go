@@ -0,0 +1,58 @@
+package covenant
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/CovenantSQL/HashStablePack/parse"
+)
+
+func TestDigest(t *testing.T) {
+	hdr := BlockHeader{Version: 1, Producer: "node", Height: 42, Root: []byte{0x01}}
+	cp := Checkpoint{Header: hdr, Votes: Votes{3, 1, 2}}
+
+	for _, c := range []struct {
+		v interface {
+			MarshalHash() ([]byte, error)
+			Digest() ([32]byte, error)
+			DoubleDigest() ([32]byte, error)
+		}
+		sum func([]byte) [32]byte
+	}{
+		{&hdr, sha256.Sum256},
+		{&cp, blake2b.Sum256},
+		{cp.Votes, sha512.Sum512_256},
+	} {
+		bts, err := c.v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := c.sum(bts)
+		d, err := c.v.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d != want {
+			t.Errorf("%T: Digest %x, want %x", c.v, d, want)
+		}
+		want = c.sum(want[:])
+		d, err = c.v.DoubleDigest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d != want {
+			t.Errorf("%T: DoubleDigest %x, want %x", c.v, d, want)
+		}
+	}
+}
+
+func TestDigestUnknownType(t *testing.T) {
+	_, err := parse.File("testdata/digest/unknown.go", false)
+	if want := "Checkpiont: no such type"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
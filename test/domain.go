@@ -0,0 +1,28 @@
+package covenant
+
+//go:generate hsp -unmarshal -stream -equal
+
+//hsp:domain SignedTx "covenantsql/tx/v1"
+//hsp:domain SignedVote "covenantsql/vote/v1"
+//hsp:tuple SignedVote
+//hsp:digest sha256 SignedTx SignedVote
+
+// SignedTx and SignedVote have the same fields,
+// their domains keep their hashes apart.
+type SignedTx struct {
+	Signer string `hsp:"0"`
+	Nonce  uint64 `hsp:"1"`
+	Memo   string `hsp:"2,omitempty"`
+}
+
+type SignedVote struct {
+	Signer string `hsp:"0"`
+	Nonce  uint64 `hsp:"1"`
+	Memo   string `hsp:"2,omitempty"`
+}
+
+// Ballot inlines the domain of its votes.
+type Ballot struct {
+	Round uint64       `hsp:"0"`
+	Votes []SignedVote `hsp:"1"`
+}
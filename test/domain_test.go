@@ -0,0 +1,100 @@
+package covenant
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+	"github.com/CovenantSQL/HashStablePack/parse"
+)
+
+func TestDomain(t *testing.T) {
+	tx := SignedTx{Signer: "alice", Nonce: 3}
+	vote := SignedVote{Signer: "alice", Nonce: 3}
+	txb, err := tx.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	voteb, err := vote.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the domain is the first object of the struct
+	want := hsp.AppendMapHeader(nil, 3)
+	want = hsp.AppendDomain(want, "covenantsql/tx/v1")
+	want = hsp.AppendString(want, "alice")
+	want = hsp.AppendUint64(want, 3)
+	if !bytes.Equal(txb, want) {
+		t.Errorf("got %x, want %x", txb, want)
+	}
+	if bytes.Equal(txb, voteb) {
+		t.Errorf("a tx and a vote with the same fields are both written as %x", txb)
+	}
+
+	// so are the digests
+	txd, err := tx.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	voted, err := vote.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txd == voted {
+		t.Error("a tx and a vote with the same fields have the same digest")
+	}
+
+	// a tx of another domain can't be decoded
+	other := hsp.AppendMapHeader(nil, 3)
+	other = hsp.AppendDomain(other, "covenantsql/vote/v1")
+	other = hsp.AppendString(other, "alice")
+	other = hsp.AppendUint64(other, 3)
+	var got SignedTx
+	if _, err = got.UnmarshalHash(other); err == nil {
+		t.Error("decoded a tx of another domain")
+	} else if _, ok := err.(hsp.DomainError); !ok {
+		t.Errorf("got error %v, want a DomainError", err)
+	}
+	if _, err = got.UnmarshalHash(txb); err != nil || got != tx {
+		t.Errorf("got %v, %v, want %v", got, err, tx)
+	}
+}
+
+func TestDomainInline(t *testing.T) {
+	vote := SignedVote{Signer: "bob", Nonce: 1, Memo: "yes"}
+	ballot := Ballot{Round: 2, Votes: []SignedVote{vote}}
+	voteb, err := vote.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bts, err := ballot.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the inlined votes are written with their domain
+	if !bytes.HasSuffix(bts, voteb) {
+		t.Errorf("%x doesn't end with the vote %x", bts, voteb)
+	}
+	// as one object, counting their domain
+	if left, err := hsp.Skip(voteb); err != nil || len(left) != 0 {
+		t.Errorf("skipped the vote %x to %x, %v", voteb, left, err)
+	}
+	if len(bts) > ballot.Msgsize() {
+		t.Errorf("Msgsize is %d, less than %d", ballot.Msgsize(), len(bts))
+	}
+}
+
+func TestDomainErrors(t *testing.T) {
+	for file, want := range map[string]string{
+		"unknown.go":   "Vote: no such type",
+		"nonstruct.go": "Nonce: only structs can have a domain",
+		"reused.go":    `domain "tx/v1" is also the one of`,
+	} {
+		_, err := parse.File("testdata/domain/"+file, false)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got error %v, want %q", file, err, want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package covenant
+
+//go:generate hsp -unmarshal -encode
+
+// Snapshot may be too large to be
+// marshaled in memory, and is
+// streamed with hsp.EncodeHash instead.
+type Snapshot struct {
+	Height   uint64            `hsp:"0"`
+	Balances map[string]uint64 `hsp:"1"`
+	Blocks   []SnapshotBlock   `hsp:"2"`
+}
+
+type SnapshotBlock struct {
+	Hash [32]byte `hsp:"0"`
+	Txs  [][]byte `hsp:"1"`
+}
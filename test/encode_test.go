@@ -0,0 +1,58 @@
+package covenant
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+func testSnapshot(n int) *Snapshot {
+	s := &Snapshot{Height: uint64(n), Balances: make(map[string]uint64, n)}
+	for i := 0; i < n; i++ {
+		s.Balances[fmt.Sprintf("account%d", i)] = uint64(i)
+		b := SnapshotBlock{Txs: [][]byte{[]byte(fmt.Sprintf("tx%d", i))}}
+		b.Hash = sha256.Sum256(b.Txs[0])
+		s.Blocks = append(s.Blocks, b)
+	}
+	return s
+}
+
+func TestEncodeHashFile(t *testing.T) {
+	s := testSnapshot(1000)
+	name := filepath.Join(t.TempDir(), "snapshot")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = hsp.EncodeHash(f, s); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := s.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("the file holds %d bytes, differing from the %d of MarshalHash", len(got), len(want))
+	}
+
+	var back Snapshot
+	if _, err = back.UnmarshalHash(got); err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Blocks) != len(s.Blocks) || back.Balances["account999"] != 999 {
+		t.Errorf("read back %d blocks and %d balances", len(back.Blocks), len(back.Balances))
+	}
+}
@@ -0,0 +1,111 @@
+package covenant
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/CovenantSQL/CovenantSQL/proto"
+)
+
+func TestEqualHashSemantics(t *testing.T) {
+	lvl := Level(3)
+	base := func() Ledger {
+		expires := time.Unix(1600000000, 0)
+		return Ledger{
+			Name:      "ledger",
+			Entries:   []Entry{{Key: "a", Score: 1}},
+			Latest:    &Entry{Key: "b"},
+			Index:     map[string]*Level{"x": &lvl, "y": nil},
+			Timestamp: time.Unix(1500000000, 1),
+			Memo:      "memo",
+			Heights:   map[int64]Name{1: "one", -1: "minus"},
+			Expires:   &expires,
+			Ignored:   "a",
+		}
+	}
+	cases := []struct {
+		name   string
+		mutate func(l *Ledger)
+	}{
+		{"same", func(l *Ledger) {}},
+		{"ignored field", func(l *Ledger) { l.Ignored = "b" }},
+		{"nil and empty slice", func(l *Ledger) { l.Entries = nil }},
+		{"nil and empty map", func(l *Ledger) { l.Owners = map[Name]uint32{} }},
+		{"nil and empty bytes", func(l *Ledger) { l.Payload = Blob{} }},
+		{"time location", func(l *Ledger) { l.Timestamp = l.Timestamp.In(time.FixedZone("x", 3600)) }},
+		{"time nanosecond", func(l *Ledger) { l.Timestamp = l.Timestamp.Add(1) }},
+		{"zero time", func(l *Ledger) { l.Timestamp = time.Time{} }},
+		{"time pointer location", func(l *Ledger) { *l.Expires = l.Expires.In(time.FixedZone("x", 3600)) }},
+		{"time pointer", func(l *Ledger) { *l.Expires = l.Expires.Add(1) }},
+		{"nil time pointer", func(l *Ledger) { l.Expires = nil }},
+		{"negative zero", func(l *Ledger) { l.Entries[0].Score = math.Copysign(0, -1) }},
+		{"nil pointer", func(l *Ledger) { l.Latest = nil }},
+		{"nil map value", func(l *Ledger) { l.Index["y"] = &lvl }},
+		{"missing key", func(l *Ledger) { delete(l.Heights, 1); l.Heights[2] = "one" }},
+		{"map value", func(l *Ledger) { l.Heights[1] = "uno" }},
+		{"interface", func(l *Ledger) { l.Memo = []byte("memo") }},
+		{"byte array", func(l *Ledger) { l.Digest[31] = 1 }},
+	}
+	for _, c := range cases {
+		a, b := base(), base()
+		c.mutate(&b)
+		abts, err := a.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bbts, err := b.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := bytes.Equal(abts, bbts)
+		if got := a.EqualHash(&b); got != want {
+			t.Errorf("%s: EqualHash = %v, want %v", c.name, got, want)
+		}
+		if got := b.EqualHash(&a); got != want {
+			t.Errorf("%s: reversed EqualHash = %v, want %v", c.name, got, want)
+		}
+	}
+
+	nan := Entry{Score: math.NaN()}
+	if !nan.EqualHash(&nan) {
+		t.Error("NaN has the same encoding, EqualHash = false")
+	}
+	var nilLedger *Ledger
+	if nilLedger.EqualHash(&Ledger{}) || !nilLedger.EqualHash(nil) {
+		t.Error("nil Ledger comparison")
+	}
+}
+
+func TestEqualHashNesting(t *testing.T) {
+	blk1, blk2 := testBlock(10), testBlock(10)
+	if !blk1.EqualHash(&blk2) {
+		t.Fatal("equal blocks differ")
+	}
+	blk2.Txs[9].Header.Amount++
+	if blk1.EqualHash(&blk2) {
+		t.Fatal("different nested values are equal")
+	}
+
+	r1 := Receipt{Signees: map[proto.NodeID]uint64{"a": 1, "b": 2}}
+	r2 := Receipt{Signees: map[proto.NodeID]uint64{"b": 2, "a": 1}}
+	if !r1.EqualHash(&r2) {
+		t.Fatal("equal receipts differ")
+	}
+	r2.BlockHash[0] = 1
+	if r1.EqualHash(&r2) {
+		t.Fatal("different hashes are equal")
+	}
+}
+
+func BenchmarkEqualHashLargeBlock(b *testing.B) {
+	blk1, blk2 := testBlock(2000), testBlock(2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !blk1.EqualHash(&blk2) {
+			b.Fatal("equal blocks differ")
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package covenant
+
+//go:generate hsp -unmarshal -stream -equal
+
+//hsp:float canonical Reading
+
+// Reading is written with canonical floats
+type Reading struct {
+	Celsius float64            `hsp:"0"`
+	Ratio   float32            `hsp:"1"`
+	Samples []float64          `hsp:"2"`
+	Peak    *float32           `hsp:"3"`
+	Bounds  map[string]float64 `hsp:"4"`
+}
+
+// ReadingV2 widens the Ratio of Reading to a float64,
+// which keeps the hashes of the existing values
+type ReadingV2 struct {
+	Celsius float64            `hsp:"0,canonical"`
+	Ratio   float64            `hsp:"1,canonical"`
+	Samples []float64          `hsp:"2,canonical"`
+	Peak    *float32           `hsp:"3,canonical"`
+	Bounds  map[string]float64 `hsp:"4,canonical"`
+}
+
+// Quote has the same encoding as Order,
+// when its Amount is a whole number
+type Quote struct {
+	ID     uint64  `hsp:"0"`
+	Amount float64 `hsp:"1,integral"`
+	Seller string  `hsp:"2"`
+}
@@ -0,0 +1,560 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"io"
+	"sort"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// MarshalHash marshals for hash
+func (z Quote) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 3
+	o = append(o, 0x83)
+	o = hsp.AppendUint64(o, z.ID)
+	o = hsp.AppendFloat64Integral(o, z.Amount)
+	o = hsp.AppendString(o, z.Seller)
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Quote) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 3 {
+		err = hsp.ArrayError{Wanted: 3, Got: zb0001}
+		return
+	}
+	z.ID, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Amount, bts, err = hsp.ReadFloat64IntegralBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Seller, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z Quote) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 3
+	err = en.Append(0x83)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.ID)
+	if err != nil {
+		return
+	}
+	err = en.WriteFloat64Integral(z.Amount)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Seller)
+	if err != nil {
+		return
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Quote) EqualHash(other *Quote) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.ID != other.ID {
+		return false
+	}
+	if !hsp.EqualFloatCanonical(z.Amount, other.Amount) {
+		return false
+	}
+	if z.Seller != other.Seller {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z Quote) Msgsize() (s int) {
+	s = 1 + 2 + hsp.Uint64Size + 2 + hsp.Float64IntegralSize + 2 + hsp.StringPrefixSize + len(z.Seller)
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *Reading) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 5
+	o = append(o, 0x85)
+	o = hsp.AppendFloat64Canonical(o, z.Celsius)
+	o = hsp.AppendFloat32Canonical(o, z.Ratio)
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Samples)))
+	for za0001 := range z.Samples {
+		o = hsp.AppendFloat64Canonical(o, z.Samples[za0001])
+	}
+	if z.Peak == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		o = hsp.AppendFloat32Canonical(o, *z.Peak)
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Bounds)))
+	za0002Slice := make([]string, 0, len(z.Bounds))
+	for i := range z.Bounds {
+		za0002Slice = append(za0002Slice, i)
+	}
+	sort.Strings(za0002Slice)
+	for _, za0002 := range za0002Slice {
+		za0003 := z.Bounds[za0002]
+		o = hsp.AppendString(o, za0002)
+		o = hsp.AppendFloat64Canonical(o, za0003)
+	}
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Reading) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 5 {
+		err = hsp.ArrayError{Wanted: 5, Got: zb0001}
+		return
+	}
+	z.Celsius, bts, err = hsp.ReadFloat64CanonicalBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Ratio, bts, err = hsp.ReadFloat32CanonicalBytes(bts)
+	if err != nil {
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = hsp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Samples) >= int(zb0002) {
+		z.Samples = (z.Samples)[:zb0002]
+	} else {
+		z.Samples = make([]float64, zb0002)
+	}
+	for za0001 := range z.Samples {
+		z.Samples[za0001], bts, err = hsp.ReadFloat64CanonicalBytes(bts)
+		if err != nil {
+			return
+		}
+	}
+	if hsp.IsNil(bts) {
+		bts, err = hsp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Peak = nil
+	} else {
+		if z.Peak == nil {
+			z.Peak = new(float32)
+		}
+		*z.Peak, bts, err = hsp.ReadFloat32CanonicalBytes(bts)
+		if err != nil {
+			return
+		}
+	}
+	var zb0003 uint32
+	zb0003, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if z.Bounds == nil {
+		z.Bounds = make(map[string]float64, zb0003)
+	} else if len(z.Bounds) > 0 {
+		for key := range z.Bounds {
+			delete(z.Bounds, key)
+		}
+	}
+	for zb0003 > 0 {
+		var za0002 string
+		var za0003 float64
+		zb0003--
+		za0002, bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+		za0003, bts, err = hsp.ReadFloat64CanonicalBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Bounds[za0002] = za0003
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *Reading) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 5
+	err = en.Append(0x85)
+	if err != nil {
+		return
+	}
+	err = en.WriteFloat64Canonical(z.Celsius)
+	if err != nil {
+		return
+	}
+	err = en.WriteFloat32Canonical(z.Ratio)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Samples)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.Samples {
+		err = en.WriteFloat64Canonical(z.Samples[za0001])
+		if err != nil {
+			return
+		}
+	}
+	if z.Peak == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = en.WriteFloat32Canonical(*z.Peak)
+		if err != nil {
+			return
+		}
+	}
+	err = en.WriteMapHeader(uint32(len(z.Bounds)))
+	if err != nil {
+		return
+	}
+	za0002Slice := make([]string, 0, len(z.Bounds))
+	for i := range z.Bounds {
+		za0002Slice = append(za0002Slice, i)
+	}
+	sort.Strings(za0002Slice)
+	for _, za0002 := range za0002Slice {
+		za0003 := z.Bounds[za0002]
+		err = en.WriteString(za0002)
+		if err != nil {
+			return
+		}
+		err = en.WriteFloat64Canonical(za0003)
+		if err != nil {
+			return
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Reading) EqualHash(other *Reading) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if !hsp.EqualFloatCanonical(z.Celsius, other.Celsius) {
+		return false
+	}
+	if !hsp.EqualFloatCanonical(float64(z.Ratio), float64(other.Ratio)) {
+		return false
+	}
+	if len(z.Samples) != len(other.Samples) {
+		return false
+	}
+	for za0001 := range z.Samples {
+		if !hsp.EqualFloatCanonical(z.Samples[za0001], other.Samples[za0001]) {
+			return false
+		}
+	}
+	if (z.Peak == nil) != (other.Peak == nil) {
+		return false
+	}
+	if z.Peak != nil {
+		if !hsp.EqualFloatCanonical(float64(*z.Peak), float64(*other.Peak)) {
+			return false
+		}
+	}
+	if len(z.Bounds) != len(other.Bounds) {
+		return false
+	}
+	for za0002, za0003 := range z.Bounds {
+		zb0001, ok := other.Bounds[za0002]
+		if !ok {
+			return false
+		}
+		if !hsp.EqualFloatCanonical(za0003, zb0001) {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Reading) Msgsize() (s int) {
+	s = 1 + 2 + hsp.Float64CanonicalSize + 2 + hsp.Float32CanonicalSize + 2 + hsp.ArrayHeaderSize + (len(z.Samples) * (hsp.Float64CanonicalSize)) + 2
+	if z.Peak == nil {
+		s += hsp.NilSize
+	} else {
+		s += hsp.Float32CanonicalSize
+	}
+	s += 2 + hsp.MapHeaderSize
+	if z.Bounds != nil {
+		for za0002, za0003 := range z.Bounds {
+			_ = za0003
+			s += hsp.StringPrefixSize + len(za0002) + hsp.Float64CanonicalSize
+		}
+	}
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *ReadingV2) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 5
+	o = append(o, 0x85)
+	o = hsp.AppendFloat64Canonical(o, z.Celsius)
+	o = hsp.AppendFloat64Canonical(o, z.Ratio)
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Samples)))
+	for za0001 := range z.Samples {
+		o = hsp.AppendFloat64Canonical(o, z.Samples[za0001])
+	}
+	if z.Peak == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		o = hsp.AppendFloat32Canonical(o, *z.Peak)
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Bounds)))
+	za0002Slice := make([]string, 0, len(z.Bounds))
+	for i := range z.Bounds {
+		za0002Slice = append(za0002Slice, i)
+	}
+	sort.Strings(za0002Slice)
+	for _, za0002 := range za0002Slice {
+		za0003 := z.Bounds[za0002]
+		o = hsp.AppendString(o, za0002)
+		o = hsp.AppendFloat64Canonical(o, za0003)
+	}
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *ReadingV2) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 5 {
+		err = hsp.ArrayError{Wanted: 5, Got: zb0001}
+		return
+	}
+	z.Celsius, bts, err = hsp.ReadFloat64CanonicalBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Ratio, bts, err = hsp.ReadFloat64CanonicalBytes(bts)
+	if err != nil {
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = hsp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Samples) >= int(zb0002) {
+		z.Samples = (z.Samples)[:zb0002]
+	} else {
+		z.Samples = make([]float64, zb0002)
+	}
+	for za0001 := range z.Samples {
+		z.Samples[za0001], bts, err = hsp.ReadFloat64CanonicalBytes(bts)
+		if err != nil {
+			return
+		}
+	}
+	if hsp.IsNil(bts) {
+		bts, err = hsp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Peak = nil
+	} else {
+		if z.Peak == nil {
+			z.Peak = new(float32)
+		}
+		*z.Peak, bts, err = hsp.ReadFloat32CanonicalBytes(bts)
+		if err != nil {
+			return
+		}
+	}
+	var zb0003 uint32
+	zb0003, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if z.Bounds == nil {
+		z.Bounds = make(map[string]float64, zb0003)
+	} else if len(z.Bounds) > 0 {
+		for key := range z.Bounds {
+			delete(z.Bounds, key)
+		}
+	}
+	for zb0003 > 0 {
+		var za0002 string
+		var za0003 float64
+		zb0003--
+		za0002, bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+		za0003, bts, err = hsp.ReadFloat64CanonicalBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Bounds[za0002] = za0003
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *ReadingV2) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 5
+	err = en.Append(0x85)
+	if err != nil {
+		return
+	}
+	err = en.WriteFloat64Canonical(z.Celsius)
+	if err != nil {
+		return
+	}
+	err = en.WriteFloat64Canonical(z.Ratio)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Samples)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.Samples {
+		err = en.WriteFloat64Canonical(z.Samples[za0001])
+		if err != nil {
+			return
+		}
+	}
+	if z.Peak == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = en.WriteFloat32Canonical(*z.Peak)
+		if err != nil {
+			return
+		}
+	}
+	err = en.WriteMapHeader(uint32(len(z.Bounds)))
+	if err != nil {
+		return
+	}
+	za0002Slice := make([]string, 0, len(z.Bounds))
+	for i := range z.Bounds {
+		za0002Slice = append(za0002Slice, i)
+	}
+	sort.Strings(za0002Slice)
+	for _, za0002 := range za0002Slice {
+		za0003 := z.Bounds[za0002]
+		err = en.WriteString(za0002)
+		if err != nil {
+			return
+		}
+		err = en.WriteFloat64Canonical(za0003)
+		if err != nil {
+			return
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *ReadingV2) EqualHash(other *ReadingV2) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if !hsp.EqualFloatCanonical(z.Celsius, other.Celsius) {
+		return false
+	}
+	if !hsp.EqualFloatCanonical(z.Ratio, other.Ratio) {
+		return false
+	}
+	if len(z.Samples) != len(other.Samples) {
+		return false
+	}
+	for za0001 := range z.Samples {
+		if !hsp.EqualFloatCanonical(z.Samples[za0001], other.Samples[za0001]) {
+			return false
+		}
+	}
+	if (z.Peak == nil) != (other.Peak == nil) {
+		return false
+	}
+	if z.Peak != nil {
+		if !hsp.EqualFloatCanonical(float64(*z.Peak), float64(*other.Peak)) {
+			return false
+		}
+	}
+	if len(z.Bounds) != len(other.Bounds) {
+		return false
+	}
+	for za0002, za0003 := range z.Bounds {
+		zb0001, ok := other.Bounds[za0002]
+		if !ok {
+			return false
+		}
+		if !hsp.EqualFloatCanonical(za0003, zb0001) {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *ReadingV2) Msgsize() (s int) {
+	s = 1 + 2 + hsp.Float64CanonicalSize + 2 + hsp.Float64CanonicalSize + 2 + hsp.ArrayHeaderSize + (len(z.Samples) * (hsp.Float64CanonicalSize)) + 2
+	if z.Peak == nil {
+		s += hsp.NilSize
+	} else {
+		s += hsp.Float32CanonicalSize
+	}
+	s += 2 + hsp.MapHeaderSize
+	if z.Bounds != nil {
+		for za0002, za0003 := range z.Bounds {
+			_ = za0003
+			s += hsp.StringPrefixSize + len(za0002) + hsp.Float64CanonicalSize
+		}
+	}
+	return
+}
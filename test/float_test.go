@@ -0,0 +1,100 @@
+package covenant
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/CovenantSQL/HashStablePack/parse"
+)
+
+func TestCanonicalFloatHash(t *testing.T) {
+	nan := math.Float64frombits(0x7ff8000000000001)
+	negZero := math.Copysign(0, -1)
+	peak := float32(math.NaN())
+	r1 := Reading{
+		Celsius: negZero,
+		Ratio:   0.5,
+		Samples: []float64{math.NaN(), 1.5},
+		Peak:    &peak,
+		Bounds:  map[string]float64{"low": negZero},
+	}
+	canonicalPeak := float32(math.Float64frombits(0x7ff8000000000000))
+	r2 := Reading{
+		Ratio:   0.5,
+		Samples: []float64{nan, 1.5},
+		Peak:    &canonicalPeak,
+		Bounds:  map[string]float64{"low": 0},
+	}
+	bts1, err := r1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bts2, err := r2.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bts1, bts2) {
+		t.Fatalf("NaNs or -0 changed the hash:\n%x\n%x", bts1, bts2)
+	}
+	if !r1.EqualHash(&r2) {
+		t.Fatal("EqualHash should ignore the NaN payloads and the sign of 0")
+	}
+
+	// float32 fields are widened
+	r3 := ReadingV2{
+		Celsius: r1.Celsius,
+		Ratio:   float64(r1.Ratio),
+		Samples: r1.Samples,
+		Peak:    r1.Peak,
+		Bounds:  r1.Bounds,
+	}
+	bts3, err := r3.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bts1, bts3) {
+		t.Fatalf("widening a float32 field changed the hash:\n%x\n%x", bts1, bts3)
+	}
+}
+
+func TestIntegralFloatHash(t *testing.T) {
+	for _, amount := range []int64{0, 1, -7, math.MaxInt32 + 1, math.MinInt64} {
+		o := Order{ID: 42, Amount: amount, Seller: "alice"}
+		q := Quote{ID: 42, Amount: float64(amount), Seller: "alice"}
+		bts1, err := o.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bts2, err := q.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Errorf("whole amount %d written as %x, want %x", amount, bts2, bts1)
+		}
+	}
+
+	for _, amount := range []float64{0.5, math.Inf(1), math.NaN(), 1 << 63} {
+		q1 := Quote{ID: 1, Amount: amount}
+		bts, err := q1.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var q2 Quote
+		if _, err = q2.UnmarshalHash(bts); err != nil {
+			t.Fatal(err)
+		}
+		if !q1.EqualHash(&q2) {
+			t.Errorf("decoded amount %v, want %v", q2.Amount, amount)
+		}
+	}
+}
+
+func TestFloatUnknownType(t *testing.T) {
+	_, err := parse.File("testdata/float/unknown.go", false)
+	if want := "Readng: no such type"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,28 @@
+package covenant
+
+import hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+
+//go:generate hsp -unmarshal -stream -equal
+
+//hsp:nesting inline
+
+// Page is a generic container
+type Page[T hsp.HashMarshaler] struct {
+	Items []T          `hsp:"0"`
+	Next  *T           `hsp:"1"`
+	Index map[string]T `hsp:"2"`
+	Total uint64       `hsp:"3"`
+}
+
+// Pair has two type parameters
+type Pair[K, V hsp.HashMarshaler] struct {
+	Key   K `hsp:"0"`
+	Value V `hsp:"1"`
+}
+
+// EntryPage instantiates the generic types
+type EntryPage struct {
+	Page  Page[*Entry]           `hsp:"0"`
+	Pairs []Pair[*Entry, *Entry] `hsp:"1"`
+	Last  *Page[*Entry]          `hsp:"2"`
+}
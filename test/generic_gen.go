@@ -0,0 +1,513 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"io"
+	"sort"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// MarshalHash marshals for hash
+func (z *EntryPage) MarshalHash() (o []byte, err error) {
+	var b []byte
+	return z.AppendHash(hsp.Require(b, z.Msgsize()))
+}
+
+// AppendHash appends the hash encoding to b
+func (z *EntryPage) AppendHash(b []byte) (o []byte, err error) {
+	o = b
+	// map header, size 3
+	o = append(o, 0x83)
+	if o, err = z.Page.AppendHash(o); err != nil {
+		return
+	}
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Pairs)))
+	for za0001 := range z.Pairs {
+		if o, err = z.Pairs[za0001].AppendHash(o); err != nil {
+			return
+		}
+	}
+	if z.Last == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		if o, err = z.Last.AppendHash(o); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *EntryPage) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 3 {
+		err = hsp.ArrayError{Wanted: 3, Got: zb0001}
+		return
+	}
+	bts, err = z.Page.UnmarshalHash(bts)
+	if err != nil {
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = hsp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Pairs) >= int(zb0002) {
+		z.Pairs = (z.Pairs)[:zb0002]
+	} else {
+		z.Pairs = make([]Pair[*Entry, *Entry], zb0002)
+	}
+	for za0001 := range z.Pairs {
+		bts, err = z.Pairs[za0001].UnmarshalHash(bts)
+		if err != nil {
+			return
+		}
+	}
+	if hsp.IsNil(bts) {
+		bts, err = hsp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Last = nil
+	} else {
+		if z.Last == nil {
+			z.Last = new(Page[*Entry])
+		}
+		bts, err = z.Last.UnmarshalHash(bts)
+		if err != nil {
+			return
+		}
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *EntryPage) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 3
+	err = en.Append(0x83)
+	if err != nil {
+		return
+	}
+	err = z.Page.WriteHash(en)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Pairs)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.Pairs {
+		err = z.Pairs[za0001].WriteHash(en)
+		if err != nil {
+			return
+		}
+	}
+	if z.Last == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Last.WriteHash(en)
+		if err != nil {
+			return
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *EntryPage) EqualHash(other *EntryPage) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if !z.Page.EqualHash(&other.Page) {
+		return false
+	}
+	if len(z.Pairs) != len(other.Pairs) {
+		return false
+	}
+	for za0001 := range z.Pairs {
+		if !z.Pairs[za0001].EqualHash(&other.Pairs[za0001]) {
+			return false
+		}
+	}
+	if (z.Last == nil) != (other.Last == nil) {
+		return false
+	}
+	if z.Last != nil {
+		if !z.Last.EqualHash(other.Last) {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *EntryPage) Msgsize() (s int) {
+	s = 1 + 2 + z.Page.Msgsize() + 2 + hsp.ArrayHeaderSize
+	for za0001 := range z.Pairs {
+		s += z.Pairs[za0001].Msgsize()
+	}
+	s += 2
+	if z.Last == nil {
+		s += hsp.NilSize
+	} else {
+		s += z.Last.Msgsize()
+	}
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *Page[T]) MarshalHash() (o []byte, err error) {
+	var b []byte
+	return z.AppendHash(hsp.Require(b, z.Msgsize()))
+}
+
+// AppendHash appends the hash encoding to b
+func (z *Page[T]) AppendHash(b []byte) (o []byte, err error) {
+	o = b
+	// map header, size 4
+	o = append(o, 0x84)
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Items)))
+	for za0001 := range z.Items {
+		o, err = hsp.AppendIntfHash(o, z.Items[za0001])
+		if err != nil {
+			return
+		}
+	}
+	if z.Next == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		o, err = hsp.AppendIntfHash(o, (*z.Next))
+		if err != nil {
+			return
+		}
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Index)))
+	za0002Slice := make([]string, 0, len(z.Index))
+	for i := range z.Index {
+		za0002Slice = append(za0002Slice, i)
+	}
+	sort.Strings(za0002Slice)
+	for _, za0002 := range za0002Slice {
+		za0003 := z.Index[za0002]
+		o = hsp.AppendString(o, za0002)
+		o, err = hsp.AppendIntfHash(o, za0003)
+		if err != nil {
+			return
+		}
+	}
+	o = hsp.AppendUint64(o, z.Total)
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Page[T]) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 4 {
+		err = hsp.ArrayError{Wanted: 4, Got: zb0001}
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = hsp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Items) >= int(zb0002) {
+		z.Items = (z.Items)[:zb0002]
+	} else {
+		z.Items = make([]T, zb0002)
+	}
+	for za0001 := range z.Items {
+		bts, err = hsp.ReadHashMarshalerBytes(bts, &z.Items[za0001])
+		if err != nil {
+			return
+		}
+	}
+	if hsp.IsNil(bts) {
+		bts, err = hsp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Next = nil
+	} else {
+		if z.Next == nil {
+			z.Next = new(T)
+		}
+		bts, err = hsp.ReadHashMarshalerBytes(bts, &(*z.Next))
+		if err != nil {
+			return
+		}
+	}
+	var zb0003 uint32
+	zb0003, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if z.Index == nil {
+		z.Index = make(map[string]T, zb0003)
+	} else if len(z.Index) > 0 {
+		for key := range z.Index {
+			delete(z.Index, key)
+		}
+	}
+	for zb0003 > 0 {
+		var za0002 string
+		var za0003 T
+		zb0003--
+		za0002, bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+		bts, err = hsp.ReadHashMarshalerBytes(bts, &za0003)
+		if err != nil {
+			return
+		}
+		z.Index[za0002] = za0003
+	}
+	z.Total, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *Page[T]) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 4
+	err = en.Append(0x84)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Items)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.Items {
+		if oTemp, err := hsp.AppendIntfHash(nil, z.Items[za0001]); err != nil {
+			return err
+		} else {
+			_, err = en.Write(oTemp)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if z.Next == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		if oTemp, err := hsp.AppendIntfHash(nil, (*z.Next)); err != nil {
+			return err
+		} else {
+			_, err = en.Write(oTemp)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err = en.WriteMapHeader(uint32(len(z.Index)))
+	if err != nil {
+		return
+	}
+	za0002Slice := make([]string, 0, len(z.Index))
+	for i := range z.Index {
+		za0002Slice = append(za0002Slice, i)
+	}
+	sort.Strings(za0002Slice)
+	for _, za0002 := range za0002Slice {
+		za0003 := z.Index[za0002]
+		err = en.WriteString(za0002)
+		if err != nil {
+			return
+		}
+		if oTemp, err := hsp.AppendIntfHash(nil, za0003); err != nil {
+			return err
+		} else {
+			_, err = en.Write(oTemp)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err = en.WriteUint64(z.Total)
+	if err != nil {
+		return
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Page[T]) EqualHash(other *Page[T]) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if len(z.Items) != len(other.Items) {
+		return false
+	}
+	for za0001 := range z.Items {
+		if !hsp.EqualIntf(z.Items[za0001], other.Items[za0001]) {
+			return false
+		}
+	}
+	if (z.Next == nil) != (other.Next == nil) {
+		return false
+	}
+	if z.Next != nil {
+		if !hsp.EqualIntf((*z.Next), (*other.Next)) {
+			return false
+		}
+	}
+	if len(z.Index) != len(other.Index) {
+		return false
+	}
+	for za0002, za0003 := range z.Index {
+		zb0001, ok := other.Index[za0002]
+		if !ok {
+			return false
+		}
+		if !hsp.EqualIntf(za0003, zb0001) {
+			return false
+		}
+	}
+	if z.Total != other.Total {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Page[T]) Msgsize() (s int) {
+	s = 1 + 2 + hsp.ArrayHeaderSize
+	for za0001 := range z.Items {
+		s += hsp.HashSize(z.Items[za0001])
+	}
+	s += 2
+	if z.Next == nil {
+		s += hsp.NilSize
+	} else {
+		s += hsp.HashSize((*z.Next))
+	}
+	s += 2 + hsp.MapHeaderSize
+	if z.Index != nil {
+		for za0002, za0003 := range z.Index {
+			_ = za0003
+			s += hsp.StringPrefixSize + len(za0002) + hsp.HashSize(za0003)
+		}
+	}
+	s += 2 + hsp.Uint64Size
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *Pair[K, V]) MarshalHash() (o []byte, err error) {
+	var b []byte
+	return z.AppendHash(hsp.Require(b, z.Msgsize()))
+}
+
+// AppendHash appends the hash encoding to b
+func (z *Pair[K, V]) AppendHash(b []byte) (o []byte, err error) {
+	o = b
+	// map header, size 2
+	o = append(o, 0x82)
+	o, err = hsp.AppendIntfHash(o, z.Key)
+	if err != nil {
+		return
+	}
+	o, err = hsp.AppendIntfHash(o, z.Value)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Pair[K, V]) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 2 {
+		err = hsp.ArrayError{Wanted: 2, Got: zb0001}
+		return
+	}
+	bts, err = hsp.ReadHashMarshalerBytes(bts, &z.Key)
+	if err != nil {
+		return
+	}
+	bts, err = hsp.ReadHashMarshalerBytes(bts, &z.Value)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *Pair[K, V]) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 2
+	err = en.Append(0x82)
+	if err != nil {
+		return
+	}
+	if oTemp, err := hsp.AppendIntfHash(nil, z.Key); err != nil {
+		return err
+	} else {
+		_, err = en.Write(oTemp)
+		if err != nil {
+			return err
+		}
+	}
+	if oTemp, err := hsp.AppendIntfHash(nil, z.Value); err != nil {
+		return err
+	} else {
+		_, err = en.Write(oTemp)
+		if err != nil {
+			return err
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Pair[K, V]) EqualHash(other *Pair[K, V]) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if !hsp.EqualIntf(z.Key, other.Key) {
+		return false
+	}
+	if !hsp.EqualIntf(z.Value, other.Value) {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Pair[K, V]) Msgsize() (s int) {
+	s = 1 + 2 + hsp.HashSize(z.Key) + 2 + hsp.HashSize(z.Value)
+	return
+}
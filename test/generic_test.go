@@ -0,0 +1,107 @@
+package covenant
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+	"github.com/CovenantSQL/HashStablePack/parse"
+)
+
+func TestGenericRoundTrip(t *testing.T) {
+	a := &Entry{Key: "a", Value: []byte{0x01}, Score: 1.5}
+	b := &Entry{Key: "b", Score: -2}
+	p1 := EntryPage{
+		Page: Page[*Entry]{
+			Items: []*Entry{a, nil, b},
+			Next:  &b,
+			Index: map[string]*Entry{"a": a, "b": b},
+			Total: 3,
+		},
+		Pairs: []Pair[*Entry, *Entry]{{Key: a, Value: b}, {Key: b}},
+	}
+	bts1, err := p1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bts1) > p1.Msgsize() {
+		t.Fatalf("Msgsize() = %d, encoded %d bytes", p1.Msgsize(), len(bts1))
+	}
+
+	var p2 EntryPage
+	left, err := p2.UnmarshalHash(bts1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Fatalf("%d bytes left over after UnmarshalHash()", len(left))
+	}
+	if p2.Page.Items[1] != nil || p2.Page.Index["a"].Key != "a" || (*p2.Page.Next).Score != -2 {
+		t.Fatalf("decoded %+v", p2.Page)
+	}
+	if p2.Pairs[1].Value != nil || p2.Last != nil {
+		t.Fatalf("decoded %+v", p2)
+	}
+	if !p1.EqualHash(&p2) {
+		t.Fatal("decoded value differs")
+	}
+	bts2, err := p2.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bts1, bts2) {
+		t.Fatal("hash not stable after round trip")
+	}
+
+	var buf bytes.Buffer
+	if err = p1.WriteHash(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), bts1) {
+		t.Fatal("WriteHash differs from MarshalHash")
+	}
+
+	p2.Pairs[0].Value.Score = 3
+	if p1.EqualHash(&p2) {
+		t.Fatal("EqualHash should report the change")
+	}
+}
+
+func TestGenericNested(t *testing.T) {
+	// type parameters are nested as the
+	// types of other packages
+	e := &Entry{Key: "k"}
+	inner, err := e.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Pair[*Entry, *Entry]{Key: e}
+	got, err := p.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := hsp.AppendMapHeader(nil, 2)
+	want = hsp.AppendBytes(want, inner)
+	want = hsp.AppendNil(want)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got %x, want %x", got, want)
+	}
+}
+
+func TestGenericConstraint(t *testing.T) {
+	for file, want := range map[string]string{
+		"any.go":    "Box: type parameter T: constraint any doesn't implement hsp.HashMarshaler",
+		"suffix.go": "Box: type parameter K: constraint fooHashMarshaler doesn't implement hsp.HashMarshaler",
+	} {
+		_, err := parse.File("testdata/generic/"+file, false)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got error %v, want %q", file, err, want)
+		}
+	}
+
+	// interfaces embedding hsp.HashMarshaler are fine
+	if _, err := parse.File("testdata/generic/embedded.go", false); err != nil {
+		t.Errorf("embedded.go: %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package covenant
+
+import "time"
+
+//go:generate hsp -golden
+
+//hsp:golden Wallet NewWalletEmpty NewWalletGenesis
+
+// Wallet is checked against the samples
+// returned by its constructors
+type Wallet struct {
+	Address string            `hsp:"0"`
+	Balance uint64            `hsp:"1"`
+	Nonce   uint32            `hsp:"2"`
+	Tokens  map[string]uint64 `hsp:"3"`
+	Created time.Time         `hsp:"4"`
+}
+
+// NewWalletEmpty is a golden sample of Wallet
+func NewWalletEmpty() Wallet {
+	return Wallet{}
+}
+
+// NewWalletGenesis is a golden sample of Wallet
+func NewWalletGenesis() Wallet {
+	return Wallet{
+		Address: "4kKmVH5hS3VyJAT9GDLWJGmXRNPwRGhrJ5NXXgp6rCYgmpqRKTn",
+		Balance: 1e9,
+		Nonce:   1,
+		Tokens:  map[string]uint64{"Particle": 100, "Wave": 7},
+		Created: time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// Transfer is checked against random samples
+type Transfer struct {
+	From    string   `hsp:"0"`
+	To      string   `hsp:"1"`
+	Amount  uint64   `hsp:"2"`
+	Fee     float64  `hsp:"3"`
+	Memo    []byte   `hsp:"4"`
+	Witness []string `hsp:"5"`
+}
@@ -0,0 +1,73 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"sort"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// MarshalHash marshals for hash
+func (z *Transfer) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 6
+	o = append(o, 0x86)
+	o = hsp.AppendString(o, z.From)
+	o = hsp.AppendString(o, z.To)
+	o = hsp.AppendUint64(o, z.Amount)
+	o = hsp.AppendFloat64(o, z.Fee)
+	o = hsp.AppendBytes(o, z.Memo)
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Witness)))
+	for za0001 := range z.Witness {
+		o = hsp.AppendString(o, z.Witness[za0001])
+	}
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Transfer) Msgsize() (s int) {
+	s = 1 + 2 + hsp.StringPrefixSize + len(z.From) + 2 + hsp.StringPrefixSize + len(z.To) + 2 + hsp.Uint64Size + 2 + hsp.Float64Size + 2 + hsp.BytesPrefixSize + len(z.Memo) + 2 + hsp.ArrayHeaderSize
+	for za0001 := range z.Witness {
+		s += hsp.StringPrefixSize + len(z.Witness[za0001])
+	}
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *Wallet) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 5
+	o = append(o, 0x85)
+	o = hsp.AppendString(o, z.Address)
+	o = hsp.AppendUint64(o, z.Balance)
+	o = hsp.AppendUint32(o, z.Nonce)
+	o = hsp.AppendMapHeader(o, uint32(len(z.Tokens)))
+	za0001Slice := make([]string, 0, len(z.Tokens))
+	for i := range z.Tokens {
+		za0001Slice = append(za0001Slice, i)
+	}
+	sort.Strings(za0001Slice)
+	for _, za0001 := range za0001Slice {
+		za0002 := z.Tokens[za0001]
+		o = hsp.AppendString(o, za0001)
+		o = hsp.AppendUint64(o, za0002)
+	}
+	o = hsp.AppendTime(o, z.Created)
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Wallet) Msgsize() (s int) {
+	s = 1 + 2 + hsp.StringPrefixSize + len(z.Address) + 2 + hsp.Uint64Size + 2 + hsp.Uint32Size + 2 + hsp.MapHeaderSize
+	if z.Tokens != nil {
+		for za0001, za0002 := range z.Tokens {
+			_ = za0002
+			s += hsp.StringPrefixSize + len(za0001) + hsp.Uint64Size
+		}
+	}
+	s += 2 + hsp.TimeSize
+	return
+}
@@ -0,0 +1,31 @@
+package covenant
+
+import hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+
+//go:generate hsp -unmarshal -stream -equal
+
+//hsp:cache FlatBlockInline with:setters
+
+// Header holds the fields shared by
+// FlatBlock and FlatBlockInline
+type Header struct {
+	Version  int32  `hsp:"0"`
+	Producer string `hsp:"1"`
+	Height   uint64 `hsp:"2"`
+}
+
+// FlatBlock declares the fields of Header
+type FlatBlock struct {
+	Version  int32    `hsp:"0"`
+	Producer string   `hsp:"1"`
+	Height   uint64   `hsp:"2"`
+	Txs      []string `hsp:"3"`
+}
+
+// FlatBlockInline embeds them, and
+// hashes as FlatBlock
+type FlatBlockInline struct {
+	Header `hsp:",inline"`
+	Txs    []string `hsp:"3"`
+	digest hsp.DigestCache
+}
@@ -0,0 +1,412 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"crypto/sha256"
+	"io"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// MarshalHash marshals for hash
+func (z *FlatBlock) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 4
+	o = append(o, 0x84)
+	o = hsp.AppendInt32(o, z.Version)
+	o = hsp.AppendString(o, z.Producer)
+	o = hsp.AppendUint64(o, z.Height)
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Txs)))
+	for za0001 := range z.Txs {
+		o = hsp.AppendString(o, z.Txs[za0001])
+	}
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *FlatBlock) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 4 {
+		err = hsp.ArrayError{Wanted: 4, Got: zb0001}
+		return
+	}
+	z.Version, bts, err = hsp.ReadInt32Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Producer, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Height, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = hsp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Txs) >= int(zb0002) {
+		z.Txs = (z.Txs)[:zb0002]
+	} else {
+		z.Txs = make([]string, zb0002)
+	}
+	for za0001 := range z.Txs {
+		z.Txs[za0001], bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *FlatBlock) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 4
+	err = en.Append(0x84)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt32(z.Version)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Producer)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.Height)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Txs)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.Txs {
+		err = en.WriteString(z.Txs[za0001])
+		if err != nil {
+			return
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *FlatBlock) EqualHash(other *FlatBlock) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.Version != other.Version {
+		return false
+	}
+	if z.Producer != other.Producer {
+		return false
+	}
+	if z.Height != other.Height {
+		return false
+	}
+	if len(z.Txs) != len(other.Txs) {
+		return false
+	}
+	for za0001 := range z.Txs {
+		if z.Txs[za0001] != other.Txs[za0001] {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *FlatBlock) Msgsize() (s int) {
+	s = 1 + 2 + hsp.Int32Size + 2 + hsp.StringPrefixSize + len(z.Producer) + 2 + hsp.Uint64Size + 2 + hsp.ArrayHeaderSize
+	for za0001 := range z.Txs {
+		s += hsp.StringPrefixSize + len(z.Txs[za0001])
+	}
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *FlatBlockInline) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 4
+	o = append(o, 0x84)
+	o = hsp.AppendInt32(o, z.Header.Version)
+	o = hsp.AppendString(o, z.Header.Producer)
+	o = hsp.AppendUint64(o, z.Header.Height)
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Txs)))
+	for za0001 := range z.Txs {
+		o = hsp.AppendString(o, z.Txs[za0001])
+	}
+	return
+}
+
+// Digest returns the sha256 digest of MarshalHash
+func (z *FlatBlockInline) Digest() (d [32]byte, err error) {
+	var o []byte
+	if o, err = z.MarshalHash(); err != nil {
+		return
+	}
+	d = sha256.Sum256(o)
+	return
+}
+
+// DoubleDigest returns the sha256 digest of Digest
+func (z *FlatBlockInline) DoubleDigest() (d [32]byte, err error) {
+	if d, err = z.Digest(); err != nil {
+		return
+	}
+	d = sha256.Sum256(d[:])
+	return
+}
+
+// CachedDigest returns Digest, computed once until InvalidateHash is called
+func (z *FlatBlockInline) CachedDigest() (d [32]byte, err error) {
+	var ok bool
+	if d, ok = z.digest.Load(); ok {
+		return
+	}
+	if d, err = z.Digest(); err != nil {
+		return
+	}
+	z.digest.Store(d)
+	return
+}
+
+// InvalidateHash drops the digest cached by CachedDigest
+func (z *FlatBlockInline) InvalidateHash() {
+	z.digest.Invalidate()
+}
+
+// SetHeaderVersion sets Header.Version and invalidates the cached digest
+func (z *FlatBlockInline) SetHeaderVersion(v int32) {
+	z.Header.Version = v
+	z.digest.Invalidate()
+}
+
+// SetHeaderProducer sets Header.Producer and invalidates the cached digest
+func (z *FlatBlockInline) SetHeaderProducer(v string) {
+	z.Header.Producer = v
+	z.digest.Invalidate()
+}
+
+// SetHeaderHeight sets Header.Height and invalidates the cached digest
+func (z *FlatBlockInline) SetHeaderHeight(v uint64) {
+	z.Header.Height = v
+	z.digest.Invalidate()
+}
+
+// SetTxs sets Txs and invalidates the cached digest
+func (z *FlatBlockInline) SetTxs(v []string) {
+	z.Txs = v
+	z.digest.Invalidate()
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *FlatBlockInline) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 4 {
+		err = hsp.ArrayError{Wanted: 4, Got: zb0001}
+		return
+	}
+	z.Header.Version, bts, err = hsp.ReadInt32Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Header.Producer, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Header.Height, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = hsp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Txs) >= int(zb0002) {
+		z.Txs = (z.Txs)[:zb0002]
+	} else {
+		z.Txs = make([]string, zb0002)
+	}
+	for za0001 := range z.Txs {
+		z.Txs[za0001], bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+	}
+	z.digest.Invalidate()
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *FlatBlockInline) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 4
+	err = en.Append(0x84)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt32(z.Header.Version)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Header.Producer)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.Header.Height)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Txs)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.Txs {
+		err = en.WriteString(z.Txs[za0001])
+		if err != nil {
+			return
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *FlatBlockInline) EqualHash(other *FlatBlockInline) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.Header.Version != other.Header.Version {
+		return false
+	}
+	if z.Header.Producer != other.Header.Producer {
+		return false
+	}
+	if z.Header.Height != other.Header.Height {
+		return false
+	}
+	if len(z.Txs) != len(other.Txs) {
+		return false
+	}
+	for za0001 := range z.Txs {
+		if z.Txs[za0001] != other.Txs[za0001] {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *FlatBlockInline) Msgsize() (s int) {
+	s = 1 + 2 + hsp.Int32Size + 2 + hsp.StringPrefixSize + len(z.Header.Producer) + 2 + hsp.Uint64Size + 2 + hsp.ArrayHeaderSize
+	for za0001 := range z.Txs {
+		s += hsp.StringPrefixSize + len(z.Txs[za0001])
+	}
+	return
+}
+
+// MarshalHash marshals for hash
+func (z Header) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 3
+	o = append(o, 0x83)
+	o = hsp.AppendInt32(o, z.Version)
+	o = hsp.AppendString(o, z.Producer)
+	o = hsp.AppendUint64(o, z.Height)
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Header) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 3 {
+		err = hsp.ArrayError{Wanted: 3, Got: zb0001}
+		return
+	}
+	z.Version, bts, err = hsp.ReadInt32Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Producer, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Height, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z Header) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 3
+	err = en.Append(0x83)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt32(z.Version)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Producer)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.Height)
+	if err != nil {
+		return
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Header) EqualHash(other *Header) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.Version != other.Version {
+		return false
+	}
+	if z.Producer != other.Producer {
+		return false
+	}
+	if z.Height != other.Height {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z Header) Msgsize() (s int) {
+	s = 1 + 2 + hsp.Int32Size + 2 + hsp.StringPrefixSize + len(z.Producer) + 2 + hsp.Uint64Size
+	return
+}
@@ -0,0 +1,45 @@
+package covenant
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/CovenantSQL/HashStablePack/parse"
+)
+
+func TestInlineHashesAsFlat(t *testing.T) {
+	flat := FlatBlock{Version: 2, Producer: "miner", Height: 1024, Txs: []string{"a", "b"}}
+	var inl FlatBlockInline
+	inl.SetHeaderVersion(2)
+	inl.SetHeaderProducer("miner")
+	inl.SetHeaderHeight(1024)
+	inl.Txs = []string{"a", "b"}
+
+	bts1, err := flat.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bts2, err := inl.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bts1, bts2) {
+		t.Fatalf("flattened fields hash differently:\n%x\n%x", bts1, bts2)
+	}
+
+	var dec FlatBlockInline
+	if _, err = dec.UnmarshalHash(bts1); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Height != 1024 || dec.Producer != "miner" || !dec.EqualHash(&inl) {
+		t.Fatalf("decoded %+v, want %+v", dec, inl)
+	}
+}
+
+func TestInlineTagCollision(t *testing.T) {
+	_, err := parse.File("testdata/collide/collide.go", false)
+	if err == nil || !strings.Contains(err.Error(), `tag "0" of Parent collides with Header.Height`) {
+		t.Errorf("expected a tag collision error, got %v", err)
+	}
+}
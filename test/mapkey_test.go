@@ -0,0 +1,160 @@
+package covenant
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/CovenantSQL/CovenantSQL/crypto/hash"
+	"github.com/CovenantSQL/CovenantSQL/proto"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+func TestMarshalHashMapKeyStable(t *testing.T) {
+	a := Accounts{
+		Balances: map[int64]uint64{-2: 1, 3: 2, -1: 3, 0: 4},
+		Nonces:   map[uint32]MyInt{7: 1, 1: 2, 300: 3},
+		Hashes:   map[hash.Hash]string{{0x02}: "b", {0x01}: "a", {0x03}: "c"},
+		Nodes:    map[proto.NodeID]int32{"node2": 2, "node1": 1, "node10": 10},
+		Prefixes: map[[4]byte]bool{{0xff}: true, {0x00, 0x01}: false, {0x00}: true},
+	}
+	bts1, err := a.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 32; i++ {
+		bts2, err := a.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatal("hash not stable")
+		}
+	}
+}
+
+func TestMarshalHashMapKeyOrder(t *testing.T) {
+	a := Accounts{
+		Balances: map[int64]uint64{3: 2, -1: 3, -2: 1},
+	}
+	bts, err := a.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// integers are sorted by numeric value,
+	// not by their encoded bytes
+	want := hsp.AppendMapHeader(nil, 5)
+	want = hsp.AppendMapHeader(want, 3)
+	want = hsp.AppendInt64(want, -2)
+	want = hsp.AppendUint64(want, 1)
+	want = hsp.AppendInt64(want, -1)
+	want = hsp.AppendUint64(want, 3)
+	want = hsp.AppendInt64(want, 3)
+	want = hsp.AppendUint64(want, 2)
+	for i := 0; i < 4; i++ {
+		want = hsp.AppendMapHeader(want, 0)
+	}
+	if !bytes.Equal(bts, want) {
+		t.Fatalf("got %x, want %x", bts, want)
+	}
+}
+
+// the named strings of other packages, e.g.
+// proto.NodeID, are sorted by their MarshalHash
+// output, like the other named types, and not
+// byte-wise, as the local named strings are
+func TestMarshalHashMapKeyForeignString(t *testing.T) {
+	a := Accounts{Nodes: map[proto.NodeID]int32{"node2": 2, "node1": 1, "node10": 10}}
+	bts, err := a.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type entry struct {
+		enc []byte
+		v   int32
+	}
+	var entries []entry
+	for k, v := range a.Nodes {
+		enc, err := k.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry{enc, v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].enc, entries[j].enc) < 0 })
+
+	want := hsp.AppendMapHeader(nil, 5)
+	for i := 0; i < 3; i++ {
+		want = hsp.AppendMapHeader(want, 0)
+	}
+	want = hsp.AppendMapHeader(want, 3)
+	for _, e := range entries {
+		want = hsp.AppendBytes(want, e.enc)
+		want = hsp.AppendInt32(want, e.v)
+	}
+	want = hsp.AppendMapHeader(want, 0)
+	if !bytes.Equal(bts, want) {
+		t.Fatalf("got %x, want %x", bts, want)
+	}
+}
+
+func TestUnmarshalHashMapKeys(t *testing.T) {
+	l1 := Ledger{
+		Heights:  map[int64]Name{-5: "a", 10: "b", 0: "c"},
+		Owners:   map[Name]uint32{"carol": 3, "alice": 1, "bob": 2},
+		Prefixes: map[[4]byte]Level{{0x01}: 1, {0x00, 0x02}: 2},
+	}
+	bts1, err := l1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l2 Ledger
+	if _, err = l2.UnmarshalHash(bts1); err != nil {
+		t.Fatal(err)
+	}
+	if l2.Heights[-5] != "a" || l2.Owners["bob"] != 2 || l2.Prefixes[[4]byte{0x00, 0x02}] != 2 {
+		t.Fatalf("decoded %+v", l2)
+	}
+	bts2, err := l2.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bts1, bts2) {
+		t.Fatal("hash not stable after round trip")
+	}
+}
+
+func TestMarshalHashIntfStable(t *testing.T) {
+	memo := map[string]interface{}{}
+	for i := 0; i < 64; i++ {
+		memo[string(rune('a'+i))] = map[string]string{"x": "1", "y": "2", "z": "3"}
+	}
+	memo["entry"] = Entry{Key: "k"}
+	l := Ledger{Memo: memo}
+	bts1, err := l.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 32; i++ {
+		bts2, err := l.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatal("hash of interface{} map not stable")
+		}
+	}
+
+	// values with a MarshalHash method are
+	// written as nested fields of their type
+	entry, err := (&Entry{Key: "k"}).MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(bts1, hsp.AppendBytes(hsp.AppendString(nil, "entry"), entry)) {
+		t.Fatal("interface{} value not written with MarshalHash")
+	}
+}
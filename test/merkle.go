@@ -0,0 +1,24 @@
+package covenant
+
+//go:generate hsp -unmarshal -stream -equal
+
+//hsp:digest blake2b Batch
+
+// Payment is small enough to be inlined
+// into its parents, the trees of its slices
+// hold its own MarshalHash output anyway.
+type Payment struct {
+	From   string `hsp:"0"`
+	To     string `hsp:"1"`
+	Amount uint64 `hsp:"2"`
+}
+
+type PaymentBlock struct {
+	Height   uint64    `hsp:"0"`
+	Payments []Payment `hsp:"1,merkle"`
+}
+
+type Batch struct {
+	Payments []Payment `hsp:"0,merkle"`
+	Fees     []Payment `hsp:"1"`
+}
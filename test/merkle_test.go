@@ -0,0 +1,89 @@
+package covenant
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+func testPayments(n int) []Payment {
+	ps := make([]Payment, n)
+	for i := range ps {
+		ps[i] = Payment{From: "alice", To: "bob", Amount: uint64(i)}
+	}
+	return ps
+}
+
+func TestMerkleField(t *testing.T) {
+	block := PaymentBlock{Height: 7, Payments: testPayments(5)}
+	var leaves [][32]byte
+	for i := range block.Payments {
+		bts, err := block.Payments[i].MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaves = append(leaves, hsp.MerkleLeaf(sha256.Sum256, bts))
+	}
+	root := hsp.MerkleRoot(sha256.Sum256, leaves)
+
+	// the root is written instead of the elements
+	want := hsp.AppendMapHeader(nil, 2)
+	want = hsp.AppendUint64(want, 7)
+	want = hsp.AppendBytes(want, root[:])
+	bts, err := block.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bts, want) {
+		t.Errorf("got %x, want %x", bts, want)
+	}
+	if got, err := block.PaymentsMerkleRoot(); err != nil || got != root {
+		t.Errorf("PaymentsMerkleRoot: got %x, %v, want %x", got, err, root)
+	}
+
+	for i := range block.Payments {
+		proof, err := block.PaymentsProof(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := VerifyPaymentBlockPaymentsProof(root, block.Payments[i], proof)
+		if err != nil || !ok {
+			t.Errorf("%d: valid proof rejected: %v", i, err)
+		}
+		forged := block.Payments[i]
+		forged.Amount += 100
+		if ok, _ = VerifyPaymentBlockPaymentsProof(root, forged, proof); ok {
+			t.Errorf("%d: proof of a forged element accepted", i)
+		}
+	}
+	if _, err = block.PaymentsProof(5); err != hsp.ErrMerkleIndex {
+		t.Errorf("got %v for an index out of range", err)
+	}
+
+	// the elements are not decoded
+	var decoded PaymentBlock
+	if _, err = decoded.UnmarshalHash(bts); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Height != 7 || decoded.Payments != nil {
+		t.Errorf("decoded %+v", decoded)
+	}
+}
+
+func TestMerkleFieldDigest(t *testing.T) {
+	// the tree uses the digest of Batch
+	batch := Batch{Payments: testPayments(3)}
+	var leaves [][32]byte
+	for i := range batch.Payments {
+		bts, _ := batch.Payments[i].MarshalHash()
+		leaves = append(leaves, hsp.MerkleLeaf(blake2b.Sum256, bts))
+	}
+	want := hsp.MerkleRoot(blake2b.Sum256, leaves)
+	if got, err := batch.PaymentsMerkleRoot(); err != nil || got != want {
+		t.Errorf("got %x, %v, want %x", got, err, want)
+	}
+}
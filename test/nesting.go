@@ -0,0 +1,26 @@
+package covenant
+
+//go:generate hsp -unmarshal -stream -equal
+
+//hsp:nesting inline
+
+type TxHeader struct {
+	Nonce     uint64 `hsp:"0"`
+	Sender    string `hsp:"1"`
+	Recipient string `hsp:"2"`
+	Amount    uint64 `hsp:"3"`
+}
+
+type Tx struct {
+	Header    TxHeader `hsp:"0"`
+	Signature []byte   `hsp:"1"`
+	Fee       uint64   `hsp:"2"`
+	Memo      string   `hsp:"3"`
+}
+
+type Block struct {
+	Producer string    `hsp:"0"`
+	Txs      []Tx      `hsp:"1"`
+	Last     *Tx       `hsp:"2"`
+	Parent   *TxHeader `hsp:"3"`
+}
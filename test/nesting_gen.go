@@ -0,0 +1,422 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"bytes"
+	"io"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// MarshalHash marshals for hash
+func (z *Block) MarshalHash() (o []byte, err error) {
+	var b []byte
+	return z.AppendHash(hsp.Require(b, z.Msgsize()))
+}
+
+// AppendHash appends the hash encoding to b
+func (z *Block) AppendHash(b []byte) (o []byte, err error) {
+	o = b
+	// map header, size 4
+	o = append(o, 0x84)
+	o = hsp.AppendString(o, z.Producer)
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Txs)))
+	for za0001 := range z.Txs {
+		if o, err = z.Txs[za0001].AppendHash(o); err != nil {
+			return
+		}
+	}
+	if z.Last == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		if o, err = z.Last.AppendHash(o); err != nil {
+			return
+		}
+	}
+	if z.Parent == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		if o, err = z.Parent.AppendHash(o); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Block) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 4 {
+		err = hsp.ArrayError{Wanted: 4, Got: zb0001}
+		return
+	}
+	z.Producer, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	var zb0002 uint32
+	zb0002, bts, err = hsp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Txs) >= int(zb0002) {
+		z.Txs = (z.Txs)[:zb0002]
+	} else {
+		z.Txs = make([]Tx, zb0002)
+	}
+	for za0001 := range z.Txs {
+		bts, err = z.Txs[za0001].UnmarshalHash(bts)
+		if err != nil {
+			return
+		}
+	}
+	if hsp.IsNil(bts) {
+		bts, err = hsp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Last = nil
+	} else {
+		if z.Last == nil {
+			z.Last = new(Tx)
+		}
+		bts, err = z.Last.UnmarshalHash(bts)
+		if err != nil {
+			return
+		}
+	}
+	if hsp.IsNil(bts) {
+		bts, err = hsp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Parent = nil
+	} else {
+		if z.Parent == nil {
+			z.Parent = new(TxHeader)
+		}
+		bts, err = z.Parent.UnmarshalHash(bts)
+		if err != nil {
+			return
+		}
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *Block) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 4
+	err = en.Append(0x84)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Producer)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Txs)))
+	if err != nil {
+		return
+	}
+	for za0001 := range z.Txs {
+		err = z.Txs[za0001].WriteHash(en)
+		if err != nil {
+			return
+		}
+	}
+	if z.Last == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Last.WriteHash(en)
+		if err != nil {
+			return
+		}
+	}
+	if z.Parent == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		err = z.Parent.WriteHash(en)
+		if err != nil {
+			return
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Block) EqualHash(other *Block) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.Producer != other.Producer {
+		return false
+	}
+	if len(z.Txs) != len(other.Txs) {
+		return false
+	}
+	for za0001 := range z.Txs {
+		if !z.Txs[za0001].EqualHash(&other.Txs[za0001]) {
+			return false
+		}
+	}
+	if (z.Last == nil) != (other.Last == nil) {
+		return false
+	}
+	if z.Last != nil {
+		if !z.Last.EqualHash(other.Last) {
+			return false
+		}
+	}
+	if (z.Parent == nil) != (other.Parent == nil) {
+		return false
+	}
+	if z.Parent != nil {
+		if !z.Parent.EqualHash(other.Parent) {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Block) Msgsize() (s int) {
+	s = 1 + 2 + hsp.StringPrefixSize + len(z.Producer) + 2 + hsp.ArrayHeaderSize
+	for za0001 := range z.Txs {
+		s += z.Txs[za0001].Msgsize()
+	}
+	s += 2
+	if z.Last == nil {
+		s += hsp.NilSize
+	} else {
+		s += z.Last.Msgsize()
+	}
+	s += 2
+	if z.Parent == nil {
+		s += hsp.NilSize
+	} else {
+		s += z.Parent.Msgsize()
+	}
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *Tx) MarshalHash() (o []byte, err error) {
+	var b []byte
+	return z.AppendHash(hsp.Require(b, z.Msgsize()))
+}
+
+// AppendHash appends the hash encoding to b
+func (z *Tx) AppendHash(b []byte) (o []byte, err error) {
+	o = b
+	// map header, size 4
+	o = append(o, 0x84)
+	if o, err = z.Header.AppendHash(o); err != nil {
+		return
+	}
+	o = hsp.AppendBytes(o, z.Signature)
+	o = hsp.AppendUint64(o, z.Fee)
+	o = hsp.AppendString(o, z.Memo)
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Tx) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 4 {
+		err = hsp.ArrayError{Wanted: 4, Got: zb0001}
+		return
+	}
+	bts, err = z.Header.UnmarshalHash(bts)
+	if err != nil {
+		return
+	}
+	z.Signature, bts, err = hsp.ReadBytesBytes(bts, z.Signature)
+	if err != nil {
+		return
+	}
+	z.Fee, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Memo, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *Tx) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 4
+	err = en.Append(0x84)
+	if err != nil {
+		return
+	}
+	err = z.Header.WriteHash(en)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.Signature)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.Fee)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Memo)
+	if err != nil {
+		return
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Tx) EqualHash(other *Tx) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if !z.Header.EqualHash(&other.Header) {
+		return false
+	}
+	if !bytes.Equal(z.Signature, other.Signature) {
+		return false
+	}
+	if z.Fee != other.Fee {
+		return false
+	}
+	if z.Memo != other.Memo {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Tx) Msgsize() (s int) {
+	s = 1 + 2 + z.Header.Msgsize() + 2 + hsp.BytesPrefixSize + len(z.Signature) + 2 + hsp.Uint64Size + 2 + hsp.StringPrefixSize + len(z.Memo)
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *TxHeader) MarshalHash() (o []byte, err error) {
+	var b []byte
+	return z.AppendHash(hsp.Require(b, z.Msgsize()))
+}
+
+// AppendHash appends the hash encoding to b
+func (z *TxHeader) AppendHash(b []byte) (o []byte, err error) {
+	o = b
+	// map header, size 4
+	o = append(o, 0x84)
+	o = hsp.AppendUint64(o, z.Nonce)
+	o = hsp.AppendString(o, z.Sender)
+	o = hsp.AppendString(o, z.Recipient)
+	o = hsp.AppendUint64(o, z.Amount)
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *TxHeader) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 4 {
+		err = hsp.ArrayError{Wanted: 4, Got: zb0001}
+		return
+	}
+	z.Nonce, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Sender, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Recipient, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Amount, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *TxHeader) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 4
+	err = en.Append(0x84)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.Nonce)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Sender)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Recipient)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.Amount)
+	if err != nil {
+		return
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *TxHeader) EqualHash(other *TxHeader) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.Nonce != other.Nonce {
+		return false
+	}
+	if z.Sender != other.Sender {
+		return false
+	}
+	if z.Recipient != other.Recipient {
+		return false
+	}
+	if z.Amount != other.Amount {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *TxHeader) Msgsize() (s int) {
+	s = 1 + 2 + hsp.Uint64Size + 2 + hsp.StringPrefixSize + len(z.Sender) + 2 + hsp.StringPrefixSize + len(z.Recipient) + 2 + hsp.Uint64Size
+	return
+}
@@ -0,0 +1,79 @@
+package covenant
+
+import (
+	"bytes"
+	"testing"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+func TestAppendHashInlineNesting(t *testing.T) {
+	tx := Tx{
+		Header:    TxHeader{Nonce: 1, Sender: "alice", Recipient: "bob", Amount: 100},
+		Signature: []byte{0x01, 0x02},
+		Fee:       3,
+	}
+	blk := Block{Producer: "node", Txs: []Tx{tx, tx}, Last: &tx}
+
+	txBts, err := tx.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// nested values are appended in place,
+	// without a 'bin' header
+	want := hsp.AppendMapHeader(nil, 4)
+	want = hsp.AppendString(want, "node")
+	want = hsp.AppendArrayHeader(want, 2)
+	want = append(want, txBts...)
+	want = append(want, txBts...)
+	want = append(want, txBts...)
+	want = hsp.AppendNil(want)
+
+	bts, err := blk.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bts, want) {
+		t.Fatalf("got %x, want %x", bts, want)
+	}
+
+	// AppendHash keeps the existing prefix
+	prefix := []byte{0xde, 0xad}
+	appended, err := blk.AppendHash(prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(appended, append(prefix, want...)) {
+		t.Fatalf("got %x, want prefix and %x", appended, want)
+	}
+
+	var decoded Block
+	left, err := decoded.UnmarshalHash(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 0 {
+		t.Fatalf("%d bytes left over after UnmarshalHash()", len(left))
+	}
+	if decoded.Last == nil || decoded.Last.Header.Sender != "alice" || len(decoded.Txs) != 2 {
+		t.Fatalf("decoded %+v", decoded)
+	}
+}
+
+func BenchmarkAppendHashInlineNesting(b *testing.B) {
+	tx := Tx{
+		Header:    TxHeader{Nonce: 1, Sender: "alice", Recipient: "bob", Amount: 100},
+		Signature: make([]byte, 64),
+	}
+	blk := Block{Producer: "node", Txs: make([]Tx, 1000)}
+	for i := range blk.Txs {
+		blk.Txs[i] = tx
+	}
+	buf := make([]byte, 0, blk.Msgsize())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, _ = blk.AppendHash(buf[:0])
+	}
+}
@@ -0,0 +1,45 @@
+package covenant
+
+import "time"
+
+//go:generate hsp -unmarshal -stream -equal
+
+//hsp:tuple TupleOrder
+
+// Order is the first version of OrderV2
+type Order struct {
+	ID     uint64 `hsp:"0"`
+	Amount int64  `hsp:"1"`
+	Seller string `hsp:"2"`
+}
+
+// OrderV2 adds optional fields to Order
+// without changing its hash when they are empty
+type OrderV2 struct {
+	ID       uint64            `hsp:"0"`
+	Amount   int64             `hsp:"1"`
+	Note     string            `hsp:"10,omitempty"`
+	Seller   string            `hsp:"2"`
+	Price    float64           `hsp:"3,omitempty"`
+	Expires  time.Time         `hsp:"4,omitempty"`
+	Buyer    *string           `hsp:"5,omitempty"`
+	Tags     []string          `hsp:"6,omitempty"`
+	Extra    map[string]uint32 `hsp:"7,omitempty"`
+	Payload  []byte            `hsp:"8,omitempty"`
+	Urgent   bool              `hsp:"9,omitempty"`
+	Metadata interface{}       `hsp:"91,omitempty"`
+}
+
+// TupleOrder is OrderV2 written as an array
+type TupleOrder struct {
+	ID   uint64 `hsp:"0"`
+	Note string `hsp:"1,omitempty"`
+	Size uint32 `hsp:"2,omitempty"`
+}
+
+// Sparse interleaves plain and omitempty fields
+type Sparse struct {
+	O1 string `hsp:"1,omitempty"`
+	P  string `hsp:"2"`
+	O3 string `hsp:"3,omitempty"`
+}
@@ -0,0 +1,1029 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// MarshalHash marshals for hash
+func (z Order) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 3
+	o = append(o, 0x83)
+	o = hsp.AppendUint64(o, z.ID)
+	o = hsp.AppendInt64(o, z.Amount)
+	o = hsp.AppendString(o, z.Seller)
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Order) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 3 {
+		err = hsp.ArrayError{Wanted: 3, Got: zb0001}
+		return
+	}
+	z.ID, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Amount, bts, err = hsp.ReadInt64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Seller, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z Order) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 3
+	err = en.Append(0x83)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.ID)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.Amount)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Seller)
+	if err != nil {
+		return
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Order) EqualHash(other *Order) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.ID != other.ID {
+		return false
+	}
+	if z.Amount != other.Amount {
+		return false
+	}
+	if z.Seller != other.Seller {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z Order) Msgsize() (s int) {
+	s = 1 + 2 + hsp.Uint64Size + 2 + hsp.Int64Size + 2 + hsp.StringPrefixSize + len(z.Seller)
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *OrderV2) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	zb0001 := uint32(0)
+	if z.Note != "" {
+		zb0001++
+	}
+	if z.Price != 0 {
+		zb0001++
+	}
+	if !z.Expires.IsZero() {
+		zb0001++
+	}
+	if z.Buyer != nil {
+		zb0001++
+	}
+	if len(z.Tags) != 0 {
+		zb0001++
+	}
+	if len(z.Extra) != 0 {
+		zb0001++
+	}
+	if len(z.Payload) != 0 {
+		zb0001++
+	}
+	if z.Urgent {
+		zb0001++
+	}
+	if z.Metadata != nil {
+		zb0001++
+	}
+	zb0002 := uint32(3)
+	if zb0001 > 0 {
+		zb0002++
+	}
+	o = hsp.AppendMapHeader(o, zb0002)
+	o = hsp.AppendUint64(o, z.ID)
+	o = hsp.AppendInt64(o, z.Amount)
+	o = hsp.AppendString(o, z.Seller)
+	if zb0001 > 0 {
+		o = hsp.AppendMapHeader(o, zb0001)
+		if z.Note != "" {
+			o = hsp.AppendString(o, "10")
+			o = hsp.AppendString(o, z.Note)
+		}
+		if z.Price != 0 {
+			o = hsp.AppendString(o, "3")
+			o = hsp.AppendFloat64(o, z.Price)
+		}
+		if !z.Expires.IsZero() {
+			o = hsp.AppendString(o, "4")
+			o = hsp.AppendTime(o, z.Expires)
+		}
+		if z.Buyer != nil {
+			o = hsp.AppendString(o, "5")
+			if z.Buyer == nil {
+				o = hsp.AppendNil(o)
+			} else {
+				o = hsp.AppendString(o, *z.Buyer)
+			}
+		}
+		if len(z.Tags) != 0 {
+			o = hsp.AppendString(o, "6")
+			o = hsp.AppendArrayHeader(o, uint32(len(z.Tags)))
+			for za0001 := range z.Tags {
+				o = hsp.AppendString(o, z.Tags[za0001])
+			}
+		}
+		if len(z.Extra) != 0 {
+			o = hsp.AppendString(o, "7")
+			o = hsp.AppendMapHeader(o, uint32(len(z.Extra)))
+			za0002Slice := make([]string, 0, len(z.Extra))
+			for i := range z.Extra {
+				za0002Slice = append(za0002Slice, i)
+			}
+			sort.Strings(za0002Slice)
+			for _, za0002 := range za0002Slice {
+				za0003 := z.Extra[za0002]
+				o = hsp.AppendString(o, za0002)
+				o = hsp.AppendUint32(o, za0003)
+			}
+		}
+		if len(z.Payload) != 0 {
+			o = hsp.AppendString(o, "8")
+			o = hsp.AppendBytes(o, z.Payload)
+		}
+		if z.Urgent {
+			o = hsp.AppendString(o, "9")
+			o = hsp.AppendBool(o, z.Urgent)
+		}
+		if z.Metadata != nil {
+			o = hsp.AppendString(o, "91")
+			o, err = hsp.AppendIntfHash(o, z.Metadata)
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *OrderV2) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 < 3 || zb0001 > 3+1 {
+		err = hsp.ArrayError{Wanted: 3 + 1, Got: zb0001}
+		return
+	}
+	z.ID, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Amount, bts, err = hsp.ReadInt64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Note = ""
+	z.Seller, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Price = 0
+	z.Expires = time.Time{}
+	z.Buyer = nil
+	z.Tags = nil
+	z.Extra = nil
+	z.Payload = nil
+	z.Urgent = false
+	z.Metadata = nil
+	if zb0001 > 3 {
+		var zb0002 uint32
+		zb0002, bts, err = hsp.ReadMapHeaderBytes(bts)
+		if err != nil {
+			return
+		}
+		if zb0002 == 0 || zb0002 > 9 {
+			err = hsp.ArrayError{Wanted: 9, Got: zb0002}
+			return
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "10" {
+			bts = rest
+			zb0002--
+			z.Note, bts, err = hsp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "3" {
+			bts = rest
+			zb0002--
+			z.Price, bts, err = hsp.ReadFloat64Bytes(bts)
+			if err != nil {
+				return
+			}
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "4" {
+			bts = rest
+			zb0002--
+			if hsp.IsNil(bts) {
+				bts, err = hsp.ReadNilBytes(bts)
+				z.Expires = time.Time{}
+			} else {
+				z.Expires, bts, err = hsp.ReadTimeBytes(bts)
+			}
+			if err != nil {
+				return
+			}
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "5" {
+			bts = rest
+			zb0002--
+			if hsp.IsNil(bts) {
+				bts, err = hsp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Buyer = nil
+			} else {
+				if z.Buyer == nil {
+					z.Buyer = new(string)
+				}
+				*z.Buyer, bts, err = hsp.ReadStringBytes(bts)
+				if err != nil {
+					return
+				}
+			}
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "6" {
+			bts = rest
+			zb0002--
+			var zb0003 uint32
+			zb0003, bts, err = hsp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				return
+			}
+			if cap(z.Tags) >= int(zb0003) {
+				z.Tags = (z.Tags)[:zb0003]
+			} else {
+				z.Tags = make([]string, zb0003)
+			}
+			for za0001 := range z.Tags {
+				z.Tags[za0001], bts, err = hsp.ReadStringBytes(bts)
+				if err != nil {
+					return
+				}
+			}
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "7" {
+			bts = rest
+			zb0002--
+			var zb0004 uint32
+			zb0004, bts, err = hsp.ReadMapHeaderBytes(bts)
+			if err != nil {
+				return
+			}
+			if z.Extra == nil {
+				z.Extra = make(map[string]uint32, zb0004)
+			} else if len(z.Extra) > 0 {
+				for key := range z.Extra {
+					delete(z.Extra, key)
+				}
+			}
+			for zb0004 > 0 {
+				var za0002 string
+				var za0003 uint32
+				zb0004--
+				za0002, bts, err = hsp.ReadStringBytes(bts)
+				if err != nil {
+					return
+				}
+				za0003, bts, err = hsp.ReadUint32Bytes(bts)
+				if err != nil {
+					return
+				}
+				z.Extra[za0002] = za0003
+			}
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "8" {
+			bts = rest
+			zb0002--
+			z.Payload, bts, err = hsp.ReadBytesBytes(bts, z.Payload)
+			if err != nil {
+				return
+			}
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "9" {
+			bts = rest
+			zb0002--
+			z.Urgent, bts, err = hsp.ReadBoolBytes(bts)
+			if err != nil {
+				return
+			}
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "91" {
+			bts = rest
+			zb0002--
+			z.Metadata, bts, err = hsp.ReadIntfBytes(bts)
+			if err != nil {
+				return
+			}
+		}
+		if zb0002 != 0 {
+			err = hsp.ArrayError{Wanted: 0, Got: zb0002}
+			return
+		}
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *OrderV2) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	zb0001 := uint32(0)
+	if z.Note != "" {
+		zb0001++
+	}
+	if z.Price != 0 {
+		zb0001++
+	}
+	if !z.Expires.IsZero() {
+		zb0001++
+	}
+	if z.Buyer != nil {
+		zb0001++
+	}
+	if len(z.Tags) != 0 {
+		zb0001++
+	}
+	if len(z.Extra) != 0 {
+		zb0001++
+	}
+	if len(z.Payload) != 0 {
+		zb0001++
+	}
+	if z.Urgent {
+		zb0001++
+	}
+	if z.Metadata != nil {
+		zb0001++
+	}
+	zb0002 := uint32(3)
+	if zb0001 > 0 {
+		zb0002++
+	}
+	err = en.WriteMapHeader(zb0002)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.ID)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.Amount)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Seller)
+	if err != nil {
+		return
+	}
+	if zb0001 > 0 {
+		err = en.WriteMapHeader(zb0001)
+		if err != nil {
+			return
+		}
+		if z.Note != "" {
+			err = en.WriteString("10")
+			if err != nil {
+				return
+			}
+			err = en.WriteString(z.Note)
+			if err != nil {
+				return
+			}
+		}
+		if z.Price != 0 {
+			err = en.WriteString("3")
+			if err != nil {
+				return
+			}
+			err = en.WriteFloat64(z.Price)
+			if err != nil {
+				return
+			}
+		}
+		if !z.Expires.IsZero() {
+			err = en.WriteString("4")
+			if err != nil {
+				return
+			}
+			if (z.Expires).IsZero() {
+				err = en.WriteNil()
+			} else {
+				err = en.WriteTime(z.Expires)
+			}
+			if err != nil {
+				return
+			}
+		}
+		if z.Buyer != nil {
+			err = en.WriteString("5")
+			if err != nil {
+				return
+			}
+			if z.Buyer == nil {
+				err = en.WriteNil()
+				if err != nil {
+					return
+				}
+			} else {
+				err = en.WriteString(*z.Buyer)
+				if err != nil {
+					return
+				}
+			}
+		}
+		if len(z.Tags) != 0 {
+			err = en.WriteString("6")
+			if err != nil {
+				return
+			}
+			err = en.WriteArrayHeader(uint32(len(z.Tags)))
+			if err != nil {
+				return
+			}
+			for za0001 := range z.Tags {
+				err = en.WriteString(z.Tags[za0001])
+				if err != nil {
+					return
+				}
+			}
+		}
+		if len(z.Extra) != 0 {
+			err = en.WriteString("7")
+			if err != nil {
+				return
+			}
+			err = en.WriteMapHeader(uint32(len(z.Extra)))
+			if err != nil {
+				return
+			}
+			za0002Slice := make([]string, 0, len(z.Extra))
+			for i := range z.Extra {
+				za0002Slice = append(za0002Slice, i)
+			}
+			sort.Strings(za0002Slice)
+			for _, za0002 := range za0002Slice {
+				za0003 := z.Extra[za0002]
+				err = en.WriteString(za0002)
+				if err != nil {
+					return
+				}
+				err = en.WriteUint32(za0003)
+				if err != nil {
+					return
+				}
+			}
+		}
+		if len(z.Payload) != 0 {
+			err = en.WriteString("8")
+			if err != nil {
+				return
+			}
+			err = en.WriteBytes(z.Payload)
+			if err != nil {
+				return
+			}
+		}
+		if z.Urgent {
+			err = en.WriteString("9")
+			if err != nil {
+				return
+			}
+			err = en.WriteBool(z.Urgent)
+			if err != nil {
+				return
+			}
+		}
+		if z.Metadata != nil {
+			err = en.WriteString("91")
+			if err != nil {
+				return
+			}
+			if oTemp, err := hsp.AppendIntfHash(nil, z.Metadata); err != nil {
+				return err
+			} else {
+				_, err = en.Write(oTemp)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *OrderV2) EqualHash(other *OrderV2) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.ID != other.ID {
+		return false
+	}
+	if z.Amount != other.Amount {
+		return false
+	}
+	if (z.Note != "") != (other.Note != "") {
+		return false
+	}
+	if z.Note != "" {
+		if z.Note != other.Note {
+			return false
+		}
+	}
+	if z.Seller != other.Seller {
+		return false
+	}
+	if (z.Price != 0) != (other.Price != 0) {
+		return false
+	}
+	if z.Price != 0 {
+		if math.Float64bits(z.Price) != math.Float64bits(other.Price) {
+			return false
+		}
+	}
+	if (!z.Expires.IsZero()) != (!other.Expires.IsZero()) {
+		return false
+	}
+	if !z.Expires.IsZero() {
+		if !(z.Expires).Equal(other.Expires) {
+			return false
+		}
+	}
+	if (z.Buyer != nil) != (other.Buyer != nil) {
+		return false
+	}
+	if z.Buyer != nil {
+		if (z.Buyer == nil) != (other.Buyer == nil) {
+			return false
+		}
+		if z.Buyer != nil {
+			if *z.Buyer != *other.Buyer {
+				return false
+			}
+		}
+	}
+	if (len(z.Tags) != 0) != (len(other.Tags) != 0) {
+		return false
+	}
+	if len(z.Tags) != 0 {
+		if len(z.Tags) != len(other.Tags) {
+			return false
+		}
+		for za0001 := range z.Tags {
+			if z.Tags[za0001] != other.Tags[za0001] {
+				return false
+			}
+		}
+	}
+	if (len(z.Extra) != 0) != (len(other.Extra) != 0) {
+		return false
+	}
+	if len(z.Extra) != 0 {
+		if len(z.Extra) != len(other.Extra) {
+			return false
+		}
+		for za0002, za0003 := range z.Extra {
+			zb0001, ok := other.Extra[za0002]
+			if !ok {
+				return false
+			}
+			if za0003 != zb0001 {
+				return false
+			}
+		}
+	}
+	if (len(z.Payload) != 0) != (len(other.Payload) != 0) {
+		return false
+	}
+	if len(z.Payload) != 0 {
+		if !bytes.Equal(z.Payload, other.Payload) {
+			return false
+		}
+	}
+	if (z.Urgent) != (other.Urgent) {
+		return false
+	}
+	if z.Urgent {
+		if z.Urgent != other.Urgent {
+			return false
+		}
+	}
+	if (z.Metadata != nil) != (other.Metadata != nil) {
+		return false
+	}
+	if z.Metadata != nil {
+		if !hsp.EqualIntf(z.Metadata, other.Metadata) {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *OrderV2) Msgsize() (s int) {
+	s = hsp.MapHeaderSize + 1 + 2 + hsp.Uint64Size + 2 + hsp.Int64Size + 3 + hsp.StringPrefixSize + len(z.Note) + 2 + hsp.StringPrefixSize + len(z.Seller) + 2 + hsp.Float64Size + 2 + hsp.TimeSize + 2
+	if z.Buyer == nil {
+		s += hsp.NilSize
+	} else {
+		s += hsp.StringPrefixSize + len(*z.Buyer)
+	}
+	s += 2 + hsp.ArrayHeaderSize
+	for za0001 := range z.Tags {
+		s += hsp.StringPrefixSize + len(z.Tags[za0001])
+	}
+	s += 2 + hsp.MapHeaderSize
+	if z.Extra != nil {
+		for za0002, za0003 := range z.Extra {
+			_ = za0003
+			s += hsp.StringPrefixSize + len(za0002) + hsp.Uint32Size
+		}
+	}
+	s += 2 + hsp.BytesPrefixSize + len(z.Payload) + 2 + hsp.BoolSize + 3 + hsp.GuessSize(z.Metadata)
+	return
+}
+
+// MarshalHash marshals for hash
+func (z Sparse) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	zb0001 := uint32(0)
+	if z.O1 != "" {
+		zb0001++
+	}
+	if z.O3 != "" {
+		zb0001++
+	}
+	zb0002 := uint32(1)
+	if zb0001 > 0 {
+		zb0002++
+	}
+	o = hsp.AppendMapHeader(o, zb0002)
+	o = hsp.AppendString(o, z.P)
+	if zb0001 > 0 {
+		o = hsp.AppendMapHeader(o, zb0001)
+		if z.O1 != "" {
+			o = hsp.AppendString(o, "1")
+			o = hsp.AppendString(o, z.O1)
+		}
+		if z.O3 != "" {
+			o = hsp.AppendString(o, "3")
+			o = hsp.AppendString(o, z.O3)
+		}
+	}
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Sparse) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 < 1 || zb0001 > 1+1 {
+		err = hsp.ArrayError{Wanted: 1 + 1, Got: zb0001}
+		return
+	}
+	z.O1 = ""
+	z.P, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	z.O3 = ""
+	if zb0001 > 1 {
+		var zb0002 uint32
+		zb0002, bts, err = hsp.ReadMapHeaderBytes(bts)
+		if err != nil {
+			return
+		}
+		if zb0002 == 0 || zb0002 > 2 {
+			err = hsp.ArrayError{Wanted: 2, Got: zb0002}
+			return
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "1" {
+			bts = rest
+			zb0002--
+			z.O1, bts, err = hsp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "3" {
+			bts = rest
+			zb0002--
+			z.O3, bts, err = hsp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		}
+		if zb0002 != 0 {
+			err = hsp.ArrayError{Wanted: 0, Got: zb0002}
+			return
+		}
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z Sparse) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	zb0001 := uint32(0)
+	if z.O1 != "" {
+		zb0001++
+	}
+	if z.O3 != "" {
+		zb0001++
+	}
+	zb0002 := uint32(1)
+	if zb0001 > 0 {
+		zb0002++
+	}
+	err = en.WriteMapHeader(zb0002)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.P)
+	if err != nil {
+		return
+	}
+	if zb0001 > 0 {
+		err = en.WriteMapHeader(zb0001)
+		if err != nil {
+			return
+		}
+		if z.O1 != "" {
+			err = en.WriteString("1")
+			if err != nil {
+				return
+			}
+			err = en.WriteString(z.O1)
+			if err != nil {
+				return
+			}
+		}
+		if z.O3 != "" {
+			err = en.WriteString("3")
+			if err != nil {
+				return
+			}
+			err = en.WriteString(z.O3)
+			if err != nil {
+				return
+			}
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Sparse) EqualHash(other *Sparse) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if (z.O1 != "") != (other.O1 != "") {
+		return false
+	}
+	if z.O1 != "" {
+		if z.O1 != other.O1 {
+			return false
+		}
+	}
+	if z.P != other.P {
+		return false
+	}
+	if (z.O3 != "") != (other.O3 != "") {
+		return false
+	}
+	if z.O3 != "" {
+		if z.O3 != other.O3 {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z Sparse) Msgsize() (s int) {
+	s = hsp.MapHeaderSize + 1 + 2 + hsp.StringPrefixSize + len(z.O1) + 2 + hsp.StringPrefixSize + len(z.P) + 2 + hsp.StringPrefixSize + len(z.O3)
+	return
+}
+
+// MarshalHash marshals for hash
+func (z TupleOrder) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	zb0001 := uint32(0)
+	if z.Note != "" {
+		zb0001++
+	}
+	if z.Size != 0 {
+		zb0001++
+	}
+	zb0002 := uint32(1)
+	if zb0001 > 0 {
+		zb0002++
+	}
+	o = hsp.AppendArrayHeader(o, zb0002)
+	o = hsp.AppendUint64(o, z.ID)
+	if zb0001 > 0 {
+		o = hsp.AppendMapHeader(o, zb0001)
+		if z.Note != "" {
+			o = hsp.AppendString(o, "1")
+			o = hsp.AppendString(o, z.Note)
+		}
+		if z.Size != 0 {
+			o = hsp.AppendString(o, "2")
+			o = hsp.AppendUint32(o, z.Size)
+		}
+	}
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *TupleOrder) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 < 1 || zb0001 > 1+1 {
+		err = hsp.ArrayError{Wanted: 1 + 1, Got: zb0001}
+		return
+	}
+	z.ID, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Note = ""
+	z.Size = 0
+	if zb0001 > 1 {
+		var zb0002 uint32
+		zb0002, bts, err = hsp.ReadMapHeaderBytes(bts)
+		if err != nil {
+			return
+		}
+		if zb0002 == 0 || zb0002 > 2 {
+			err = hsp.ArrayError{Wanted: 2, Got: zb0002}
+			return
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "1" {
+			bts = rest
+			zb0002--
+			z.Note, bts, err = hsp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+		}
+		if tag, rest, terr := hsp.ReadStringZC(bts); zb0002 > 0 && terr == nil && string(tag) == "2" {
+			bts = rest
+			zb0002--
+			z.Size, bts, err = hsp.ReadUint32Bytes(bts)
+			if err != nil {
+				return
+			}
+		}
+		if zb0002 != 0 {
+			err = hsp.ArrayError{Wanted: 0, Got: zb0002}
+			return
+		}
+	}
+	o = bts
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z TupleOrder) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	zb0001 := uint32(0)
+	if z.Note != "" {
+		zb0001++
+	}
+	if z.Size != 0 {
+		zb0001++
+	}
+	zb0002 := uint32(1)
+	if zb0001 > 0 {
+		zb0002++
+	}
+	err = en.WriteArrayHeader(zb0002)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.ID)
+	if err != nil {
+		return
+	}
+	if zb0001 > 0 {
+		err = en.WriteMapHeader(zb0001)
+		if err != nil {
+			return
+		}
+		if z.Note != "" {
+			err = en.WriteString("1")
+			if err != nil {
+				return
+			}
+			err = en.WriteString(z.Note)
+			if err != nil {
+				return
+			}
+		}
+		if z.Size != 0 {
+			err = en.WriteString("2")
+			if err != nil {
+				return
+			}
+			err = en.WriteUint32(z.Size)
+			if err != nil {
+				return
+			}
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *TupleOrder) EqualHash(other *TupleOrder) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.ID != other.ID {
+		return false
+	}
+	if (z.Note != "") != (other.Note != "") {
+		return false
+	}
+	if z.Note != "" {
+		if z.Note != other.Note {
+			return false
+		}
+	}
+	if (z.Size != 0) != (other.Size != 0) {
+		return false
+	}
+	if z.Size != 0 {
+		if z.Size != other.Size {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z TupleOrder) Msgsize() (s int) {
+	s = hsp.MapHeaderSize + 1 + hsp.Uint64Size + 2 + hsp.StringPrefixSize + len(z.Note) + 2 + hsp.Uint32Size
+	return
+}
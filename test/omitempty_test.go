@@ -0,0 +1,142 @@
+package covenant
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestOmitEmptyKeepsHash(t *testing.T) {
+	o1 := Order{ID: 42, Amount: -7, Seller: "alice"}
+	o2 := OrderV2{ID: 42, Amount: -7, Seller: "alice", Tags: []string{}, Extra: map[string]uint32{}}
+	bts1, err := o1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bts2, err := o2.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bts1, bts2) {
+		t.Fatalf("empty omitempty fields changed the hash:\n%x\n%x", bts1, bts2)
+	}
+
+	o2.Price = 9.5
+	if bts2, err = o2.MarshalHash(); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(bts1, bts2) {
+		t.Fatal("a set omitempty field should change the hash")
+	}
+}
+
+func TestOmitEmptyRoundTrip(t *testing.T) {
+	buyer := "bob"
+	for _, o1 := range []OrderV2{
+		{ID: 1, Seller: "alice"},
+		{ID: 2, Note: "2", Seller: "3", Urgent: true},
+		{
+			ID: 3, Amount: 10, Note: "rush", Seller: "alice", Price: 1.25,
+			Expires: time.Unix(1540000000, 0), Buyer: &buyer, Tags: []string{"a", "b"},
+			Extra: map[string]uint32{"x": 1}, Payload: []byte{0x01}, Urgent: true, Metadata: "meta",
+		},
+	} {
+		bts1, err := o1.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var o2 OrderV2
+		left, err := o2.UnmarshalHash(bts1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(left) > 0 {
+			t.Fatalf("%d bytes left over after UnmarshalHash()", len(left))
+		}
+		if !o1.EqualHash(&o2) {
+			t.Fatalf("decoded %+v, want %+v", o2, o1)
+		}
+		bts2, err := o2.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bts1, bts2) {
+			t.Fatal("hash not stable after round trip")
+		}
+	}
+
+	// an Order decodes as an OrderV2
+	bts, err := (&Order{ID: 4, Seller: "carol"}).MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var o OrderV2
+	if _, err = o.UnmarshalHash(bts); err != nil {
+		t.Fatal(err)
+	}
+	if o.ID != 4 || o.Seller != "carol" || o.Note != "" {
+		t.Fatalf("decoded %+v", o)
+	}
+}
+
+func TestOmitEmptyEqualHash(t *testing.T) {
+	// -0 is empty, as 0 is
+	o1 := OrderV2{Price: math.Copysign(0, -1)}
+	o2 := OrderV2{}
+	if !o1.EqualHash(&o2) {
+		t.Error("-0 and 0 should be equal when omitted")
+	}
+	o2.Urgent = true
+	if o1.EqualHash(&o2) {
+		t.Error("set and empty fields should differ")
+	}
+}
+
+func TestOmitEmptyTuple(t *testing.T) {
+	t1 := TupleOrder{ID: 9, Size: 3}
+	bts, err := t1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bts) > t1.Msgsize() {
+		t.Fatalf("Msgsize() = %d, encoded %d bytes", t1.Msgsize(), len(bts))
+	}
+	var t2 TupleOrder
+	if _, err = t2.UnmarshalHash(bts); err != nil {
+		t.Fatal(err)
+	}
+	if t2 != t1 {
+		t.Fatalf("decoded %+v, want %+v", t2, t1)
+	}
+}
+
+func TestOmitEmptyPrefixFree(t *testing.T) {
+	// a set field must not be read as the tag of another
+	s1 := Sparse{P: "1", O3: "v"}
+	s2 := Sparse{O1: "3", P: "v"}
+	bts1, err := s1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bts2, err := s2.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(bts1, bts2) {
+		t.Fatalf("%+v and %+v both marshal to %x", s1, s2, bts1)
+	}
+	for i, bts := range [][]byte{bts1, bts2} {
+		var s Sparse
+		left, err := s.UnmarshalHash(bts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(left) > 0 {
+			t.Fatalf("%d bytes left over after UnmarshalHash()", len(left))
+		}
+		if want := []Sparse{s1, s2}[i]; s != want {
+			t.Errorf("decoded %+v, want %+v", s, want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package covenant
+
+import (
+	"time"
+)
+
+//go:generate hsp -unmarshal -equal
+
+type Level uint16
+type Blob []byte
+type Name string
+
+type Entry struct {
+	Key   string  `hsp:"k"`
+	Value []byte  `hsp:"v"`
+	Score float64 `hsp:"s"`
+}
+
+type Ledger struct {
+	Height    uint64            `hsp:"01"`
+	Name      string            `hsp:"00"`
+	Level     Level             `hsp:"02"`
+	Digest    [32]byte          `hsp:"03"`
+	Entries   []Entry           `hsp:"04"`
+	Latest    *Entry            `hsp:"05"`
+	Index     map[string]*Level `hsp:"06"`
+	Payload   Blob              `hsp:"07"`
+	Timestamp time.Time         `hsp:"08"`
+	Memo      interface{}       `hsp:"09"`
+	Heights   map[int64]Name    `hsp:"10"`
+	Owners    map[Name]uint32   `hsp:"11"`
+	Prefixes  map[[4]byte]Level `hsp:"12"`
+	Expires   *time.Time        `hsp:"13"`
+	Ignored   string            `hsp:"-"`
+}
@@ -0,0 +1,726 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"time"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// MarshalHash marshals for hash
+func (z Blob) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	o = hsp.AppendBytes(o, []byte(z))
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Blob) UnmarshalHash(bts []byte) (o []byte, err error) {
+	{
+		var zb0001 []byte
+		zb0001, bts, err = hsp.ReadBytesBytes(bts, []byte((*z)))
+		if err != nil {
+			return
+		}
+		(*z) = Blob(zb0001)
+	}
+	o = bts
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Blob) EqualHash(other *Blob) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if !bytes.Equal([]byte((*z)), []byte((*other))) {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z Blob) Msgsize() (s int) {
+	s = hsp.BytesPrefixSize + len([]byte(z))
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *Entry) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 3
+	o = append(o, 0x83)
+	o = hsp.AppendString(o, z.Key)
+	o = hsp.AppendFloat64(o, z.Score)
+	o = hsp.AppendBytes(o, z.Value)
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Entry) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 3 {
+		err = hsp.ArrayError{Wanted: 3, Got: zb0001}
+		return
+	}
+	z.Key, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Score, bts, err = hsp.ReadFloat64Bytes(bts)
+	if err != nil {
+		return
+	}
+	z.Value, bts, err = hsp.ReadBytesBytes(bts, z.Value)
+	if err != nil {
+		return
+	}
+	o = bts
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Entry) EqualHash(other *Entry) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.Key != other.Key {
+		return false
+	}
+	if math.Float64bits(z.Score) != math.Float64bits(other.Score) {
+		return false
+	}
+	if !bytes.Equal(z.Value, other.Value) {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Entry) Msgsize() (s int) {
+	s = 1 + 2 + hsp.StringPrefixSize + len(z.Key) + 2 + hsp.Float64Size + 2 + hsp.BytesPrefixSize + len(z.Value)
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *Ledger) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 14
+	o = append(o, 0x8e)
+	o = hsp.AppendString(o, z.Name)
+	o = hsp.AppendUint64(o, z.Height)
+	o = hsp.AppendUint16(o, uint16(z.Level))
+	o = hsp.AppendBytes(o, (z.Digest)[:])
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Entries)))
+	for za0002 := range z.Entries {
+		// map header, size 3
+		o = append(o, 0x83)
+		o = hsp.AppendString(o, z.Entries[za0002].Key)
+		o = hsp.AppendBytes(o, z.Entries[za0002].Value)
+		o = hsp.AppendFloat64(o, z.Entries[za0002].Score)
+	}
+	if z.Latest == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		// map header, size 3
+		o = append(o, 0x83)
+		o = hsp.AppendString(o, z.Latest.Key)
+		o = hsp.AppendBytes(o, z.Latest.Value)
+		o = hsp.AppendFloat64(o, z.Latest.Score)
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Index)))
+	za0003Slice := make([]string, 0, len(z.Index))
+	for i := range z.Index {
+		za0003Slice = append(za0003Slice, i)
+	}
+	sort.Strings(za0003Slice)
+	for _, za0003 := range za0003Slice {
+		za0004 := z.Index[za0003]
+		o = hsp.AppendString(o, za0003)
+		if za0004 == nil {
+			o = hsp.AppendNil(o)
+		} else {
+			o = hsp.AppendUint16(o, uint16(*za0004))
+		}
+	}
+	o = hsp.AppendBytes(o, []byte(z.Payload))
+	o = hsp.AppendTime(o, z.Timestamp)
+	o, err = hsp.AppendIntfHash(o, z.Memo)
+	if err != nil {
+		return
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Heights)))
+	za0005Slice := make([]int64, 0, len(z.Heights))
+	for i := range z.Heights {
+		za0005Slice = append(za0005Slice, i)
+	}
+	sort.Slice(za0005Slice, func(i, j int) bool { return za0005Slice[i] < za0005Slice[j] })
+	for _, za0005 := range za0005Slice {
+		za0006 := z.Heights[za0005]
+		o = hsp.AppendInt64(o, za0005)
+		o = hsp.AppendString(o, string(za0006))
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Owners)))
+	za0007Slice := make([]Name, 0, len(z.Owners))
+	for i := range z.Owners {
+		za0007Slice = append(za0007Slice, i)
+	}
+	sort.Slice(za0007Slice, func(i, j int) bool { return string(za0007Slice[i]) < string(za0007Slice[j]) })
+	for _, za0007 := range za0007Slice {
+		za0008 := z.Owners[za0007]
+		o = hsp.AppendString(o, string(za0007))
+		o = hsp.AppendUint32(o, za0008)
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Prefixes)))
+	za0009Slice := make([][4]byte, 0, len(z.Prefixes))
+	for i := range z.Prefixes {
+		za0009Slice = append(za0009Slice, i)
+	}
+	sort.Slice(za0009Slice, func(i, j int) bool { return bytes.Compare(za0009Slice[i][:], za0009Slice[j][:]) < 0 })
+	for _, za0009 := range za0009Slice {
+		za0010 := z.Prefixes[za0009]
+		o = hsp.AppendBytes(o, (za0009)[:])
+		o = hsp.AppendUint16(o, uint16(za0010))
+	}
+	if z.Expires == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		o = hsp.AppendTime(o, *z.Expires)
+	}
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Ledger) UnmarshalHash(bts []byte) (o []byte, err error) {
+	var zb0001 uint32
+	zb0001, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if zb0001 != 14 {
+		err = hsp.ArrayError{Wanted: 14, Got: zb0001}
+		return
+	}
+	z.Name, bts, err = hsp.ReadStringBytes(bts)
+	if err != nil {
+		return
+	}
+	z.Height, bts, err = hsp.ReadUint64Bytes(bts)
+	if err != nil {
+		return
+	}
+	{
+		var zb0002 uint16
+		zb0002, bts, err = hsp.ReadUint16Bytes(bts)
+		if err != nil {
+			return
+		}
+		z.Level = Level(zb0002)
+	}
+	bts, err = hsp.ReadExactBytes(bts, (z.Digest)[:])
+	if err != nil {
+		return
+	}
+	var zb0003 uint32
+	zb0003, bts, err = hsp.ReadArrayHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if cap(z.Entries) >= int(zb0003) {
+		z.Entries = (z.Entries)[:zb0003]
+	} else {
+		z.Entries = make([]Entry, zb0003)
+	}
+	for za0002 := range z.Entries {
+		var zb0004 uint32
+		zb0004, bts, err = hsp.ReadMapHeaderBytes(bts)
+		if err != nil {
+			return
+		}
+		if zb0004 != 3 {
+			err = hsp.ArrayError{Wanted: 3, Got: zb0004}
+			return
+		}
+		z.Entries[za0002].Key, bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Entries[za0002].Value, bts, err = hsp.ReadBytesBytes(bts, z.Entries[za0002].Value)
+		if err != nil {
+			return
+		}
+		z.Entries[za0002].Score, bts, err = hsp.ReadFloat64Bytes(bts)
+		if err != nil {
+			return
+		}
+	}
+	if hsp.IsNil(bts) {
+		bts, err = hsp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Latest = nil
+	} else {
+		if z.Latest == nil {
+			z.Latest = new(Entry)
+		}
+		var zb0005 uint32
+		zb0005, bts, err = hsp.ReadMapHeaderBytes(bts)
+		if err != nil {
+			return
+		}
+		if zb0005 != 3 {
+			err = hsp.ArrayError{Wanted: 3, Got: zb0005}
+			return
+		}
+		z.Latest.Key, bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Latest.Value, bts, err = hsp.ReadBytesBytes(bts, z.Latest.Value)
+		if err != nil {
+			return
+		}
+		z.Latest.Score, bts, err = hsp.ReadFloat64Bytes(bts)
+		if err != nil {
+			return
+		}
+	}
+	var zb0006 uint32
+	zb0006, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if z.Index == nil {
+		z.Index = make(map[string]*Level, zb0006)
+	} else if len(z.Index) > 0 {
+		for key := range z.Index {
+			delete(z.Index, key)
+		}
+	}
+	for zb0006 > 0 {
+		var za0003 string
+		var za0004 *Level
+		zb0006--
+		za0003, bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+		if hsp.IsNil(bts) {
+			bts, err = hsp.ReadNilBytes(bts)
+			if err != nil {
+				return
+			}
+			za0004 = nil
+		} else {
+			if za0004 == nil {
+				za0004 = new(Level)
+			}
+			{
+				var zb0007 uint16
+				zb0007, bts, err = hsp.ReadUint16Bytes(bts)
+				if err != nil {
+					return
+				}
+				*za0004 = Level(zb0007)
+			}
+		}
+		z.Index[za0003] = za0004
+	}
+	{
+		var zb0008 []byte
+		zb0008, bts, err = hsp.ReadBytesBytes(bts, []byte(z.Payload))
+		if err != nil {
+			return
+		}
+		z.Payload = Blob(zb0008)
+	}
+	if hsp.IsNil(bts) {
+		bts, err = hsp.ReadNilBytes(bts)
+		z.Timestamp = time.Time{}
+	} else {
+		z.Timestamp, bts, err = hsp.ReadTimeBytes(bts)
+	}
+	if err != nil {
+		return
+	}
+	z.Memo, bts, err = hsp.ReadIntfBytes(bts)
+	if err != nil {
+		return
+	}
+	var zb0009 uint32
+	zb0009, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if z.Heights == nil {
+		z.Heights = make(map[int64]Name, zb0009)
+	} else if len(z.Heights) > 0 {
+		for key := range z.Heights {
+			delete(z.Heights, key)
+		}
+	}
+	for zb0009 > 0 {
+		var za0005 int64
+		var za0006 Name
+		zb0009--
+		za0005, bts, err = hsp.ReadInt64Bytes(bts)
+		if err != nil {
+			return
+		}
+		{
+			var zb0010 string
+			zb0010, bts, err = hsp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+			za0006 = Name(zb0010)
+		}
+		z.Heights[za0005] = za0006
+	}
+	var zb0011 uint32
+	zb0011, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if z.Owners == nil {
+		z.Owners = make(map[Name]uint32, zb0011)
+	} else if len(z.Owners) > 0 {
+		for key := range z.Owners {
+			delete(z.Owners, key)
+		}
+	}
+	for zb0011 > 0 {
+		var za0007 Name
+		var za0008 uint32
+		zb0011--
+		{
+			var zb0012 string
+			zb0012, bts, err = hsp.ReadStringBytes(bts)
+			if err != nil {
+				return
+			}
+			za0007 = Name(zb0012)
+		}
+		za0008, bts, err = hsp.ReadUint32Bytes(bts)
+		if err != nil {
+			return
+		}
+		z.Owners[za0007] = za0008
+	}
+	var zb0013 uint32
+	zb0013, bts, err = hsp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return
+	}
+	if z.Prefixes == nil {
+		z.Prefixes = make(map[[4]byte]Level, zb0013)
+	} else if len(z.Prefixes) > 0 {
+		for key := range z.Prefixes {
+			delete(z.Prefixes, key)
+		}
+	}
+	for zb0013 > 0 {
+		var za0009 [4]byte
+		var za0010 Level
+		zb0013--
+		bts, err = hsp.ReadExactBytes(bts, (za0009)[:])
+		if err != nil {
+			return
+		}
+		{
+			var zb0014 uint16
+			zb0014, bts, err = hsp.ReadUint16Bytes(bts)
+			if err != nil {
+				return
+			}
+			za0010 = Level(zb0014)
+		}
+		z.Prefixes[za0009] = za0010
+	}
+	if hsp.IsNil(bts) {
+		bts, err = hsp.ReadNilBytes(bts)
+		if err != nil {
+			return
+		}
+		z.Expires = nil
+	} else {
+		if z.Expires == nil {
+			z.Expires = new(time.Time)
+		}
+		if hsp.IsNil(bts) {
+			bts, err = hsp.ReadNilBytes(bts)
+			*z.Expires = time.Time{}
+		} else {
+			*z.Expires, bts, err = hsp.ReadTimeBytes(bts)
+		}
+		if err != nil {
+			return
+		}
+	}
+	o = bts
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Ledger) EqualHash(other *Ledger) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.Name != other.Name {
+		return false
+	}
+	if z.Height != other.Height {
+		return false
+	}
+	if uint16(z.Level) != uint16(other.Level) {
+		return false
+	}
+	if z.Digest != other.Digest {
+		return false
+	}
+	if len(z.Entries) != len(other.Entries) {
+		return false
+	}
+	for za0002 := range z.Entries {
+		if z.Entries[za0002].Key != other.Entries[za0002].Key {
+			return false
+		}
+		if !bytes.Equal(z.Entries[za0002].Value, other.Entries[za0002].Value) {
+			return false
+		}
+		if math.Float64bits(z.Entries[za0002].Score) != math.Float64bits(other.Entries[za0002].Score) {
+			return false
+		}
+	}
+	if (z.Latest == nil) != (other.Latest == nil) {
+		return false
+	}
+	if z.Latest != nil {
+		if z.Latest.Key != other.Latest.Key {
+			return false
+		}
+		if !bytes.Equal(z.Latest.Value, other.Latest.Value) {
+			return false
+		}
+		if math.Float64bits(z.Latest.Score) != math.Float64bits(other.Latest.Score) {
+			return false
+		}
+	}
+	if len(z.Index) != len(other.Index) {
+		return false
+	}
+	for za0003, za0004 := range z.Index {
+		zb0001, ok := other.Index[za0003]
+		if !ok {
+			return false
+		}
+		if (za0004 == nil) != (zb0001 == nil) {
+			return false
+		}
+		if za0004 != nil {
+			if uint16(*za0004) != uint16(*zb0001) {
+				return false
+			}
+		}
+	}
+	if !bytes.Equal([]byte(z.Payload), []byte(other.Payload)) {
+		return false
+	}
+	if !(z.Timestamp).Equal(other.Timestamp) {
+		return false
+	}
+	if !hsp.EqualIntf(z.Memo, other.Memo) {
+		return false
+	}
+	if len(z.Heights) != len(other.Heights) {
+		return false
+	}
+	for za0005, za0006 := range z.Heights {
+		zb0002, ok := other.Heights[za0005]
+		if !ok {
+			return false
+		}
+		if string(za0006) != string(zb0002) {
+			return false
+		}
+	}
+	if len(z.Owners) != len(other.Owners) {
+		return false
+	}
+	for za0007, za0008 := range z.Owners {
+		zb0003, ok := other.Owners[za0007]
+		if !ok {
+			return false
+		}
+		if za0008 != zb0003 {
+			return false
+		}
+	}
+	if len(z.Prefixes) != len(other.Prefixes) {
+		return false
+	}
+	for za0009, za0010 := range z.Prefixes {
+		zb0004, ok := other.Prefixes[za0009]
+		if !ok {
+			return false
+		}
+		if uint16(za0010) != uint16(zb0004) {
+			return false
+		}
+	}
+	if (z.Expires == nil) != (other.Expires == nil) {
+		return false
+	}
+	if z.Expires != nil {
+		if !(*z.Expires).Equal(*other.Expires) {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Ledger) Msgsize() (s int) {
+	s = 1 + 3 + hsp.StringPrefixSize + len(z.Name) + 3 + hsp.Uint64Size + 3 + hsp.Uint16Size + 3 + hsp.ArrayHeaderSize + (int(32) * (hsp.ByteSize)) + 3 + hsp.ArrayHeaderSize
+	for za0002 := range z.Entries {
+		s += 1 + 2 + hsp.StringPrefixSize + len(z.Entries[za0002].Key) + 2 + hsp.BytesPrefixSize + len(z.Entries[za0002].Value) + 2 + hsp.Float64Size
+	}
+	s += 3
+	if z.Latest == nil {
+		s += hsp.NilSize
+	} else {
+		s += 1 + 2 + hsp.StringPrefixSize + len(z.Latest.Key) + 2 + hsp.BytesPrefixSize + len(z.Latest.Value) + 2 + hsp.Float64Size
+	}
+	s += 3 + hsp.MapHeaderSize
+	if z.Index != nil {
+		for za0003, za0004 := range z.Index {
+			_ = za0004
+			s += hsp.StringPrefixSize + len(za0003)
+			if za0004 == nil {
+				s += hsp.NilSize
+			} else {
+				s += hsp.Uint16Size
+			}
+		}
+	}
+	s += 3 + hsp.BytesPrefixSize + len([]byte(z.Payload)) + 3 + hsp.TimeSize + 3 + hsp.GuessSize(z.Memo) + 3 + hsp.MapHeaderSize
+	if z.Heights != nil {
+		for za0005, za0006 := range z.Heights {
+			_ = za0006
+			_ = za0005
+			s += hsp.Int64Size + hsp.StringPrefixSize + len(string(za0006))
+		}
+	}
+	s += 3 + hsp.MapHeaderSize
+	if z.Owners != nil {
+		for za0007, za0008 := range z.Owners {
+			_ = za0008
+			s += hsp.StringPrefixSize + len(string(za0007)) + hsp.Uint32Size
+		}
+	}
+	s += 3 + hsp.MapHeaderSize
+	if z.Prefixes != nil {
+		for za0009, za0010 := range z.Prefixes {
+			_ = za0010
+			_ = za0009
+			s += hsp.ArrayHeaderSize + (int(4) * (hsp.ByteSize)) + hsp.Uint16Size
+		}
+	}
+	s += 3
+	if z.Expires == nil {
+		s += hsp.NilSize
+	} else {
+		s += hsp.TimeSize
+	}
+	return
+}
+
+// MarshalHash marshals for hash
+func (z Level) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	o = hsp.AppendUint16(o, uint16(z))
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Level) UnmarshalHash(bts []byte) (o []byte, err error) {
+	{
+		var zb0001 uint16
+		zb0001, bts, err = hsp.ReadUint16Bytes(bts)
+		if err != nil {
+			return
+		}
+		(*z) = Level(zb0001)
+	}
+	o = bts
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Level) EqualHash(other *Level) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if uint16((*z)) != uint16((*other)) {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z Level) Msgsize() (s int) {
+	s = hsp.Uint16Size
+	return
+}
+
+// MarshalHash marshals for hash
+func (z Name) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	o = hsp.AppendString(o, string(z))
+	return
+}
+
+// UnmarshalHash unmarshals the output of MarshalHash
+func (z *Name) UnmarshalHash(bts []byte) (o []byte, err error) {
+	{
+		var zb0001 string
+		zb0001, bts, err = hsp.ReadStringBytes(bts)
+		if err != nil {
+			return
+		}
+		(*z) = Name(zb0001)
+	}
+	o = bts
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Name) EqualHash(other *Name) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if string((*z)) != string((*other)) {
+		return false
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z Name) Msgsize() (s int) {
+	s = hsp.StringPrefixSize + len(string(z))
+	return
+}
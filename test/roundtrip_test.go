@@ -0,0 +1,65 @@
+package covenant
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestUnmarshalHashRoundTrip(t *testing.T) {
+	lv := Level(7)
+	l1 := Ledger{
+		Height: 1024,
+		Name:   "genesis",
+		Level:  3,
+		Digest: [32]byte{0x01, 0x02, 0x03},
+		Entries: []Entry{
+			{Key: "a", Value: []byte{0x10}, Score: 1.5},
+			{Key: "b", Value: []byte{0x20, 0x21}, Score: -2},
+		},
+		Latest:    &Entry{Key: "b", Value: []byte{0x20, 0x21}, Score: -2},
+		Index:     map[string]*Level{"x": &lv, "y": nil, "z": &lv},
+		Payload:   Blob("payload"),
+		Timestamp: time.Unix(1540000000, 123456789),
+		Memo:      "memo",
+		Ignored:   "not hashed",
+	}
+	bts1, err := l1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var l2 Ledger
+	left, err := l2.UnmarshalHash(bts1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Fatalf("%d bytes left over after UnmarshalHash()", len(left))
+	}
+	if l2.Name != l1.Name || l2.Height != l1.Height || l2.Digest != l1.Digest {
+		t.Fatalf("decoded %+v, want %+v", l2, l1)
+	}
+	if !l2.Timestamp.Equal(l1.Timestamp) {
+		t.Fatalf("decoded time %s, want %s", l2.Timestamp, l1.Timestamp)
+	}
+	if l2.Index["y"] != nil || *l2.Index["z"] != lv {
+		t.Fatalf("decoded index %v", l2.Index)
+	}
+	if l2.Ignored != "" {
+		t.Fatal("ignored field should not be decoded")
+	}
+
+	bts2, err := l2.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bts1, bts2) {
+		t.Fatal("hash not stable after round trip")
+	}
+
+	// truncated input must fail
+	if _, err = l2.UnmarshalHash(bts1[:len(bts1)-1]); err == nil {
+		t.Fatal("expected error on truncated input")
+	}
+}
@@ -0,0 +1,108 @@
+package covenant
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CovenantSQL/HashStablePack/gen"
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+	"github.com/CovenantSQL/HashStablePack/parse"
+)
+
+// roundtripSchema returns the schema
+// written by 'hsp schema roundtrip.go'
+func roundtripSchema(t *testing.T) *hsp.FileSchema {
+	fs, err := parse.File("roundtrip.go", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &hsp.FileSchema{Package: fs.Package, Nesting: "bin", Types: make(map[string]*hsp.Schema)}
+	for name, el := range fs.Identities {
+		if s := gen.SchemaOf(el, fs.NestInline); s != nil {
+			out.Types[name] = s
+		}
+	}
+	return out
+}
+
+func marshalLedger(t *testing.T, entries ...Entry) []byte {
+	l := Ledger{Height: 7, Name: "main", Entries: entries}
+	raw, err := l.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+// the fields of Entry, inlined into Ledger.Entries,
+// are written in their declaration order
+func TestSchemaInlinedStruct(t *testing.T) {
+	var names []string
+	for _, f := range roundtripSchema(t).Types["Ledger"].Fields[4].Type.Elem.Fields {
+		names = append(names, f.Name)
+	}
+	if want := []string{"Key", "Value", "Score"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Ledger.Entries fields: got %v, want %v", names, want)
+	}
+}
+
+func TestDumpInlinedStruct(t *testing.T) {
+	var buf bytes.Buffer
+	raw := marshalLedger(t, Entry{Key: "a", Value: []byte{0x01}, Score: 1.5})
+	if err := hsp.Dump(&buf, roundtripSchema(t), "Ledger", raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range [][]string{
+		{"Key", "string", `"a"`},
+		{"Value", "[]byte", "0x01"},
+		{"Score", "float64", "1.5"},
+	} {
+		found := false
+		for _, line := range strings.Split(buf.String(), "\n") {
+			if f := strings.Fields(line); len(f) == 4 && reflect.DeepEqual(f[1:], want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("no %v line in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDiffInlinedStruct(t *testing.T) {
+	a := marshalLedger(t, Entry{Key: "a", Value: []byte{0x01}, Score: 1.5})
+	b := marshalLedger(t, Entry{Key: "a", Value: []byte{0x01}, Score: 2.5}, Entry{Key: "b"})
+	got, err := hsp.Diff(roundtripSchema(t), "Ledger", a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []hsp.Difference{
+		{Path: "Ledger.Entries[0].Score", Type: "float64", A: "1.5", B: "2.5"},
+		{Path: "Ledger.Entries[1]", Type: "Entry", B: "map(3)"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestPathInlinedStruct(t *testing.T) {
+	fs := roundtripSchema(t)
+	raw := marshalLedger(t, Entry{Key: "a", Value: []byte{0x01}, Score: 1.5})
+	score, err := hsp.LocatePath(fs, "Ledger", "Entries[0].Score", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := hsp.AppendFloat64(nil, 1.5); !bytes.Equal(score, want) {
+		t.Errorf("Entries[0].Score: got %x, want %x", score, want)
+	}
+
+	raw, err = hsp.ReplacePath(fs, "Ledger", "Entries[0].Score", raw, hsp.AppendFloat64(nil, 2.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := marshalLedger(t, Entry{Key: "a", Value: []byte{0x01}, Score: 2.5}); !bytes.Equal(raw, want) {
+		t.Errorf("got %x, want %x", raw, want)
+	}
+}
@@ -0,0 +1,32 @@
+package covenant
+
+import (
+	"crypto"
+	"crypto/ed25519"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+//go:generate hsp -unmarshal -stream -equal
+
+//hsp:domain Envelope "covenantsql/envelope/v1"
+//hsp:cache SignedReceipt
+
+// Envelope is signed with an ed25519 key,
+// its signature fields are not hashed.
+type Envelope struct {
+	Sender    string            `hsp:"0"`
+	Amount    uint64            `hsp:"1"`
+	Signee    ed25519.PublicKey `hsp:",signer"`
+	Signature []byte            `hsp:",signature"`
+}
+
+// SignedReceipt is signed with any key,
+// SignHash uses its cached digest.
+type SignedReceipt struct {
+	TxID string           `hsp:"0"`
+	Key  crypto.PublicKey `hsp:",signer"`
+	Sig  []byte           `hsp:",signature"`
+
+	digest hsp.DigestCache
+}
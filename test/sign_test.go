@@ -0,0 +1,87 @@
+package covenant
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+func TestSignHash(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := Envelope{Sender: "alice", Amount: 10}
+	before, err := env.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = env.SignHash(priv); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(env.Signee, pub) || len(env.Signature) != ed25519.SignatureSize {
+		t.Fatalf("got signer %x and signature %x", env.Signee, env.Signature)
+	}
+
+	// the signature fields are not hashed
+	after, err := env.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Errorf("signing changed the encoding from %x to %x", before, after)
+	}
+	if err = env.VerifyHash(); err != nil {
+		t.Error(err)
+	}
+
+	forged := env
+	forged.Amount = 1000
+	if err = forged.VerifyHash(); err != hsp.ErrInvalidSignature {
+		t.Errorf("forged: got %v, want ErrInvalidSignature", err)
+	}
+
+	// an ecdsa key is not an ed25519.PublicKey
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = env.SignHash(ecKey); err == nil {
+		t.Error("signed an Envelope with an ecdsa key")
+	}
+}
+
+func TestSignHashAnyKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := SignedReceipt{TxID: "tx1"}
+	if err = r.SignHash(ecKey); err != nil {
+		t.Fatal(err)
+	}
+	if err = r.VerifyHash(); err != nil {
+		t.Error(err)
+	}
+
+	// the cached digest must be invalidated
+	r.TxID = "tx2"
+	if err = r.VerifyHash(); err != nil {
+		t.Errorf("verified with the cached digest: %v", err)
+	}
+	r.InvalidateHash()
+	if err = r.VerifyHash(); err != hsp.ErrInvalidSignature {
+		t.Errorf("got %v, want ErrInvalidSignature", err)
+	}
+
+	var unsigned SignedReceipt
+	if err = unsigned.VerifyHash(); !errors.Is(err, hsp.ErrUnknownKey) {
+		t.Errorf("unsigned: got %v, want ErrUnknownKey", err)
+	}
+}
@@ -0,0 +1,22 @@
+package covenant
+
+import (
+	"time"
+
+	"github.com/CovenantSQL/CovenantSQL/crypto/hash"
+	"github.com/CovenantSQL/CovenantSQL/proto"
+)
+
+//go:generate hsp -stream -equal
+
+type Receipt struct {
+	Tx        Tx                      `hsp:"0"`
+	BlockHash hash.Hash               `hsp:"1"`
+	Signees   map[proto.NodeID]uint64 `hsp:"2"`
+	Timestamp time.Time               `hsp:"3"`
+	Memo      interface{}             `hsp:"4"`
+	Logs      []string                `hsp:"5"`
+	Result    *Entry                  `hsp:"6"`
+	Labels    map[string]float64      `hsp:"7"`
+	Expires   *time.Time              `hsp:"8"`
+}
@@ -0,0 +1,324 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/CovenantSQL/CovenantSQL/proto"
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// MarshalHash marshals for hash
+func (z *Receipt) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 9
+	o = append(o, 0x89)
+	if oTemp, err := z.Tx.MarshalHash(); err != nil {
+		return nil, err
+	} else {
+		o = hsp.AppendBytes(o, oTemp)
+	}
+	if oTemp, err := z.BlockHash.MarshalHash(); err != nil {
+		return nil, err
+	} else {
+		o = hsp.AppendBytes(o, oTemp)
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Signees)))
+	za0001Slice := make([]struct {
+		key proto.NodeID
+		enc []byte
+	}, 0, len(z.Signees))
+	for za0001 := range z.Signees {
+		var enc []byte
+		if enc, err = za0001.MarshalHash(); err != nil {
+			return
+		}
+		za0001Slice = append(za0001Slice, struct {
+			key proto.NodeID
+			enc []byte
+		}{za0001, enc})
+	}
+	sort.Slice(za0001Slice, func(i, j int) bool { return bytes.Compare(za0001Slice[i].enc, za0001Slice[j].enc) < 0 })
+	for _, za0001Entry := range za0001Slice {
+		za0002 := z.Signees[za0001Entry.key]
+		o = hsp.AppendBytes(o, za0001Entry.enc)
+		o = hsp.AppendUint64(o, za0002)
+	}
+	o = hsp.AppendTime(o, z.Timestamp)
+	o, err = hsp.AppendIntfHash(o, z.Memo)
+	if err != nil {
+		return
+	}
+	o = hsp.AppendArrayHeader(o, uint32(len(z.Logs)))
+	for za0003 := range z.Logs {
+		o = hsp.AppendString(o, z.Logs[za0003])
+	}
+	if z.Result == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		if oTemp, err := z.Result.MarshalHash(); err != nil {
+			return nil, err
+		} else {
+			o = hsp.AppendBytes(o, oTemp)
+		}
+	}
+	o = hsp.AppendMapHeader(o, uint32(len(z.Labels)))
+	za0004Slice := make([]string, 0, len(z.Labels))
+	for i := range z.Labels {
+		za0004Slice = append(za0004Slice, i)
+	}
+	sort.Strings(za0004Slice)
+	for _, za0004 := range za0004Slice {
+		za0005 := z.Labels[za0004]
+		o = hsp.AppendString(o, za0004)
+		o = hsp.AppendFloat64(o, za0005)
+	}
+	if z.Expires == nil {
+		o = hsp.AppendNil(o)
+	} else {
+		o = hsp.AppendTime(o, *z.Expires)
+	}
+	return
+}
+
+// WriteHash writes the output of MarshalHash to w
+func (z *Receipt) WriteHash(w io.Writer) (err error) {
+	en := hsp.NewHashWriter(w)
+	// map header, size 9
+	err = en.Append(0x89)
+	if err != nil {
+		return
+	}
+	if oTemp, err := z.Tx.MarshalHash(); err != nil {
+		return err
+	} else {
+		err = en.WriteBytes(oTemp)
+		if err != nil {
+			return err
+		}
+	}
+	if oTemp, err := z.BlockHash.MarshalHash(); err != nil {
+		return err
+	} else {
+		err = en.WriteBytes(oTemp)
+		if err != nil {
+			return err
+		}
+	}
+	err = en.WriteMapHeader(uint32(len(z.Signees)))
+	if err != nil {
+		return
+	}
+	za0001Slice := make([]struct {
+		key proto.NodeID
+		enc []byte
+	}, 0, len(z.Signees))
+	for za0001 := range z.Signees {
+		var enc []byte
+		if enc, err = za0001.MarshalHash(); err != nil {
+			return
+		}
+		za0001Slice = append(za0001Slice, struct {
+			key proto.NodeID
+			enc []byte
+		}{za0001, enc})
+	}
+	sort.Slice(za0001Slice, func(i, j int) bool { return bytes.Compare(za0001Slice[i].enc, za0001Slice[j].enc) < 0 })
+	for _, za0001Entry := range za0001Slice {
+		za0002 := z.Signees[za0001Entry.key]
+		err = en.WriteBytes(za0001Entry.enc)
+		if err != nil {
+			return
+		}
+		err = en.WriteUint64(za0002)
+		if err != nil {
+			return
+		}
+	}
+	if (z.Timestamp).IsZero() {
+		err = en.WriteNil()
+	} else {
+		err = en.WriteTime(z.Timestamp)
+	}
+	if err != nil {
+		return
+	}
+	if oTemp, err := hsp.AppendIntfHash(nil, z.Memo); err != nil {
+		return err
+	} else {
+		_, err = en.Write(oTemp)
+		if err != nil {
+			return err
+		}
+	}
+	err = en.WriteArrayHeader(uint32(len(z.Logs)))
+	if err != nil {
+		return
+	}
+	for za0003 := range z.Logs {
+		err = en.WriteString(z.Logs[za0003])
+		if err != nil {
+			return
+		}
+	}
+	if z.Result == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		if oTemp, err := z.Result.MarshalHash(); err != nil {
+			return err
+		} else {
+			err = en.WriteBytes(oTemp)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err = en.WriteMapHeader(uint32(len(z.Labels)))
+	if err != nil {
+		return
+	}
+	za0004Slice := make([]string, 0, len(z.Labels))
+	for i := range z.Labels {
+		za0004Slice = append(za0004Slice, i)
+	}
+	sort.Strings(za0004Slice)
+	for _, za0004 := range za0004Slice {
+		za0005 := z.Labels[za0004]
+		err = en.WriteString(za0004)
+		if err != nil {
+			return
+		}
+		err = en.WriteFloat64(za0005)
+		if err != nil {
+			return
+		}
+	}
+	if z.Expires == nil {
+		err = en.WriteNil()
+		if err != nil {
+			return
+		}
+	} else {
+		if (*z.Expires).IsZero() {
+			err = en.WriteNil()
+		} else {
+			err = en.WriteTime(*z.Expires)
+		}
+		if err != nil {
+			return
+		}
+	}
+	err = hsp.ReleaseHashWriter(en, w)
+	return
+}
+
+// EqualHash reports whether MarshalHash of z and other are equal
+func (z *Receipt) EqualHash(other *Receipt) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if !hsp.EqualHash(&z.Tx, &other.Tx) {
+		return false
+	}
+	if !hsp.EqualHash(&z.BlockHash, &other.BlockHash) {
+		return false
+	}
+	if len(z.Signees) != len(other.Signees) {
+		return false
+	}
+	for za0001, za0002 := range z.Signees {
+		zb0001, ok := other.Signees[za0001]
+		if !ok {
+			return false
+		}
+		if za0002 != zb0001 {
+			return false
+		}
+	}
+	if !(z.Timestamp).Equal(other.Timestamp) {
+		return false
+	}
+	if !hsp.EqualIntf(z.Memo, other.Memo) {
+		return false
+	}
+	if len(z.Logs) != len(other.Logs) {
+		return false
+	}
+	for za0003 := range z.Logs {
+		if z.Logs[za0003] != other.Logs[za0003] {
+			return false
+		}
+	}
+	if (z.Result == nil) != (other.Result == nil) {
+		return false
+	}
+	if z.Result != nil {
+		if !hsp.EqualHash(z.Result, other.Result) {
+			return false
+		}
+	}
+	if len(z.Labels) != len(other.Labels) {
+		return false
+	}
+	for za0004, za0005 := range z.Labels {
+		zb0002, ok := other.Labels[za0004]
+		if !ok {
+			return false
+		}
+		if math.Float64bits(za0005) != math.Float64bits(zb0002) {
+			return false
+		}
+	}
+	if (z.Expires == nil) != (other.Expires == nil) {
+		return false
+	}
+	if z.Expires != nil {
+		if !(*z.Expires).Equal(*other.Expires) {
+			return false
+		}
+	}
+	return true
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Receipt) Msgsize() (s int) {
+	s = 1 + 2 + hsp.BytesPrefixSize + z.Tx.Msgsize() + 2 + hsp.BytesPrefixSize + z.BlockHash.Msgsize() + 2 + hsp.MapHeaderSize
+	if z.Signees != nil {
+		for za0001, za0002 := range z.Signees {
+			_ = za0002
+			s += hsp.BytesPrefixSize + za0001.Msgsize() + hsp.Uint64Size
+		}
+	}
+	s += 2 + hsp.TimeSize + 2 + hsp.GuessSize(z.Memo) + 2 + hsp.ArrayHeaderSize
+	for za0003 := range z.Logs {
+		s += hsp.StringPrefixSize + len(z.Logs[za0003])
+	}
+	s += 2
+	if z.Result == nil {
+		s += hsp.NilSize
+	} else {
+		s += hsp.BytesPrefixSize + z.Result.Msgsize()
+	}
+	s += 2 + hsp.MapHeaderSize
+	if z.Labels != nil {
+		for za0004, za0005 := range z.Labels {
+			_ = za0005
+			s += hsp.StringPrefixSize + len(za0004) + hsp.Float64Size
+		}
+	}
+	s += 2
+	if z.Expires == nil {
+		s += hsp.NilSize
+	} else {
+		s += hsp.TimeSize
+	}
+	return
+}
@@ -0,0 +1,109 @@
+package covenant
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CovenantSQL/CovenantSQL/proto"
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+func testBlock(n int) Block {
+	blk := Block{Producer: "node", Txs: make([]Tx, n)}
+	for i := range blk.Txs {
+		blk.Txs[i] = Tx{
+			Header:    TxHeader{Nonce: uint64(i), Sender: "alice", Recipient: "bob", Amount: uint64(i * 7)},
+			Signature: make([]byte, 64),
+			Fee:       1,
+			Memo:      strings.Repeat("m", i%300),
+		}
+	}
+	blk.Last = &blk.Txs[n-1]
+	return blk
+}
+
+func TestWriteHashDigest(t *testing.T) {
+	expires := time.Unix(1600000000, 0)
+	rcpt := Receipt{
+		Tx:        Tx{Header: TxHeader{Nonce: 1, Sender: "alice"}, Fee: 2},
+		Signees:   map[proto.NodeID]uint64{"b": 2, "a": 1, "c": 3},
+		Timestamp: time.Unix(1500000000, 42),
+		Memo:      map[string]interface{}{"k": "v"},
+		Logs:      []string{"x", strings.Repeat("y", 1000)},
+		Result:    &Entry{Key: "k", Value: []byte("v"), Score: 1.5},
+		Labels:    map[string]float64{"z": 1, "a": 2},
+		Expires:   &expires,
+	}
+	blk := testBlock(2000)
+
+	for _, v := range []interface {
+		MarshalHash() ([]byte, error)
+		WriteHash(w io.Writer) error
+	}{&rcpt, &Receipt{}, &Receipt{Expires: new(time.Time)}, &blk, &Block{}} {
+		bts, err := v.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := sha256.Sum256(bts)
+
+		h := sha256.New()
+		if err = v.WriteHash(h); err != nil {
+			t.Fatal(err)
+		}
+		if got := h.Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Fatalf("%T: WriteHash digest %x, want %x", v, got, want)
+		}
+	}
+}
+
+func TestWriteHashCallerWriter(t *testing.T) {
+	tx := testBlock(1).Txs[0]
+	bts, err := tx.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a caller owned *hsp.Writer is
+	// written to but not flushed
+	h := sha256.New()
+	en := hsp.NewWriterSize(h, 32)
+	if err = tx.WriteHash(en); err != nil {
+		t.Fatal(err)
+	}
+	if err = en.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := sha256.Sum256(bts); !bytes.Equal(h.Sum(nil), want[:]) {
+		t.Fatal("WriteHash digest differs from MarshalHash")
+	}
+}
+
+func BenchmarkWriteHashLargeBlock(b *testing.B) {
+	blk := testBlock(2000)
+	h := sha256.New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Reset()
+		if err := blk.WriteHash(h); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalHashLargeBlock(b *testing.B) {
+	blk := testBlock(2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, err := blk.MarshalHash()
+		if err != nil {
+			b.Fatal(err)
+		}
+		sha256.Sum256(bts)
+	}
+}
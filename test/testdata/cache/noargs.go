@@ -0,0 +1,7 @@
+package cache
+
+//hsp:cache with:setters
+
+type Account struct {
+	Balance uint64
+}
@@ -0,0 +1,7 @@
+package cache
+
+//hsp:cache Account
+
+type Account struct {
+	Balance uint64
+}
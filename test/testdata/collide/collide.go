@@ -0,0 +1,12 @@
+package collide
+
+type Header struct {
+	Height uint64 `hsp:"0"`
+}
+
+// Block flattens Header, whose
+// tag is taken by Parent
+type Block struct {
+	Header `hsp:",inline"`
+	Parent uint64 `hsp:"0"`
+}
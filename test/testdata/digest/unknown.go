@@ -0,0 +1,7 @@
+package digest
+
+//hsp:digest blake2b Checkpiont
+
+type Checkpoint struct {
+	Height uint64
+}
@@ -0,0 +1,5 @@
+package domain
+
+//hsp:domain Nonce "nonce/v1"
+
+type Nonce uint64
@@ -0,0 +1,12 @@
+package domain
+
+//hsp:domain Tx "tx/v1"
+//hsp:domain Vote "tx/v1"
+
+type Tx struct {
+	Nonce uint64
+}
+
+type Vote struct {
+	Nonce uint64
+}
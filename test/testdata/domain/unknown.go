@@ -0,0 +1,7 @@
+package domain
+
+//hsp:domain Vote "vote/v1"
+
+type Tx struct {
+	Nonce uint64
+}
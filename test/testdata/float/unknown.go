@@ -0,0 +1,7 @@
+package float
+
+//hsp:float canonical Readng
+
+type Reading struct {
+	Celsius float64
+}
@@ -0,0 +1,5 @@
+package generic
+
+type Box[T any] struct {
+	Value T
+}
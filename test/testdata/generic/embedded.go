@@ -0,0 +1,12 @@
+package generic
+
+import hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+
+type Hashable interface {
+	hsp.HashMarshaler
+	Size() int
+}
+
+type Box[T Hashable] struct {
+	Value T
+}
@@ -0,0 +1,10 @@
+package generic
+
+type fooHashMarshaler interface {
+	Size() int
+}
+
+type Box[K, V fooHashMarshaler] struct {
+	Key   K
+	Value V
+}
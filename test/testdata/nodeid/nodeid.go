@@ -0,0 +1,14 @@
+package nodeid
+
+import (
+	"github.com/CovenantSQL/CovenantSQL/crypto/hash"
+	"github.com/CovenantSQL/CovenantSQL/proto"
+)
+
+// Route has fields of foreign types
+// with a MarshalHash method
+type Route struct {
+	Node   proto.NodeID           `hsp:"0"`
+	Peers  map[proto.NodeID]int32 `hsp:"1"`
+	Parent hash.Hash              `hsp:"2"`
+}
@@ -0,0 +1,10 @@
+package nohash
+
+import "hash"
+
+// Sum has a field of a foreign type
+// without a MarshalHash method
+type Sum struct {
+	Name string
+	H    hash.Hash
+}
@@ -0,0 +1,17 @@
+package covenant
+
+import "time"
+
+//go:generate hsp -unmarshal -stream -equal
+
+// Event keeps its times with the
+// precision of the database storing it
+type Event struct {
+	ID       uint64               `hsp:"0"`
+	Created  time.Time            `hsp:"1,time=unix"`
+	Updated  time.Time            `hsp:"2,time=unix_ms"`
+	Seen     []time.Time          `hsp:"3,time=unix_us"`
+	Deadline *time.Time           `hsp:"4,time=rfc3339"`
+	Marks    map[string]time.Time `hsp:"5,time=unix_ms,omitempty"`
+	Logged   time.Time            `hsp:"6"`
+}
@@ -0,0 +1,74 @@
+package covenant
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeEncodingPrecision(t *testing.T) {
+	at := time.Date(2018, 10, 20, 8, 30, 15, 123456789, time.FixedZone("CST", 8*3600))
+	deadline := at.Add(time.Hour)
+	e1 := Event{
+		ID:       1,
+		Created:  at,
+		Updated:  at,
+		Seen:     []time.Time{at},
+		Deadline: &deadline,
+		Marks:    map[string]time.Time{"a": at},
+		Logged:   at,
+	}
+	// as read back from a database storing microseconds
+	stored := at.Truncate(time.Microsecond).UTC()
+	e2 := e1
+	e2.Created, e2.Updated, e2.Seen = stored, stored, []time.Time{stored}
+	e2.Marks = map[string]time.Time{"a": stored}
+	bts1, err := e1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bts2, err := e2.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bts1, bts2) {
+		t.Fatalf("truncated times changed the hash:\n%x\n%x", bts1, bts2)
+	}
+	if !e1.EqualHash(&e2) {
+		t.Fatal("EqualHash should compare times with the precision encoded")
+	}
+
+	e2.Updated = stored.Add(time.Millisecond)
+	if bts2, err = e2.MarshalHash(); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(bts1, bts2) {
+		t.Fatal("a time changed by a millisecond should change the hash")
+	}
+}
+
+func TestTimeEncodingRoundTrip(t *testing.T) {
+	at := time.Date(1969, 7, 20, 20, 17, 40, 987654321, time.UTC)
+	e1 := Event{ID: 2, Created: at, Seen: []time.Time{{}, at}, Deadline: &at, Logged: at}
+	bts, err := e1.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e2 Event
+	if _, err = e2.UnmarshalHash(bts); err != nil {
+		t.Fatal(err)
+	}
+	if !e1.EqualHash(&e2) {
+		t.Fatalf("decoded %+v, want %+v", e2, e1)
+	}
+	// rounded down, before the epoch too
+	if want := at.Truncate(time.Second); !e2.Created.Equal(want) {
+		t.Errorf("decoded %s, want %s", e2.Created, want)
+	}
+	if !e2.Updated.IsZero() || !e2.Seen[0].IsZero() {
+		t.Error("zero times should decode as zero times")
+	}
+	if !e2.Deadline.Equal(at) {
+		t.Errorf("decoded %s, want %s", e2.Deadline, at)
+	}
+}
@@ -0,0 +1,33 @@
+package covenant
+
+import (
+	"os"
+	"time"
+
+	"github.com/CovenantSQL/CovenantSQL/crypto/hash"
+	"github.com/CovenantSQL/CovenantSQL/proto"
+)
+
+//go:generate hsp -typecheck
+
+// Lease refers to types declared in other
+// packages and in other files of this one
+type Lease struct {
+	Holder proto.NodeID                   `hsp:"0"`
+	TTL    time.Duration                  `hsp:"1"`
+	Mode   os.FileMode                    `hsp:"2"`
+	Peers  map[proto.NodeID]time.Duration `hsp:"3"`
+	Parent hash.Hash                      `hsp:"4"`
+	Tx     Tx                             `hsp:"5"`
+}
+
+// LeaseMirror is Lease written with the underlying
+// types of those without a MarshalHash method
+type LeaseMirror struct {
+	Holder proto.NodeID           `hsp:"0"`
+	TTL    int64                  `hsp:"1"`
+	Mode   uint32                 `hsp:"2"`
+	Peers  map[proto.NodeID]int64 `hsp:"3"`
+	Parent hash.Hash        `hsp:"4"`
+	Tx     Tx               `hsp:"5"`
+}
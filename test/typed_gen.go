@@ -0,0 +1,131 @@
+package covenant
+
+// Code generated by github.com/CovenantSQL/HashStablePack DO NOT EDIT.
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/CovenantSQL/CovenantSQL/proto"
+	hsp "github.com/CovenantSQL/HashStablePack/marshalhash"
+)
+
+// MarshalHash marshals for hash
+func (z *Lease) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 6
+	o = append(o, 0x86)
+	if oTemp, err := z.Holder.MarshalHash(); err != nil {
+		return nil, err
+	} else {
+		o = hsp.AppendBytes(o, oTemp)
+	}
+	o = hsp.AppendInt64(o, int64(z.TTL))
+	o = hsp.AppendUint32(o, uint32(z.Mode))
+	o = hsp.AppendMapHeader(o, uint32(len(z.Peers)))
+	za0001Slice := make([]struct {
+		key proto.NodeID
+		enc []byte
+	}, 0, len(z.Peers))
+	for za0001 := range z.Peers {
+		var enc []byte
+		if enc, err = za0001.MarshalHash(); err != nil {
+			return
+		}
+		za0001Slice = append(za0001Slice, struct {
+			key proto.NodeID
+			enc []byte
+		}{za0001, enc})
+	}
+	sort.Slice(za0001Slice, func(i, j int) bool { return bytes.Compare(za0001Slice[i].enc, za0001Slice[j].enc) < 0 })
+	for _, za0001Entry := range za0001Slice {
+		za0002 := z.Peers[za0001Entry.key]
+		o = hsp.AppendBytes(o, za0001Entry.enc)
+		o = hsp.AppendInt64(o, int64(za0002))
+	}
+	if oTemp, err := z.Parent.MarshalHash(); err != nil {
+		return nil, err
+	} else {
+		o = hsp.AppendBytes(o, oTemp)
+	}
+	if oTemp, err := z.Tx.MarshalHash(); err != nil {
+		return nil, err
+	} else {
+		o = hsp.AppendBytes(o, oTemp)
+	}
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *Lease) Msgsize() (s int) {
+	s = 1 + 2 + hsp.BytesPrefixSize + z.Holder.Msgsize() + 2 + hsp.Int64Size + 2 + hsp.Uint32Size + 2 + hsp.MapHeaderSize
+	if z.Peers != nil {
+		for za0001, za0002 := range z.Peers {
+			_ = za0002
+			s += hsp.BytesPrefixSize + za0001.Msgsize() + hsp.Int64Size
+		}
+	}
+	s += 2 + hsp.BytesPrefixSize + z.Parent.Msgsize() + 2 + hsp.BytesPrefixSize + z.Tx.Msgsize()
+	return
+}
+
+// MarshalHash marshals for hash
+func (z *LeaseMirror) MarshalHash() (o []byte, err error) {
+	var b []byte
+	o = hsp.Require(b, z.Msgsize())
+	// map header, size 6
+	o = append(o, 0x86)
+	if oTemp, err := z.Holder.MarshalHash(); err != nil {
+		return nil, err
+	} else {
+		o = hsp.AppendBytes(o, oTemp)
+	}
+	o = hsp.AppendInt64(o, z.TTL)
+	o = hsp.AppendUint32(o, z.Mode)
+	o = hsp.AppendMapHeader(o, uint32(len(z.Peers)))
+	za0001Slice := make([]struct {
+		key proto.NodeID
+		enc []byte
+	}, 0, len(z.Peers))
+	for za0001 := range z.Peers {
+		var enc []byte
+		if enc, err = za0001.MarshalHash(); err != nil {
+			return
+		}
+		za0001Slice = append(za0001Slice, struct {
+			key proto.NodeID
+			enc []byte
+		}{za0001, enc})
+	}
+	sort.Slice(za0001Slice, func(i, j int) bool { return bytes.Compare(za0001Slice[i].enc, za0001Slice[j].enc) < 0 })
+	for _, za0001Entry := range za0001Slice {
+		za0002 := z.Peers[za0001Entry.key]
+		o = hsp.AppendBytes(o, za0001Entry.enc)
+		o = hsp.AppendInt64(o, za0002)
+	}
+	if oTemp, err := z.Parent.MarshalHash(); err != nil {
+		return nil, err
+	} else {
+		o = hsp.AppendBytes(o, oTemp)
+	}
+	if oTemp, err := z.Tx.MarshalHash(); err != nil {
+		return nil, err
+	} else {
+		o = hsp.AppendBytes(o, oTemp)
+	}
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *LeaseMirror) Msgsize() (s int) {
+	s = 1 + 2 + hsp.BytesPrefixSize + z.Holder.Msgsize() + 2 + hsp.Int64Size + 2 + hsp.Uint32Size + 2 + hsp.MapHeaderSize
+	if z.Peers != nil {
+		for za0001, za0002 := range z.Peers {
+			_ = za0002
+			s += hsp.BytesPrefixSize + za0001.Msgsize() + hsp.Int64Size
+		}
+	}
+	s += 2 + hsp.BytesPrefixSize + z.Parent.Msgsize() + 2 + hsp.BytesPrefixSize + z.Tx.Msgsize()
+	return
+}
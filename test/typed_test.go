@@ -0,0 +1,73 @@
+package covenant
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CovenantSQL/CovenantSQL/crypto/hash"
+	"github.com/CovenantSQL/CovenantSQL/proto"
+
+	"github.com/CovenantSQL/HashStablePack/gen"
+	"github.com/CovenantSQL/HashStablePack/parse"
+	"github.com/CovenantSQL/HashStablePack/printer"
+)
+
+func TestTypedUnderlying(t *testing.T) {
+	tx := Tx{Header: TxHeader{Nonce: 3}, Fee: 10, Memo: "lease"}
+	l := Lease{
+		Holder: "node",
+		TTL:    time.Minute,
+		Mode:   os.FileMode(0644),
+		Peers:  map[proto.NodeID]time.Duration{"b": time.Second, "a": time.Hour},
+		Parent: hash.Hash{0x01},
+		Tx:     tx,
+	}
+	m := LeaseMirror{
+		Holder: "node",
+		TTL:    int64(time.Minute),
+		Mode:   0644,
+		Peers:  map[proto.NodeID]int64{"b": int64(time.Second), "a": int64(time.Hour)},
+		Parent: hash.Hash{0x01},
+		Tx:     tx,
+	}
+	lb, err := l.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mb, err := m.MarshalHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(lb, mb) {
+		t.Errorf("Lease and LeaseMirror hash differently:\n%x\n%x", lb, mb)
+	}
+}
+
+func TestTypedNoMarshalHash(t *testing.T) {
+	_, err := parse.TypedFile("testdata/nohash/nohash.go", false)
+	if err == nil || !strings.Contains(err.Error(), "hash.Hash has no MarshalHash method") {
+		t.Errorf("expected a missing MarshalHash error, got %v", err)
+	}
+}
+
+func TestTypedKeepsMarshalHash(t *testing.T) {
+	// the types with their own MarshalHash are
+	// generated the same with and without -typecheck
+	const file = "testdata/nodeid/nodeid.go"
+	var out [2]map[string][]byte
+	for i, parseFile := range []func(string, bool) (*parse.FileSet, error){parse.File, parse.TypedFile} {
+		fs, err := parseFile(file, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out[i], err = printer.RenderFile("nodeid_gen.go", fs, gen.Marshal|gen.Size); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(out[0]["nodeid_gen.go"], out[1]["nodeid_gen.go"]) {
+		t.Errorf("-typecheck changed the generated code:\n%s\n%s", out[0]["nodeid_gen.go"], out[1]["nodeid_gen.go"])
+	}
+}
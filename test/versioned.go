@@ -0,0 +1,13 @@
+package covenant
+
+//go:generate hsp -unmarshal -stream -equal -golden
+
+// Profile was first generated without
+// versioning, then got a version field,
+// then a new field
+type Profile struct {
+	Ver   int    `hsp:"v,version"`
+	Name  string `hsp:"0"`
+	Karma uint64 `hsp:"1"`
+	Bio   string `hsp:"2"`
+}
@@ -0,0 +1,43 @@
+package covenant
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVersionedDispatch(t *testing.T) {
+	p := Profile{Name: "alice", Karma: 3, Bio: "hi"}
+	for ver, marshal := range []func(*Profile) ([]byte, error){
+		(*Profile).MarshalHasholdver,
+		(*Profile).MarshalHash8af277,
+		(*Profile).MarshalHash123020,
+	} {
+		p.Ver = ver
+		want, err := marshal(&p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := p.MarshalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("version %d: got %x, want %x", ver, got, want)
+		}
+		var buf bytes.Buffer
+		if err = p.WriteHash(&buf); err != nil || !bytes.Equal(buf.Bytes(), want) {
+			t.Errorf("version %d: WriteHash wrote %x, %v, want %x", ver, buf.Bytes(), err, want)
+		}
+		if p.Msgsize() < len(want) {
+			t.Errorf("version %d: Msgsize is %d, less than %d", ver, p.Msgsize(), len(want))
+		}
+	}
+
+	p.Ver = 3
+	if _, err := p.MarshalHash(); err == nil {
+		t.Error("marshaled an unknown version")
+	}
+	if err := p.WriteHash(&bytes.Buffer{}); err == nil {
+		t.Error("wrote an unknown version")
+	}
+}